@@ -0,0 +1,120 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+// address is a single generated address. Every part of it is generated at once, so
+// that a city, state, and zipcode which refer to the same address by name will agree
+// with each other.
+type address struct {
+	street string
+	city   string
+	state  string
+	zip    string
+}
+
+func streetaddress(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return addressPart(rnd, "streetaddress", func(a *address) string { return a.street }, oc, opts)
+}
+
+func city(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return addressPart(rnd, "city", func(a *address) string { return a.city }, oc, opts)
+}
+
+func state(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return addressPart(rnd, "state", func(a *address) string { return a.state }, oc, opts)
+}
+
+func zipcode(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return addressPart(rnd, "zipcode", func(a *address) string { return a.zip }, oc, opts)
+}
+
+// addressPart writes out a single part of an address. If the ref option is set, the
+// part is taken from the address previously stored under that name with the as option,
+// otherwise a new address is generated.
+func addressPart(rnd *rand.Rand, token string, part func(*address) string, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc[token]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, token, len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	var a *address
+	if ref := opts["ref"]; ref != "" {
+		v, err := oc.getNamed(ref)
+		if err != nil {
+			return "", err
+		}
+		var ok bool
+		if a, ok = v.(*address); !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to an address. Please check your input string", ref))
+		}
+	} else {
+		if a, err = newAddress(rnd, opts["country"]); err != nil {
+			return "", err
+		}
+		oc.setNamed(opts["as"], a)
+	}
+
+	result := part(a)
+	// store it in the cache
+	ca := oc[token]
+	cache := ca.([]string)
+	oc[token] = append(cache, result)
+
+	return result, nil
+}
+
+func newAddress(rnd *rand.Rand, country string) (*address, error) {
+	f, ok := AddressFormats[strings.ToUpper(country)]
+	if !ok {
+		return nil, InvalidArgumentError(fmt.Sprintf("country: %s does not have a known address format", country))
+	}
+	c := f.Cities[rnd.Intn(len(f.Cities))]
+	number := strconv.Itoa(rnd.Intn(9999) + 1)
+	street := f.Streets[rnd.Intn(len(f.Streets))]
+	if f.NumberFirst {
+		street = number + " " + street
+	} else {
+		street = street + " " + number
+	}
+	return &address{
+		street: street,
+		city:   c.Name,
+		state:  c.Region,
+		zip:    fillMask(rnd, c.PostalMask),
+	}, nil
+}
+
+// fillMask replaces each # in the mask with a random digit, and each @ with a random
+// upper case letter, leaving everything else as is
+func fillMask(rnd *rand.Rand, mask string) string {
+	const digits = "0123456789"
+	const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	b := make([]rune, 0, len(mask))
+	for _, c := range mask {
+		switch c {
+		case '#':
+			c = rune(digits[rnd.Intn(len(digits))])
+		case '@':
+			c = rune(letters[rnd.Intn(len(letters))])
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
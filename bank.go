@@ -0,0 +1,156 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func iban(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	country := strings.ToUpper(opts["country"])
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["iban"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "iban", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	mask, ok := IBANFormats[country]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("country: %s does not have a known IBAN format", country))
+	}
+	bban := fillMask(rnd, mask)
+	// The check digits are chosen so that the whole IBAN, rearranged, is 1 mod 97
+	check := 98 - ibanMod97(bban+country+"00")
+	result := fmt.Sprintf("%s%02d%s", country, check, bban)
+
+	// store it in the cache
+	ca := oc["iban"]
+	cache := ca.([]string)
+	oc["iban"] = append(cache, result)
+
+	return result, nil
+}
+
+// ibanMod97 returns the remainder of dividing the number, with letters replaced by the
+// numbers 10 through 35, by 97, following ISO 7064. The number is far too large to fit
+// in an int, so the remainder is carried along one digit at a time.
+func ibanMod97(s string) int {
+	mod := 0
+	for _, c := range s {
+		if c >= 'A' && c <= 'Z' {
+			mod = (mod*100 + int(c-'A') + 10) % 97
+		} else {
+			mod = (mod*10 + int(c-'0')) % 97
+		}
+	}
+	return mod
+}
+
+// card is a single generated credit card. The network is kept alongside the number, so
+// that a cardtype which refers to the card by name will agree with it.
+type card struct {
+	number  string
+	network string
+}
+
+func creditcard(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	network := strings.ToLower(opts["network"])
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["creditcard"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "creditcard", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	if network == "any" {
+		network = CardNetworkNames[rnd.Intn(len(CardNetworkNames))]
+	}
+	n, ok := CardNetworks[network]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("network: %s is not a known card network. Use one of any, %s", network, strings.Join(CardNetworkNames, ", ")))
+	}
+	number := n.Prefixes[rnd.Intn(len(n.Prefixes))]
+	number += randomDigits(rnd, n.Length-len(number)-1)
+	number += luhnCheck(number)
+	oc.setNamed(opts["as"], &card{number: number, network: network})
+
+	// store it in the cache
+	ca := oc["creditcard"]
+	cache := ca.([]string)
+	oc["creditcard"] = append(cache, number)
+
+	return number, nil
+}
+
+func cardtype(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["cardtype"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "cardtype", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	var network string
+	if ref := opts["ref"]; ref != "" {
+		v, err := oc.getNamed(ref)
+		if err != nil {
+			return "", err
+		}
+		c, ok := v.(*card)
+		if !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to a creditcard. Please check your input string", ref))
+		}
+		network = c.network
+	} else {
+		network = CardNetworkNames[rnd.Intn(len(CardNetworkNames))]
+	}
+
+	// store it in the cache
+	ca := oc["cardtype"]
+	cache := ca.([]string)
+	oc["cardtype"] = append(cache, network)
+
+	return network, nil
+}
+
+// luhnCheck returns the check digit which makes the number pass the Luhn algorithm.
+// Starting from the check digit on the right, every second digit is doubled.
+func luhnCheck(digits string) string {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-i)%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return strconv.Itoa((10 - sum%10) % 10)
+}
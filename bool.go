@@ -0,0 +1,76 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// boolFormats are the ways a bool can be written out, as the value for false and true
+var boolFormats = map[string][2]string{
+	"word":    {"false", "true"},
+	"numeric": {"0", "1"},
+	"yesno":   {"no", "yes"},
+}
+
+func boolean(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format, ok := boolFormats[opts["format"]]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("format: %s is not one of word, numeric, or yesno. Please check your input string", opts["format"]))
+	}
+	prob, err := opts.getFloat("probability")
+	if err != nil {
+		return "", err
+	} else if prob < 0 || prob > 1 {
+		return "", InvalidArgumentError("You have specified a probability which is not a number from 0 to 1. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["bool"]
+		cache := c.([]bool)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "bool", len(cache))
+		}
+		return formatBool(cache[ord], format), nil
+	}
+
+	b := rnd.Float64() < prob
+
+	// store it in the cache
+	ca := oc["bool"]
+	cache := ca.([]bool)
+	oc["bool"] = append(cache, b)
+	oc.setNamed(opts["as"], b)
+
+	return formatBool(b, format), nil
+}
+
+func formatBool(b bool, format [2]string) string {
+	if b {
+		return format[1]
+	}
+	return format[0]
+}
+
+// maybe writes one of two values, depending on a bool stored with the as option
+func maybe(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ref := opts["ref"]
+	if ref == "" {
+		return "", InvalidArgumentError("You must provide the name of a bool to refer to with the ref option. Please check your input string")
+	}
+	v, err := oc.getNamed(ref)
+	if err != nil {
+		return "", err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to a bool. Please check your input string", ref))
+	}
+	if b {
+		return opts["then"], nil
+	}
+	return opts["else"], nil
+}
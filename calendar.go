@@ -0,0 +1,72 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+// abbreviationLength is how many runes of a calendar name to keep when the abbrev
+// option is set, turning "Monday" into "Mon"
+const abbreviationLength = 3
+
+func weekday(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return calendarName(rnd, "weekday", Weekdays, 0, oc, opts)
+}
+
+func month(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return calendarName(rnd, "month", Months, 1, oc, opts)
+}
+
+// calendarName picks from a fixed, ordered list of names such as the days of the week.
+// If the number option is set, the position of the name in the list is written out
+// instead, counting up from first.
+func calendarName(rnd *rand.Rand, token string, names []*Name, first int, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	lang := opts["language"]
+	if !KnownLanguage(lang) {
+		return "", InvalidArgumentError(fmt.Sprintf("language: %s is not a known language", lang))
+	}
+	abbrev, err := opts.getBool("abbrev")
+	if err != nil {
+		return "", err
+	}
+	number, err := opts.getBool("number")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	var n int
+	if ord >= 0 {
+		c := oc[token]
+		cache := c.([]int)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, token, len(cache))
+		}
+		// The cache holds the position in the list, so that a reference can render
+		// it with different options than the original
+		n = cache[ord]
+	} else {
+		n = rnd.Intn(len(names))
+		// store it in the cache
+		ca := oc[token]
+		cache := ca.([]int)
+		oc[token] = append(cache, n)
+	}
+
+	if number {
+		return strconv.Itoa(n + first), nil
+	}
+	result := names[n].GetSpelling(lang)
+	if r := []rune(result); abbrev && len(r) > abbreviationLength {
+		result = string(r[:abbreviationLength])
+	}
+	return applyCase(result, cCase), nil
+}
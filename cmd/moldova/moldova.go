@@ -1,12 +1,17 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"errors"
 	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
-	"math/rand"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/StabbyCutyou/moldova"
@@ -14,48 +19,342 @@ import (
 
 type config struct {
 	iterations int
-	template   string
+	templates  []string
+	labels     []string
+	format     string
+	output     string
+	table      string
+	header     []string
+	align      []string
+	defaults   string
+	eol        string
+	nullRate   float64
+	nullValue  string
+	// collect is whether a token which fails is replaced by the placeholder, rather
+	// than the line being skipped
+	collect     bool
+	placeholder string
+	seed        int64
+	seeded      bool
+}
+
+// stringList is a flag which can be provided more than once, collecting each value
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
 }
 
 func main() {
-	cfg, err := getConfig()
+	cfg, err := getConfig(os.Args[1:], os.Stdin)
 	if err != nil {
 		log.Fatal(err)
 	}
-	cs, err := moldova.BuildCallstack(cfg.template)
+	if !cfg.seeded {
+		// Let the user know how to get this exact output again
+		log.Printf("Using seed %d", cfg.seed)
+	}
+	out, err := openOutput(cfg.output)
 	if err != nil {
 		log.Fatal(err)
 	}
+	err = run(cfg, out)
+	if cfg.output != "" {
+		if cerr := out.Close(); cerr != nil {
+			log.Fatal(cerr)
+		}
+	}
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// openOutput creates or truncates the file at path for writing, or returns stdout if
+// there is no path
+func openOutput(path string) (*os.File, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(path)
+}
+
+// run renders each configured template to out, once per iteration. When there is more
+// than one template, each block of output is preceded by a line holding it's label. A
+// line which fails to render is logged and skipped, or written with the placeholder in
+// place of the failed tokens, and an error is returned once every iteration has run.
+// Output is buffered, rather than written a line at a time, as each write to stdout is a
+// syscall.
+func run(cfg *config, w io.Writer) error {
+	out := bufio.NewWriter(w)
 	didErr := false
-	result := &bytes.Buffer{}
-	// Seed the random package
-	rand.Seed(time.Now().Unix())
-	for i := 0; i < cfg.iterations; i++ {
-		err := cs.Write(result)
+	for i, tpl := range cfg.templates {
+		if len(cfg.templates) > 1 {
+			out.WriteString("==> " + cfg.label(i) + " <==" + cfg.eol)
+		}
+		cs, err := moldova.BuildCallstack(tpl)
 		if err != nil {
 			log.Print(err)
-			didErr = true
-		} else {
-			os.Stdout.Write([]byte(result.String() + "\n"))
+			out.Flush()
+			return err
+		}
+		if err := cs.LoadDefaults(strings.NewReader(cfg.defaults)); err != nil {
+			log.Print(err)
+			out.Flush()
+			return err
+		}
+		if err := cs.SetNullRate(cfg.nullRate, cfg.nullValue); err != nil {
+			log.Print(err)
+			out.Flush()
+			return err
 		}
-		result.Reset()
+		cs.SetEscaper(escapers[cfg.format])
+		cs.SetCollectErrors(cfg.collect, cfg.placeholder)
+		// Give each template it's own seed, so they don't all produce the same values,
+		// while keeping the output of a single template the same as it's always been
+		cs.Seed(cfg.seed + int64(i))
+		if cfg.format == "mdtable" {
+			writeTableHeader(out, cfg.header, cfg.align, cfg.eol)
+		}
+		// A line which fails is logged and skipped, so the rest are still written
+		cs.WriteEach(cfg.iterations, func(_ int, result *bytes.Buffer, err error) error {
+			// With a placeholder, a line with failed tokens is still written
+			_, partial := err.(moldova.WriteErrors)
+			if (err == nil || partial) && cfg.format == "mdtable" {
+				if terr := checkTableRow(result.String(), len(cfg.header)); terr != nil {
+					err, partial = terr, false
+				}
+			}
+			if err != nil {
+				log.Print(err)
+				didErr = true
+			}
+			if err == nil || partial {
+				if cfg.format == "sql" {
+					out.WriteString("INSERT INTO " + cfg.table + " VALUES (")
+					result.WriteTo(out)
+					out.WriteString(");" + cfg.eol)
+				} else if cfg.format == "mdtable" {
+					out.WriteString("| ")
+					result.WriteTo(out)
+					out.WriteString(" |" + cfg.eol)
+				} else {
+					result.WriteTo(out)
+					out.WriteString(cfg.eol)
+				}
+			}
+			return nil
+		})
 	}
 
+	if err := out.Flush(); err != nil {
+		log.Print(err)
+		return err
+	}
 	if didErr {
-		os.Exit(1)
+		return errors.New("One or more lines failed to render")
 	}
+	return nil
+}
+
+// tableAlignments are the markers in the separator row of a markdown table which align a
+// column each way
+var tableAlignments = map[string]string{
+	"":       "---",
+	"left":   ":---",
+	"center": ":---:",
+	"right":  "---:",
 }
 
-func getConfig() (*config, error) {
-	n := flag.Int("n", 1, "The number of times to generate a line of output. Cannot be set lower than 1")
-	t := flag.String("t", "", "The template to generate results from")
-	flag.Parse()
+// writeTableHeader writes the header of a markdown table, followed by the row separating
+// it from the body, which sets how each column is aligned. Each row ends with eol.
+func writeTableHeader(out *bufio.Writer, header []string, align []string, eol string) {
+	out.WriteString("| " + strings.Join(header, " | ") + " |" + eol)
+	markers := make([]string, len(header))
+	for i := range markers {
+		if i < len(align) {
+			markers[i] = tableAlignments[align[i]]
+		} else {
+			markers[i] = tableAlignments[""]
+		}
+	}
+	out.WriteString("| " + strings.Join(markers, " | ") + " |" + eol)
+}
+
+// checkTableRow returns an error if the row does not have a cell for every column of the
+// header. Cells are separated by the pipes in the template, as those in the values of
+// tokens have been escaped.
+func checkTableRow(row string, columns int) error {
+	cells := 1
+	for i := 0; i < len(row); i++ {
+		if row[i] == '\\' {
+			i++
+		} else if row[i] == '|' {
+			cells++
+		}
+	}
+	if cells != columns {
+		return fmt.Errorf("The row %q has %d cells, but the header has %d columns", row, cells, columns)
+	}
+	return nil
+}
+
+// splitList splits a comma separated flag into it's values, with the space around each
+// of them removed
+func splitList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	l := strings.Split(v, ",")
+	for i := range l {
+		l[i] = strings.TrimSpace(l[i])
+	}
+	return l
+}
+
+// label returns the label for the template at index i, or a numbered one if there were
+// not enough labels provided
+func (cfg *config) label(i int) string {
+	if i < len(cfg.labels) {
+		return cfg.labels[i]
+	}
+	return "template " + strconv.Itoa(i+1)
+}
+
+// lineEndings are what each value of the -eol flag ends a line of output with
+var lineEndings = map[string]string{
+	"lf":   "\n",
+	"crlf": "\r\n",
+	"none": "",
+}
+
+// escapers are the Escapers for each output format. The raw format writes values as
+// they are.
+var escapers = map[string]moldova.Escaper{
+	"raw":     nil,
+	"csv":     moldova.EscapeCSV,
+	"sql":     moldova.EscapeSQL,
+	"mdtable": moldova.EscapeMarkdown,
+}
+
+func getConfig(args []string, stdin io.Reader) (*config, error) {
+	fs := flag.NewFlagSet("moldova", flag.ContinueOnError)
+	n := fs.Int("n", 1, "The number of times to generate a line of output. Cannot be set lower than 1")
+	var t, l stringList
+	fs.Var(&t, "t", "The template to generate results from. Can be provided more than once, to render several templates in turn")
+	fs.Var(&l, "l", "A label to print before the output of each template, in the same order as -t. Can be provided more than once")
+	f := fs.String("f", "", "A file to read the template from, instead of using -t. Use - to read from STDIN")
+	format := fs.String("format", "raw", "The format of the output, which values are escaped for. Either raw, csv, sql, or mdtable")
+	table := fs.String("table", "", "The table to insert into, with -format sql. Each line of output becomes an INSERT INTO table VALUES (line); statement")
+	header := fs.String("header", "", "The comma separated column names of the table, with -format mdtable. Cells in the template are separated by |")
+	align := fs.String("align", "", "The comma separated alignment of each column of the table, with -format mdtable. Either left, center, right, or empty for the default")
+	d := fs.String("defaults", "", "A file of defaults for the options of tokens, used by every template. Either lines of token.option=value, or a JSON object of tokens to their options")
+	eol := fs.String("eol", "lf", "The line ending to write after each line of output. Either lf, crlf for Windows, or none")
+	nr := fs.Float64("nullrate", 0, "The rate from 0 to 1 at which the value of every token is replaced with -nullvalue, to simulate missing data")
+	nv := fs.String("nullvalue", "NULL", "The value written in place of a token by -nullrate")
+	p := fs.String("placeholder", "", "Written in place of a token which fails, so the rest of the line is still written, rather than skipping it. The errors are still printed to STDERR")
+	o := fs.String("o", "", "A file to write the output to, instead of STDOUT. It is created if it does not exist, and truncated if it does")
+	s := fs.Int64("seed", 0, "The seed for the random values, to reproduce the output of a previous run. If not provided, one is chosen and printed to STDERR")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
 	if *n <= 0 {
 		*n = 1
 	}
-	if *t == "" {
-		return nil, errors.New("You must provide a template using the -t option")
+	if len(t) > 0 && *f != "" {
+		return nil, errors.New("You cannot provide a template with both the -t and -f options")
+	} else if *f != "" {
+		tpl, err := readTemplate(*f, stdin)
+		if err != nil {
+			return nil, err
+		}
+		t = stringList{tpl}
+	}
+	if len(t) == 0 {
+		return nil, errors.New("You must provide a template using the -t or -f option")
+	}
+	for _, tpl := range t {
+		if tpl == "" {
+			return nil, errors.New("You cannot provide an empty template")
+		}
+	}
+	if len(l) > len(t) {
+		return nil, errors.New("You cannot provide more labels with -l than there are templates")
 	}
 
-	return &config{iterations: *n, template: *t}, nil
+	if _, ok := escapers[*format]; !ok {
+		return nil, errors.New("You must provide a format of either raw, csv, sql, or mdtable")
+	}
+	if *format == "sql" && *table == "" {
+		return nil, errors.New("You must provide a table with -table when using -format sql")
+	} else if *format != "sql" && *table != "" {
+		return nil, errors.New("You can only provide a table with -table when using -format sql")
+	}
+	if _, ok := lineEndings[*eol]; !ok {
+		return nil, errors.New("You must provide a line ending of either lf, crlf, or none")
+	}
+	if *nr < 0 || *nr > 1 {
+		return nil, errors.New("You must provide a null rate with -nullrate from 0 to 1")
+	}
+	h, a := splitList(*header), splitList(*align)
+	if *format == "mdtable" && len(h) == 0 {
+		return nil, errors.New("You must provide the columns of the table with -header when using -format mdtable")
+	} else if *format != "mdtable" && (len(h) > 0 || len(a) > 0) {
+		return nil, errors.New("You can only provide -header and -align when using -format mdtable")
+	}
+	if len(a) > len(h) {
+		return nil, errors.New("You cannot provide more alignments with -align than there are columns in -header")
+	}
+	for _, v := range a {
+		if _, ok := tableAlignments[v]; !ok {
+			return nil, fmt.Errorf("The alignment %q must be either left, center, right, or empty for the default", v)
+		}
+	}
+
+	var defaults string
+	if *d != "" {
+		b, err := ioutil.ReadFile(*d)
+		if err != nil {
+			return nil, err
+		}
+		defaults = string(b)
+	}
+
+	cfg := &config{iterations: *n, templates: t, labels: l, format: *format, output: *o, table: *table, header: h, align: a, defaults: defaults, eol: lineEndings[*eol], nullRate: *nr, nullValue: *nv, seed: *s}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "seed":
+			cfg.seeded = true
+		case "placeholder":
+			// The placeholder can be empty, so it's being set that matters
+			cfg.collect = true
+			cfg.placeholder = *p
+		}
+	})
+	if !cfg.seeded {
+		cfg.seed = time.Now().UnixNano()
+	}
+	return cfg, nil
+}
+
+// readTemplate reads a template from the file at path, or from stdin if the path is -.
+// Editors like to end files with a newline, which would otherwise end up in every line
+// of output, so a single trailing newline is removed.
+func readTemplate(path string, stdin io.Reader) (string, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = ioutil.ReadAll(stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return "", err
+	}
+	tpl := strings.TrimSuffix(string(b), "\n")
+	return strings.TrimSuffix(tpl, "\r"), nil
 }
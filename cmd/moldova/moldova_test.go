@@ -0,0 +1,502 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSeedIsReproducible(t *testing.T) {
+	args := []string{"-n", "20", "-seed", "42", "-t", "{int}|{float}|{country}|{unicode:length:8}|{firstname} {lastname}|{time}"}
+	cfg, err := getConfig(args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.seeded || cfg.seed != 42 {
+		t.Fatalf("Expected the provided seed of 42 to be used, got %d", cfg.seed)
+	}
+	// Each run is its own process, so that nothing left over from the first run in
+	// memory can make the second one match
+	outputs := make([][]byte, 2)
+	for i := range outputs {
+		cmd := exec.Command(os.Args[0], append([]string{"-test.run=TestHelperProcess", "--"}, args...)...)
+		cmd.Env = append(os.Environ(), "MOLDOVA_HELPER_PROCESS=1")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(out) == 0 {
+			t.Fatal("Expected the run to write some output")
+		}
+		outputs[i] = out
+	}
+	if !bytes.Equal(outputs[0], outputs[1]) {
+		t.Errorf("Expected two runs with the same seed to produce the same output, got:\n%s\nand:\n%s", outputs[0], outputs[1])
+	}
+}
+
+// TestHelperProcess isn't a real test. It runs main with the arguments after --, when
+// another test starts the test binary as a separate process to stand in for moldova.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("MOLDOVA_HELPER_PROCESS") != "1" {
+		return
+	}
+	for i, arg := range os.Args {
+		if arg == "--" {
+			os.Args = append([]string{os.Args[0]}, os.Args[i+1:]...)
+			break
+		}
+	}
+	main()
+	os.Exit(0)
+}
+
+func TestSeedIsChosenWhenMissing(t *testing.T) {
+	cfg, err := getConfig([]string{"-t", "{int}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.seeded {
+		t.Error("Expected the seed to be marked as chosen, not provided")
+	}
+	// Running again with the chosen seed should reproduce the output
+	outputs := make([]string, 2)
+	for i := range outputs {
+		out := &bytes.Buffer{}
+		if err := run(cfg, out); err != nil {
+			t.Fatal(err)
+		}
+		outputs[i] = out.String()
+	}
+	if outputs[0] != outputs[1] {
+		t.Error("Expected the chosen seed to reproduce the same output")
+	}
+}
+
+func TestMissingTemplate(t *testing.T) {
+	if _, err := getConfig([]string{"-n", "5"}, nil); err == nil {
+		t.Error("Expected an error when no template is provided")
+	}
+}
+
+func TestTemplateFromFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "moldova")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("INSERT INTO floof VALUES ({int:min:1|max:1},\n'{country}')\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	cfg, err := getConfig([]string{"-f", f.Name()}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.templates[0] != "INSERT INTO floof VALUES ({int:min:1|max:1},\n'{country}')" {
+		t.Errorf("Template was not read from the file correctly: %q", cfg.templates[0])
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "INSERT INTO floof VALUES (1,\n'") {
+		t.Errorf("Template from file did not render as expected: %q", out.String())
+	}
+}
+
+func TestTemplateFromStdin(t *testing.T) {
+	cfg, err := getConfig([]string{"-f", "-"}, strings.NewReader("{int:min:5|max:5}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "5\n" {
+		t.Errorf("Template from STDIN did not render as expected: %q", out.String())
+	}
+}
+
+func TestTemplateFlagsAreExclusive(t *testing.T) {
+	if _, err := getConfig([]string{"-t", "{int}", "-f", "-"}, strings.NewReader("{int}")); err == nil {
+		t.Error("Expected an error when providing both -t and -f")
+	}
+}
+
+func TestMissingTemplateFile(t *testing.T) {
+	if _, err := getConfig([]string{"-f", "/this/file/does/not/exist"}, nil); err == nil {
+		t.Error("Expected an error when the template file does not exist")
+	}
+}
+
+func TestMultipleTemplates(t *testing.T) {
+	args := []string{"-n", "2", "-t", "user {int:min:1|max:1}", "-t", "order {int:min:3|max:3}", "-l", "users", "-seed", "1"}
+	cfg, err := getConfig(args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	// Labels which are not provided are numbered
+	expected := "==> users <==\nuser 1\nuser 1\n==> template 2 <==\norder 3\norder 3\n"
+	if out.String() != expected {
+		t.Errorf("Multiple templates did not render as expected: %q", out.String())
+	}
+}
+
+func TestTooManyLabels(t *testing.T) {
+	if _, err := getConfig([]string{"-t", "{int}", "-l", "a", "-l", "b"}, nil); err == nil {
+		t.Error("Expected an error when providing more labels than templates")
+	}
+}
+
+func TestCSVFormat(t *testing.T) {
+	args := []string{"-format", "csv", "-t", "{int:min:5|max:5},{company:legal:Widgets, Inc},{filename:ext:a\"b|words:1|case:up},{#: not a field}{int:min:7|max:7}"}
+	cfg, err := getConfig(args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	// Every field should survive a round trip through a CSV reader
+	record, err := csv.NewReader(strings.NewReader(out.String())).Read()
+	if err != nil {
+		t.Fatalf("Output %q is not valid CSV: %s", out.String(), err)
+	}
+	if len(record) != 4 || record[0] != "5" || record[3] != "7" {
+		t.Errorf("CSV output did not keep it's fields apart: %q", out.String())
+	}
+	if !strings.HasSuffix(record[1], " Widgets, Inc") || !strings.HasSuffix(record[2], ".a\"b") {
+		t.Errorf("CSV fields were not unescaped as expected: %q", record)
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	if _, err := getConfig([]string{"-format", "xml", "-t", "{int}"}, nil); err == nil {
+		t.Error("Expected an error when providing an unknown format")
+	}
+}
+
+func TestSQLFormat(t *testing.T) {
+	args := []string{"-n", "2", "-format", "sql", "-table", "users", "-seed", "1", "-t", "{int:min:5|max:5}, '{company:legal:O'Reilly}', {#: a comment}'{int:min:7|max:7}'"}
+	cfg, err := getConfig(args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	statement := regexp.MustCompile(`^INSERT INTO users VALUES \(5, '[^']+ O''Reilly', '7'\);$`)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 statements, got %q", out.String())
+	}
+	for _, l := range lines {
+		if !statement.MatchString(l) {
+			t.Errorf("SQL output was not a valid INSERT statement: %q", l)
+		}
+	}
+}
+
+func TestSQLFormatNeedsTable(t *testing.T) {
+	if _, err := getConfig([]string{"-format", "sql", "-t", "{int}"}, nil); err == nil {
+		t.Error("Expected an error when using the sql format without a table")
+	}
+	if _, err := getConfig([]string{"-table", "users", "-t", "{int}"}, nil); err == nil {
+		t.Error("Expected an error when providing a table without the sql format")
+	}
+}
+
+func TestMarkdownTableFormat(t *testing.T) {
+	args := []string{"-n", "3", "-format", "mdtable", "-header", "ID, Name,Notes", "-align", "right,,center", "-t", "{int:min:5|max:5} | {firstname} | {repeat:count:2|sep:\\||tpl:a}"}
+	cfg, err := getConfig(args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected a header, a separator, and 3 rows, got %q", out.String())
+	}
+	if lines[0] != "| ID | Name | Notes |" {
+		t.Errorf("Unexpected header row %q", lines[0])
+	}
+	if lines[1] != "| ---: | --- | :---: |" {
+		t.Errorf("Unexpected separator row %q", lines[1])
+	}
+	row := regexp.MustCompile(`^\| 5 \| [^|]+ \| a\\\|a \|$`)
+	for _, l := range lines[2:] {
+		if !row.MatchString(l) {
+			t.Errorf("Markdown row was not escaped as expected: %q", l)
+		}
+	}
+}
+
+func TestMarkdownTableRowsMatchHeader(t *testing.T) {
+	cfg, err := getConfig([]string{"-format", "mdtable", "-header", "a,b,c", "-t", "{int} | {int}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err == nil {
+		t.Errorf("Expected an error when a row has fewer cells than the header, got %q", out.String())
+	}
+}
+
+func TestMarkdownTableOptions(t *testing.T) {
+	for _, args := range [][]string{
+		{"-format", "mdtable", "-t", "{int}"},
+		{"-header", "a", "-t", "{int}"},
+		{"-format", "csv", "-align", "left", "-t", "{int}"},
+		{"-format", "mdtable", "-header", "a", "-align", "left,right", "-t", "{int}"},
+		{"-format", "mdtable", "-header", "a", "-align", "middle", "-t", "{int}"},
+	} {
+		if _, err := getConfig(args, nil); err == nil {
+			t.Errorf("Expected an error for the arguments %q", args)
+		}
+	}
+}
+
+func TestDefaultsFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "moldova")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("int.min=7\nint.max=7\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	cfg, err := getConfig([]string{"-n", "2", "-defaults", f.Name(), "-t", "{int} {int:max:8|min:8}", "-t", "{int}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	// The defaults apply to every template
+	expected := "==> template 1 <==\n7 8\n7 8\n==> template 2 <==\n7\n7\n"
+	if out.String() != expected {
+		t.Errorf("Expected the defaults to be used, got %q", out.String())
+	}
+
+	if _, err := getConfig([]string{"-defaults", "/this/file/does/not/exist", "-t", "{int}"}, nil); err == nil {
+		t.Error("Expected an error when the defaults file does not exist")
+	}
+}
+
+func TestLineEndings(t *testing.T) {
+	for eol, expected := range map[string]string{
+		"":     "1\n2\n",
+		"lf":   "1\n2\n",
+		"crlf": "1\r\n2\r\n",
+		"none": "12",
+	} {
+		args := []string{"-n", "2", "-t", "{rownum}"}
+		if eol != "" {
+			args = append(args, "-eol", eol)
+		}
+		cfg, err := getConfig(args, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := &bytes.Buffer{}
+		if err := run(cfg, out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != expected {
+			t.Errorf("Expected -eol %s to write %q, got %q", eol, expected, out.String())
+		}
+	}
+
+	// Every line ends the same way, including labels, statements, and tables
+	for _, args := range [][]string{
+		{"-t", "{rownum}", "-t", "{rownum}"},
+		{"-format", "sql", "-table", "t", "-t", "{rownum}"},
+		{"-format", "mdtable", "-header", "n", "-t", "{rownum}"},
+	} {
+		cfg, err := getConfig(append(args, "-n", "3", "-eol", "crlf"), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := &bytes.Buffer{}
+		if err := run(cfg, out); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Count(out.String(), "\n") != strings.Count(out.String(), "\r\n") || !strings.HasSuffix(out.String(), "\r\n") {
+			t.Errorf("Expected every line of %q to end with CRLF, got %q", args, out.String())
+		}
+	}
+
+	if _, err := getConfig([]string{"-eol", "cr", "-t", "{int}"}, nil); err == nil {
+		t.Error("Expected an error when providing an unknown line ending")
+	}
+}
+
+func TestNullRate(t *testing.T) {
+	cfg, err := getConfig([]string{"-n", "3", "-nullrate", "1", "-nullvalue", "", "-t", "{int},{guid},{int:nullprob:0|min:5|max:5}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != ",,5\n,,5\n,,5\n" {
+		t.Errorf("Expected every value but the last to be null, got %q", out.String())
+	}
+	if _, err := getConfig([]string{"-nullrate", "2", "-t", "{int}"}, nil); err == nil {
+		t.Error("Expected an error for a null rate above 1")
+	}
+}
+
+func TestPlaceholder(t *testing.T) {
+	cfg, err := getConfig([]string{"-n", "2", "-placeholder", "", "-t", "{int:min:1|max:1},{int:min:9|max:0},{int:min:3|max:3}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	// The lines are still written, but the run is still a failure
+	if err := run(cfg, out); err == nil {
+		t.Error("Expected an error for the failed tokens")
+	}
+	if out.String() != "1,,3\n1,,3\n" {
+		t.Errorf("Expected the lines to be written with an empty placeholder, got %q", out.String())
+	}
+
+	// Without a placeholder, the lines are skipped
+	if cfg, err = getConfig([]string{"-n", "2", "-t", "{int:min:1|max:1},{int:min:9|max:0}"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run(cfg, out); err == nil || out.String() != "" {
+		t.Errorf("Expected the lines to be skipped, got %q and %v", out.String(), err)
+	}
+}
+
+func TestRowNumbers(t *testing.T) {
+	args := []string{"-n", "5", "-t", "{rownum}: {int:min:5|max:5}", "-t", "{rownum:start:0|pad:3}"}
+	cfg, err := getConfig(args, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	// Each template counts it's own rows
+	expected := "==> template 1 <==\n1: 5\n2: 5\n3: 5\n4: 5\n5: 5\n==> template 2 <==\n000\n001\n002\n003\n004\n"
+	if out.String() != expected {
+		t.Errorf("Rows were not numbered as expected: %q", out.String())
+	}
+}
+
+func TestOutputFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "moldova")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out.txt")
+	// Anything already in the file should be replaced
+	if err := ioutil.WriteFile(path, []byte("previous contents\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := getConfig([]string{"-n", "3", "-o", path, "-t", "row {int:min:5|max:5}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := openOutput(cfg.output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "row 5\nrow 5\nrow 5\n" {
+		t.Errorf("Output file did not hold the expected contents: %q", string(b))
+	}
+}
+
+func TestOutputDefaultsToStdout(t *testing.T) {
+	cfg, err := getConfig([]string{"-t", "{int}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := openOutput(cfg.output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != os.Stdout {
+		t.Error("Expected output to go to STDOUT when -o is not provided")
+	}
+}
+
+// countingWriter counts the number of times it is written to
+type countingWriter struct {
+	writes int
+	bytes.Buffer
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestOutputIsBuffered(t *testing.T) {
+	cfg, err := getConfig([]string{"-n", "10000", "-t", "{guid}"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &countingWriter{}
+	if err := run(cfg, out); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 10000 {
+		t.Errorf("Expected 10000 lines of output, got %d", lines)
+	}
+	// Each line is 37 bytes, so the buffer should only need to be written about 90 times
+	if out.writes >= 1000 {
+		t.Errorf("Expected far fewer writes than lines of output, got %d", out.writes)
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	cfg, err := getConfig([]string{"-n", "1000", "-t", "{int},{float},{country}"}, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		if err := run(cfg, ioutil.Discard); err != nil {
+			b.Error(err)
+		}
+	}
+}
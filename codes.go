@@ -0,0 +1,495 @@
+package moldova
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func isbn(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	version := opts["version"]
+	hyphenate, err := opts.getBool("hyphenate")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["isbn"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "isbn", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	if version != "10" && version != "13" {
+		return "", InvalidArgumentError(fmt.Sprintf("version: %s is not a known ISBN version. Use either 10 or 13", version))
+	}
+	// Both versions share the same 9 digits, made of the English language group, a
+	// publisher, and a title. Publishers with shorter numbers have room for more titles.
+	group := strconv.Itoa(rnd.Intn(2))
+	publisher := randomDigits(rnd, rnd.Intn(6)+2)
+	title := randomDigits(rnd, 8-len(publisher))
+	parts := []string{group, publisher, title}
+	if version == "13" {
+		parts = append([]string{"978"}, parts...)
+		// An ISBN-13 is an EAN-13 barcode, in the range set aside for books
+		parts = append(parts, gs1Check(strings.Join(parts, "")))
+	} else {
+		parts = append(parts, isbn10Check(strings.Join(parts, "")))
+	}
+	sep := ""
+	if hyphenate {
+		sep = "-"
+	}
+	result := strings.Join(parts, sep)
+
+	// store it in the cache
+	ca := oc["isbn"]
+	cache := ca.([]string)
+	oc["isbn"] = append(cache, result)
+
+	return result, nil
+}
+
+// randomDigits returns a string of n random digits
+func randomDigits(rnd *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('0' + rnd.Intn(10))
+	}
+	return string(b)
+}
+
+// isbn10Check returns the check digit for the first 9 digits of an ISBN-10. A check
+// digit of 10 is written as X.
+func isbn10Check(digits string) string {
+	sum := 0
+	for i, c := range digits {
+		sum += (10 - i) * int(c-'0')
+	}
+	check := (11 - sum%11) % 11
+	if check == 10 {
+		return "X"
+	}
+	return strconv.Itoa(check)
+}
+
+// gs1Check returns the check digit for the digits of a GS1 barcode, such as an EAN-13 or
+// UPC-A. Counting from the right, the digits are weighted 3, 1, 3, 1, and so on, and the
+// check digit brings the sum up to a multiple of 10.
+func gs1Check(digits string) string {
+	sum := 0
+	for i := range digits {
+		w := 1
+		if i%2 == 0 {
+			w = 3
+		}
+		sum += w * int(digits[len(digits)-1-i]-'0')
+	}
+	return strconv.Itoa((10 - sum%10) % 10)
+}
+
+// nanoidAlphabet is the default alphabet of Nano ID, which is safe to use in URLs
+const nanoidAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+func nanoid(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	size, err := opts.getInt("size")
+	if err != nil {
+		return "", err
+	} else if size <= 0 {
+		return "", InvalidArgumentError("You have specified a size which is not a number greater than zero. Please check your input string")
+	}
+	alphabet := []rune(opts["alphabet"])
+	if len(alphabet) < 2 {
+		return "", InvalidArgumentError("You have specified an alphabet with fewer than 2 characters. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["nanoid"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "nanoid", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	// Like guids, these are meant to be hard to guess, so they come from crypto/rand
+	id := make([]rune, size)
+	for i := range id {
+		j, err := cryptoIntn(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		id[i] = alphabet[j]
+	}
+	result := string(id)
+
+	// store it in the cache
+	ca := oc["nanoid"]
+	cache := ca.([]string)
+	oc["nanoid"] = append(cache, result)
+
+	return result, nil
+}
+
+// vinChars are the characters a VIN can hold. I, O, and Q are left out, as they are too
+// easily mistaken for 1 and 0.
+const vinChars = "0123456789ABCDEFGHJKLMNPRSTUVWXYZ"
+
+// vinYears are the characters which can be used for the model year, in the 10th position
+const vinYears = "ABCDEFGHJKLMNPRSTVWXY123456789"
+
+// vinWeights are the weights of each position when working out the check digit. The check
+// digit itself, in the 9th position, has a weight of 0.
+var vinWeights = []int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+func vin(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["vin"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "vin", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	b := make([]byte, 17)
+	for i := range b {
+		b[i] = vinChars[rnd.Intn(len(vinChars))]
+	}
+	b[9] = vinYears[rnd.Intn(len(vinYears))]
+	b[8] = vinCheckDigit(b)
+	result := string(b)
+
+	// store it in the cache
+	ca := oc["vin"]
+	cache := ca.([]string)
+	oc["vin"] = append(cache, result)
+
+	return result, nil
+}
+
+// vinCheckDigit works out the check digit of a VIN, following the North American standard.
+// Each character is given a value, letters in the order they appear in the alphabet, and
+// the weighted sum of them mod 11 is the check digit, with 10 written as X.
+func vinCheckDigit(v []byte) byte {
+	sum := 0
+	for i, c := range v {
+		var n int
+		switch {
+		case c >= '0' && c <= '9':
+			n = int(c - '0')
+		case c >= 'A' && c <= 'I':
+			n = int(c-'A') + 1
+		case c >= 'J' && c <= 'R':
+			n = int(c-'J') + 1
+		default:
+			n = int(c-'S') + 2
+		}
+		sum += n * vinWeights[i]
+	}
+	if sum%11 == 10 {
+		return 'X'
+	}
+	return byte('0' + sum%11)
+}
+
+func licenseplate(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	region := strings.ToUpper(opts["region"])
+	formats, ok := LicensePlateFormats[region]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("region: %s is not a supported region. Use one of %s", opts["region"], strings.Join(plateRegions(), ", ")))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["licenseplate"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "licenseplate", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	result := fillMask(rnd, formats[rnd.Intn(len(formats))])
+
+	// store it in the cache
+	ca := oc["licenseplate"]
+	cache := ca.([]string)
+	oc["licenseplate"] = append(cache, result)
+
+	return result, nil
+}
+
+// plateRegions returns the regions with license plate formats, in order
+func plateRegions() []string {
+	regions := make([]string, 0, len(LicensePlateFormats))
+	for r := range LicensePlateFormats {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+func gitsha(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	short, err := opts.getBool("short")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["gitsha"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "gitsha", len(cache))
+		}
+		return shortenSHA(cache[ord], short), nil
+	}
+
+	// A SHA-1 is 20 bytes, written as 40 hex digits
+	sum := make([]byte, 20)
+	for i := range sum {
+		sum[i] = byte(rnd.Intn(256))
+	}
+	result := hex.EncodeToString(sum)
+
+	// store it in the cache
+	ca := oc["gitsha"]
+	cache := ca.([]string)
+	oc["gitsha"] = append(cache, result)
+
+	return shortenSHA(result, short), nil
+}
+
+// shortenSHA cuts the SHA down to the 7 digits git uses to abbreviate it, if asked to
+func shortenSHA(sha string, short bool) string {
+	if short {
+		return sha[:7]
+	}
+	return sha
+}
+
+// base36Chars and base62Chars are the digits of each base, in order
+const (
+	base36Chars = "0123456789abcdefghijklmnopqrstuvwxyz"
+	base62Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+func base36(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	result, err := shortCode(oc, opts, "base36", base36Chars)
+	if err != nil {
+		return "", err
+	}
+	return applyCase(result, cCase), nil
+}
+
+func base62(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	// Case is part of what the code means in base62, so it can't be changed
+	return shortCode(oc, opts, "base62", base62Chars)
+}
+
+// shortCode writes a code of length digits from chars, or the code from the cache if the
+// ordinal option asks for one. Like guids, these are often handed out where they shouldn't
+// be guessable, so they come from crypto/rand.
+func shortCode(oc objectCache, opts cmdOptions, name string, chars string) (string, error) {
+	length, err := opts.getInt("length")
+	if err != nil {
+		return "", err
+	} else if length <= 0 {
+		return "", InvalidArgumentError("You have specified a length which is not a number greater than zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc[name]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, name, len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	code := make([]byte, length)
+	for i := range code {
+		c, err := cryptoChar(chars)
+		if err != nil {
+			return "", err
+		}
+		code[i] = c
+	}
+	result := string(code)
+
+	// store it in the cache
+	ca := oc[name]
+	cache := ca.([]string)
+	oc[name] = append(cache, result)
+
+	return result, nil
+}
+
+func imei(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	tac := opts["tac"]
+	if tac != "random" && tac != "realistic" && (len(tac) != 8 || strings.Trim(tac, "0123456789") != "") {
+		return "", InvalidArgumentError(fmt.Sprintf("tac: %s is not random, realistic, or a TAC of 8 digits. Please check your input string", tac))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["imei"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "imei", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	// The Type Allocation Code identifies the model of the device, and is followed by a
+	// 6 digit serial number and the Luhn check digit
+	switch tac {
+	case "random":
+		tac = randomDigits(rnd, 8)
+	case "realistic":
+		weights := make([]float64, len(IMEIReportingBodies))
+		for i, b := range IMEIReportingBodies {
+			weights[i] = b.Weight
+		}
+		tac = IMEIReportingBodies[weightedIndex(rnd, weights)].Value + randomDigits(rnd, 6)
+	}
+	number := tac + randomDigits(rnd, 6)
+	number += luhnCheck(number)
+
+	// store it in the cache
+	ca := oc["imei"]
+	cache := ca.([]string)
+	oc["imei"] = append(cache, number)
+
+	return number, nil
+}
+
+// jwtSignatureSizes are the number of bytes in the signature made by each algorithm a JWT
+// can be signed with. The RSA sizes are for a 2048 bit key.
+var jwtSignatureSizes = map[string]int{
+	"HS256": 32, "HS384": 48, "HS512": 64,
+	"RS256": 256, "RS384": 256, "RS512": 256,
+	"ES256": 64, "ES384": 96,
+}
+
+func jwt(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	alg := strings.ToUpper(opts["alg"])
+	size, ok := jwtSignatureSizes[alg]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("alg: %s is not a known algorithm. Use one of HS256, HS384, HS512, RS256, RS384, RS512, ES256, or ES384", opts["alg"]))
+	}
+	payload, err := opts.getInt("payload")
+	if err != nil {
+		return "", err
+	} else if payload <= 0 {
+		return "", InvalidArgumentError("You have specified a payload which is not a number greater than zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["jwt"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "jwt", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	// The header is real, so that code which looks at it to decide how to handle the
+	// token still works, but the payload and signature are only random bytes. Like guids,
+	// they come from crypto/rand, as code under test may treat them as secrets.
+	header := `{"alg":"` + alg + `","typ":"JWT"}`
+	result := strings.Join([]string{
+		base64.RawURLEncoding.EncodeToString([]byte(header)),
+		base64.RawURLEncoding.EncodeToString(randomBytes(payload)),
+		base64.RawURLEncoding.EncodeToString(randomBytes(size)),
+	}, ".")
+
+	// store it in the cache
+	ca := oc["jwt"]
+	cache := ca.([]string)
+	oc["jwt"] = append(cache, result)
+
+	return result, nil
+}
+
+func ean13(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return barcode(rnd, oc, opts, "ean13", 13)
+}
+
+func upc(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return barcode(rnd, oc, opts, "upc", 12)
+}
+
+// barcode writes a GS1 barcode of length digits, the last of which is the check digit, or
+// the barcode from the cache if the ordinal option asks for one. The company option is
+// the digits every barcode starts with.
+func barcode(rnd *rand.Rand, oc objectCache, opts cmdOptions, name string, length int) (string, error) {
+	company := opts["company"]
+	if len(company) >= length || strings.Trim(company, "0123456789") != "" {
+		return "", InvalidArgumentError(fmt.Sprintf("company: %s is not a number of fewer than %d digits", company, length))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc[name]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, name, len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	digits := company + randomDigits(rnd, length-1-len(company))
+	result := digits + gs1Check(digits)
+
+	// store it in the cache
+	ca := oc[name]
+	cache := ca.([]string)
+	oc[name] = append(cache, result)
+
+	return result, nil
+}
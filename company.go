@@ -0,0 +1,101 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func company(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["company"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "company", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	// The legal designation is either a boolean, asking for a random one or none at
+	// all, or the exact designation to use
+	legal := opts["legal"]
+	if b, err := strconv.ParseBool(legal); err == nil {
+		legal = ""
+		if b {
+			legal = CompanySuffixes[rnd.Intn(len(CompanySuffixes))]
+		}
+	}
+
+	// Names are made of one or two words
+	words := []string{CompanyWords[rnd.Intn(len(CompanyWords))]}
+	if rnd.Intn(2) == 1 {
+		words = append(words, CompanyWords[rnd.Intn(len(CompanyWords))])
+	}
+	if legal != "" {
+		words = append(words, legal)
+	}
+	result := strings.Join(words, " ")
+
+	// store it in the cache
+	ca := oc["company"]
+	cache := ca.([]string)
+	oc["company"] = append(cache, result)
+
+	return applyCase(result, cCase), nil
+}
+
+func jobtitle(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["jobtitle"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "jobtitle", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	words := make([]string, 0, 3)
+	switch level := opts["level"]; level {
+	case "any":
+		// Not every title has a level
+		if n := rnd.Intn(len(JobLevels) + 1); n < len(JobLevels) {
+			words = append(words, JobLevels[jobLevelNames[n]])
+		}
+	case "none":
+	default:
+		l, ok := JobLevels[level]
+		if !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("level: %s is not a known job level. Use one of junior, senior, lead, any, or none", level))
+		}
+		words = append(words, l)
+	}
+	words = append(words, JobAreas[rnd.Intn(len(JobAreas))], JobRoles[rnd.Intn(len(JobRoles))])
+	result := strings.Join(words, " ")
+
+	// store it in the cache
+	ca := oc["jobtitle"]
+	cache := ca.([]string)
+	oc["jobtitle"] = append(cache, result)
+
+	return applyCase(result, cCase), nil
+}
+
+// jobLevelNames are the keys of JobLevels in a fixed order, as ranging over a map would
+// not give a stable result for a given seed
+var jobLevelNames = []string{"junior", "senior", "lead"}
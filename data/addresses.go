@@ -0,0 +1,121 @@
+package data
+
+// City is a single city, along with the region (state, province, etc) it sits in and
+// the mask for the postal codes used there. In a mask, a # is replaced with a random
+// digit, a @ with a random letter, and anything else is kept as is.
+type City struct {
+	Name       string
+	Region     string
+	PostalMask string
+}
+
+// AddressFormat describes how addresses are written for a given country
+type AddressFormat struct {
+	Cities  []*City
+	Streets []string
+	// NumberFirst is true when the house number is written before the street name,
+	// such as "12 Main Street", rather than after it, like "Hauptstraße 12"
+	NumberFirst bool
+}
+
+// AddressFormats is a lookup map of ISO 3166-1 alpha-2 country codes to the way
+// addresses are written in that country. If you'd like to see a country added, please
+// open a PR.
+var AddressFormats = map[string]*AddressFormat{
+	"US": &AddressFormat{
+		Cities: []*City{
+			&City{"New York", "NY", "100##"},
+			&City{"Los Angeles", "CA", "900##"},
+			&City{"Chicago", "IL", "606##"},
+			&City{"Houston", "TX", "770##"},
+			&City{"Phoenix", "AZ", "850##"},
+			&City{"Philadelphia", "PA", "191##"},
+			&City{"San Antonio", "TX", "782##"},
+			&City{"San Diego", "CA", "921##"},
+			&City{"Dallas", "TX", "752##"},
+			&City{"Seattle", "WA", "981##"},
+			&City{"Denver", "CO", "802##"},
+			&City{"Boston", "MA", "021##"},
+			&City{"Atlanta", "GA", "303##"},
+			&City{"Miami", "FL", "331##"},
+			&City{"Portland", "OR", "972##"},
+			&City{"Nashville", "TN", "372##"},
+			&City{"Detroit", "MI", "482##"},
+			&City{"Minneapolis", "MN", "554##"},
+		},
+		Streets: []string{
+			"Main Street", "Oak Street", "Maple Avenue", "Park Avenue", "Pine Street",
+			"Cedar Lane", "Elm Street", "Washington Boulevard", "Lake Drive", "Hill Road",
+			"Sunset Boulevard", "Church Street", "River Road", "Highland Avenue", "Walnut Street",
+		},
+		NumberFirst: true,
+	},
+	"CA": &AddressFormat{
+		Cities: []*City{
+			&City{"Toronto", "ON", "M#@ #@#"},
+			&City{"Ottawa", "ON", "K#@ #@#"},
+			&City{"Montréal", "QC", "H#@ #@#"},
+			&City{"Vancouver", "BC", "V#@ #@#"},
+			&City{"Calgary", "AB", "T#@ #@#"},
+			&City{"Winnipeg", "MB", "R#@ #@#"},
+			&City{"Halifax", "NS", "B#@ #@#"},
+		},
+		Streets: []string{
+			"Yonge Street", "King Street", "Queen Street", "Bay Street", "Rue Sainte-Catherine",
+			"Granville Street", "Portage Avenue", "Maple Crescent", "Bloor Street",
+		},
+		NumberFirst: true,
+	},
+	"GB": &AddressFormat{
+		Cities: []*City{
+			&City{"London", "England", "SW# #@@"},
+			&City{"Manchester", "England", "M# #@@"},
+			&City{"Birmingham", "England", "B# #@@"},
+			&City{"Leeds", "England", "LS# #@@"},
+			&City{"Bristol", "England", "BS# #@@"},
+			&City{"Edinburgh", "Scotland", "EH# #@@"},
+			&City{"Glasgow", "Scotland", "G# #@@"},
+			&City{"Cardiff", "Wales", "CF# #@@"},
+			&City{"Belfast", "Northern Ireland", "BT# #@@"},
+		},
+		Streets: []string{
+			"High Street", "Station Road", "Church Lane", "Victoria Road", "Park Road",
+			"Queen's Road", "Mill Lane", "Green Lane", "King Street", "The Crescent",
+		},
+		NumberFirst: true,
+	},
+	"DE": &AddressFormat{
+		Cities: []*City{
+			&City{"Berlin", "Berlin", "10###"},
+			&City{"Hamburg", "Hamburg", "20###"},
+			&City{"München", "Bayern", "80###"},
+			&City{"Köln", "Nordrhein-Westfalen", "50###"},
+			&City{"Frankfurt am Main", "Hessen", "60###"},
+			&City{"Stuttgart", "Baden-Württemberg", "70###"},
+			&City{"Leipzig", "Sachsen", "04###"},
+			&City{"Dresden", "Sachsen", "01###"},
+		},
+		Streets: []string{
+			"Hauptstraße", "Schulstraße", "Gartenstraße", "Bahnhofstraße", "Dorfstraße",
+			"Bergstraße", "Lindenstraße", "Goethestraße", "Schillerplatz", "Am Markt",
+		},
+		NumberFirst: false,
+	},
+	"FR": &AddressFormat{
+		Cities: []*City{
+			&City{"Paris", "Île-de-France", "750##"},
+			&City{"Marseille", "Provence-Alpes-Côte d'Azur", "130##"},
+			&City{"Lyon", "Auvergne-Rhône-Alpes", "690##"},
+			&City{"Toulouse", "Occitanie", "310##"},
+			&City{"Nice", "Provence-Alpes-Côte d'Azur", "060##"},
+			&City{"Nantes", "Pays de la Loire", "440##"},
+			&City{"Strasbourg", "Grand Est", "670##"},
+			&City{"Bordeaux", "Nouvelle-Aquitaine", "330##"},
+		},
+		Streets: []string{
+			"rue de la Paix", "rue Victor Hugo", "avenue de la République", "boulevard Saint-Michel",
+			"rue du Moulin", "place de l'Église", "rue Pasteur", "avenue Jean Jaurès",
+		},
+		NumberFirst: true,
+	},
+}
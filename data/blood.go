@@ -0,0 +1,14 @@
+package data
+
+// BloodTypes are the ABO blood groups with their Rh factor, weighted by roughly how
+// common each is in the population of the United States. The weights add up to 100.
+var BloodTypes = []WeightedValue{
+	{"O+", 37.4},
+	{"A+", 35.7},
+	{"B+", 8.5},
+	{"O-", 6.6},
+	{"A-", 6.3},
+	{"AB+", 3.4},
+	{"B-", 1.5},
+	{"AB-", 0.6},
+}
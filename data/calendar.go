@@ -0,0 +1,32 @@
+package data
+
+// Weekdays is the list of the days of the week, starting with Sunday so that the index
+// of each day matches Golangs time.Weekday. Any language missing from a day will fall
+// back to English.
+var Weekdays = []*Name{
+	&Name{English, spellings{English: "Sunday", Spanish: "domingo", French: "dimanche", German: "Sonntag", Italian: "domenica", Portuguese: "domingo", Dutch: "zondag", Romanian: "duminică", Catalan: "diumenge", Latin: "dies Solis", Russian: "воскресенье", Ukrainian: "неділя", Polish: "niedziela", Czech: "neděle", Greek: "Κυριακή", Hungarian: "vasárnap", Finnish: "sunnuntai", Lithuanian: "sekmadienis", Swahili: "Jumapili", Japanese: "日曜日", Chinese: "星期日", Korean: "일요일", Arabic: "الأحد"}},
+	&Name{English, spellings{English: "Monday", Spanish: "lunes", French: "lundi", German: "Montag", Italian: "lunedì", Portuguese: "segunda-feira", Dutch: "maandag", Romanian: "luni", Catalan: "dilluns", Latin: "dies Lunae", Russian: "понедельник", Ukrainian: "понеділок", Polish: "poniedziałek", Czech: "pondělí", Greek: "Δευτέρα", Hungarian: "hétfő", Finnish: "maanantai", Lithuanian: "pirmadienis", Swahili: "Jumatatu", Japanese: "月曜日", Chinese: "星期一", Korean: "월요일", Arabic: "الاثنين"}},
+	&Name{English, spellings{English: "Tuesday", Spanish: "martes", French: "mardi", German: "Dienstag", Italian: "martedì", Portuguese: "terça-feira", Dutch: "dinsdag", Romanian: "marți", Catalan: "dimarts", Latin: "dies Martis", Russian: "вторник", Ukrainian: "вівторок", Polish: "wtorek", Czech: "úterý", Greek: "Τρίτη", Hungarian: "kedd", Finnish: "tiistai", Lithuanian: "antradienis", Swahili: "Jumanne", Japanese: "火曜日", Chinese: "星期二", Korean: "화요일", Arabic: "الثلاثاء"}},
+	&Name{English, spellings{English: "Wednesday", Spanish: "miércoles", French: "mercredi", German: "Mittwoch", Italian: "mercoledì", Portuguese: "quarta-feira", Dutch: "woensdag", Romanian: "miercuri", Catalan: "dimecres", Latin: "dies Mercurii", Russian: "среда", Ukrainian: "середа", Polish: "środa", Czech: "středa", Greek: "Τετάρτη", Hungarian: "szerda", Finnish: "keskiviikko", Lithuanian: "trečiadienis", Swahili: "Jumatano", Japanese: "水曜日", Chinese: "星期三", Korean: "수요일", Arabic: "الأربعاء"}},
+	&Name{English, spellings{English: "Thursday", Spanish: "jueves", French: "jeudi", German: "Donnerstag", Italian: "giovedì", Portuguese: "quinta-feira", Dutch: "donderdag", Romanian: "joi", Catalan: "dijous", Latin: "dies Iovis", Russian: "четверг", Ukrainian: "четвер", Polish: "czwartek", Czech: "čtvrtek", Greek: "Πέμπτη", Hungarian: "csütörtök", Finnish: "torstai", Lithuanian: "ketvirtadienis", Swahili: "Alhamisi", Japanese: "木曜日", Chinese: "星期四", Korean: "목요일", Arabic: "الخميس"}},
+	&Name{English, spellings{English: "Friday", Spanish: "viernes", French: "vendredi", German: "Freitag", Italian: "venerdì", Portuguese: "sexta-feira", Dutch: "vrijdag", Romanian: "vineri", Catalan: "divendres", Latin: "dies Veneris", Russian: "пятница", Ukrainian: "пʼятниця", Polish: "piątek", Czech: "pátek", Greek: "Παρασκευή", Hungarian: "péntek", Finnish: "perjantai", Lithuanian: "penktadienis", Swahili: "Ijumaa", Japanese: "金曜日", Chinese: "星期五", Korean: "금요일", Arabic: "الجمعة"}},
+	&Name{English, spellings{English: "Saturday", Spanish: "sábado", French: "samedi", German: "Samstag", Italian: "sabato", Portuguese: "sábado", Dutch: "zaterdag", Romanian: "sâmbătă", Catalan: "dissabte", Latin: "dies Saturni", Russian: "суббота", Ukrainian: "субота", Polish: "sobota", Czech: "sobota", Greek: "Σάββατο", Hungarian: "szombat", Finnish: "lauantai", Lithuanian: "šeštadienis", Swahili: "Jumamosi", Japanese: "土曜日", Chinese: "星期六", Korean: "토요일", Arabic: "السبت"}},
+}
+
+// Months is the list of the months of the year, starting with January so that the index
+// of each month is one less than Golangs time.Month. Any language missing from a month
+// will fall back to English.
+var Months = []*Name{
+	&Name{English, spellings{English: "January", Spanish: "enero", French: "janvier", German: "Januar", Italian: "gennaio", Portuguese: "janeiro", Dutch: "januari", Romanian: "ianuarie", Catalan: "gener", Latin: "Ianuarius", Russian: "январь", Ukrainian: "січень", Polish: "styczeń", Czech: "leden", Greek: "Ιανουάριος", Hungarian: "január", Finnish: "tammikuu", Lithuanian: "sausis", Swahili: "Januari", Japanese: "一月", Chinese: "一月", Korean: "1월", Arabic: "يناير"}},
+	&Name{English, spellings{English: "February", Spanish: "febrero", French: "février", German: "Februar", Italian: "febbraio", Portuguese: "fevereiro", Dutch: "februari", Romanian: "februarie", Catalan: "febrer", Latin: "Februarius", Russian: "февраль", Ukrainian: "лютий", Polish: "luty", Czech: "únor", Greek: "Φεβρουάριος", Hungarian: "február", Finnish: "helmikuu", Lithuanian: "vasaris", Swahili: "Februari", Japanese: "二月", Chinese: "二月", Korean: "2월", Arabic: "فبراير"}},
+	&Name{English, spellings{English: "March", Spanish: "marzo", French: "mars", German: "März", Italian: "marzo", Portuguese: "março", Dutch: "maart", Romanian: "martie", Catalan: "març", Latin: "Martius", Russian: "март", Ukrainian: "березень", Polish: "marzec", Czech: "březen", Greek: "Μάρτιος", Hungarian: "március", Finnish: "maaliskuu", Lithuanian: "kovas", Swahili: "Machi", Japanese: "三月", Chinese: "三月", Korean: "3월", Arabic: "مارس"}},
+	&Name{English, spellings{English: "April", Spanish: "abril", French: "avril", German: "April", Italian: "aprile", Portuguese: "abril", Dutch: "april", Romanian: "aprilie", Catalan: "abril", Latin: "Aprilis", Russian: "апрель", Ukrainian: "квітень", Polish: "kwiecień", Czech: "duben", Greek: "Απρίλιος", Hungarian: "április", Finnish: "huhtikuu", Lithuanian: "balandis", Swahili: "Aprili", Japanese: "四月", Chinese: "四月", Korean: "4월", Arabic: "أبريل"}},
+	&Name{English, spellings{English: "May", Spanish: "mayo", French: "mai", German: "Mai", Italian: "maggio", Portuguese: "maio", Dutch: "mei", Romanian: "mai", Catalan: "maig", Latin: "Maius", Russian: "май", Ukrainian: "травень", Polish: "maj", Czech: "květen", Greek: "Μάιος", Hungarian: "május", Finnish: "toukokuu", Lithuanian: "gegužė", Swahili: "Mei", Japanese: "五月", Chinese: "五月", Korean: "5월", Arabic: "مايو"}},
+	&Name{English, spellings{English: "June", Spanish: "junio", French: "juin", German: "Juni", Italian: "giugno", Portuguese: "junho", Dutch: "juni", Romanian: "iunie", Catalan: "juny", Latin: "Iunius", Russian: "июнь", Ukrainian: "червень", Polish: "czerwiec", Czech: "červen", Greek: "Ιούνιος", Hungarian: "június", Finnish: "kesäkuu", Lithuanian: "birželis", Swahili: "Juni", Japanese: "六月", Chinese: "六月", Korean: "6월", Arabic: "يونيو"}},
+	&Name{English, spellings{English: "July", Spanish: "julio", French: "juillet", German: "Juli", Italian: "luglio", Portuguese: "julho", Dutch: "juli", Romanian: "iulie", Catalan: "juliol", Latin: "Iulius", Russian: "июль", Ukrainian: "липень", Polish: "lipiec", Czech: "červenec", Greek: "Ιούλιος", Hungarian: "július", Finnish: "heinäkuu", Lithuanian: "liepa", Swahili: "Julai", Japanese: "七月", Chinese: "七月", Korean: "7월", Arabic: "يوليو"}},
+	&Name{English, spellings{English: "August", Spanish: "agosto", French: "août", German: "August", Italian: "agosto", Portuguese: "agosto", Dutch: "augustus", Romanian: "august", Catalan: "agost", Latin: "Augustus", Russian: "август", Ukrainian: "серпень", Polish: "sierpień", Czech: "srpen", Greek: "Αύγουστος", Hungarian: "augusztus", Finnish: "elokuu", Lithuanian: "rugpjūtis", Swahili: "Agosti", Japanese: "八月", Chinese: "八月", Korean: "8월", Arabic: "أغسطس"}},
+	&Name{English, spellings{English: "September", Spanish: "septiembre", French: "septembre", German: "September", Italian: "settembre", Portuguese: "setembro", Dutch: "september", Romanian: "septembrie", Catalan: "setembre", Latin: "September", Russian: "сентябрь", Ukrainian: "вересень", Polish: "wrzesień", Czech: "září", Greek: "Σεπτέμβριος", Hungarian: "szeptember", Finnish: "syyskuu", Lithuanian: "rugsėjis", Swahili: "Septemba", Japanese: "九月", Chinese: "九月", Korean: "9월", Arabic: "سبتمبر"}},
+	&Name{English, spellings{English: "October", Spanish: "octubre", French: "octobre", German: "Oktober", Italian: "ottobre", Portuguese: "outubro", Dutch: "oktober", Romanian: "octombrie", Catalan: "octubre", Latin: "October", Russian: "октябрь", Ukrainian: "жовтень", Polish: "październik", Czech: "říjen", Greek: "Οκτώβριος", Hungarian: "október", Finnish: "lokakuu", Lithuanian: "spalis", Swahili: "Oktoba", Japanese: "十月", Chinese: "十月", Korean: "10월", Arabic: "أكتوبر"}},
+	&Name{English, spellings{English: "November", Spanish: "noviembre", French: "novembre", German: "November", Italian: "novembre", Portuguese: "novembro", Dutch: "november", Romanian: "noiembrie", Catalan: "novembre", Latin: "November", Russian: "ноябрь", Ukrainian: "листопад", Polish: "listopad", Czech: "listopad", Greek: "Νοέμβριος", Hungarian: "november", Finnish: "marraskuu", Lithuanian: "lapkritis", Swahili: "Novemba", Japanese: "十一月", Chinese: "十一月", Korean: "11월", Arabic: "نوفمبر"}},
+	&Name{English, spellings{English: "December", Spanish: "diciembre", French: "décembre", German: "Dezember", Italian: "dicembre", Portuguese: "dezembro", Dutch: "december", Romanian: "decembrie", Catalan: "desembre", Latin: "December", Russian: "декабрь", Ukrainian: "грудень", Polish: "grudzień", Czech: "prosinec", Greek: "Δεκέμβριος", Hungarian: "december", Finnish: "joulukuu", Lithuanian: "gruodis", Swahili: "Desemba", Japanese: "十二月", Chinese: "十二月", Korean: "12월", Arabic: "ديسمبر"}},
+}
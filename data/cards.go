@@ -0,0 +1,21 @@
+package data
+
+// CardNetwork describes the numbers issued by a credit card network
+type CardNetwork struct {
+	// Prefixes are the digits a card number from the network can start with
+	Prefixes []string
+	// Length is how many digits the card number has, including the check digit
+	Length int
+}
+
+// CardNetworks is a lookup map of the name of a credit card network to the numbers
+// it issues
+var CardNetworks = map[string]*CardNetwork{
+	"visa":       &CardNetwork{Prefixes: []string{"4"}, Length: 16},
+	"mastercard": &CardNetwork{Prefixes: []string{"51", "52", "53", "54", "55", "2221", "2720"}, Length: 16},
+	"amex":       &CardNetwork{Prefixes: []string{"34", "37"}, Length: 15},
+	"discover":   &CardNetwork{Prefixes: []string{"6011", "65"}, Length: 16},
+}
+
+// CardNetworkNames are the names of the networks in CardNetworks, in a fixed order
+var CardNetworkNames = []string{"visa", "mastercard", "amex", "discover"}
@@ -0,0 +1,38 @@
+package data
+
+// CompanyWords are combined to make up the names of companies
+var CompanyWords = []string{
+	"Acme", "Apex", "Atlas", "Beacon", "Blue", "Bright", "Cascade", "Cedar", "Crest",
+	"Delta", "Dynamics", "Echo", "Evergreen", "Falcon", "Frontier", "Global", "Granite",
+	"Harbor", "Horizon", "Iron", "Keystone", "Lighthouse", "Lunar", "Meridian", "Monarch",
+	"Northwind", "Nova", "Oak", "Orbit", "Pacific", "Peak", "Pioneer", "Quantum", "Red",
+	"Ridge", "River", "Sierra", "Silver", "Solar", "Summit", "Systems", "Titan", "Union",
+	"United", "Vertex", "Vista", "Wave", "Willow", "Zenith",
+}
+
+// CompanySuffixes are the legal designations that can be placed at the end of a
+// company name
+var CompanySuffixes = []string{
+	"Inc", "LLC", "Ltd", "Corp", "Co", "Group", "Holdings", "GmbH", "PLC", "SA",
+}
+
+// JobLevels is a lookup map of the level option of the jobtitle token to the word
+// placed at the start of the title
+var JobLevels = map[string]string{
+	"junior": "Junior",
+	"senior": "Senior",
+	"lead":   "Lead",
+}
+
+// JobAreas are the departments or disciplines a job title can belong to
+var JobAreas = []string{
+	"Software", "Data", "Product", "Marketing", "Sales", "Customer Success", "Finance",
+	"Operations", "Security", "Infrastructure", "Research", "Legal", "Human Resources",
+	"Design", "Quality Assurance", "Support",
+}
+
+// JobRoles are the kind of work a job title describes
+var JobRoles = []string{
+	"Engineer", "Manager", "Analyst", "Designer", "Consultant", "Specialist", "Architect",
+	"Coordinator", "Administrator", "Director", "Associate", "Strategist",
+}
@@ -1,270 +1,322 @@
 package data
 
-// CountryCodes is a list of Country Codes gathered from here:
-// https://en.wikipedia.org/wiki/ISO_3166-1_alpha-2#Officially_assigned_code_elements
+// Country holds each of the ISO 3166-1 representations of a single country. Not every
+// entry has an alpha-3 or numeric code - the Exceptional reservations only define an
+// alpha-2 code, and leave the others empty.
+//
+// Population is a rough estimate in thousands of people, used to weight the selection
+// of countries by how many people live there. Entries which are not places people
+// live, or which duplicate another entry, have a Population of 0.
+//
+// Continent is the name of the continent the country is in, using the seven continent
+// model, one of the values in Continents. Entries which are not places, such as the
+// United Nations, have no Continent.
+//
+// CallingCode is the ITU-T E.164 country calling code, without the leading +, gathered
+// from here: https://en.wikipedia.org/wiki/List_of_country_calling_codes
+// Codes such as 1 and 7 are shared by several countries. Entries which are not places
+// with their own telephone network, such as the European Union, have no CallingCode.
+type Country struct {
+	Alpha2      string
+	Alpha3      string
+	Numeric     string
+	Name        string
+	Population  int
+	Continent   string
+	CallingCode string
+}
+
+// Continents are the names of the continents in the seven continent model
+var Continents = []string{"Africa", "Antarctica", "Asia", "Europe", "North America", "Oceania", "South America"}
+
+// Countries is a list of Countries gathered from here:
+// https://en.wikipedia.org/wiki/ISO_3166-1#Current_codes
 // This list is a union of the Officially assigned code elements combined with
 // Exceptional reservations list. If you see a code missing and would like it added,
 // please submit a PR with some information demonstrating the code is officially in use.
-var CountryCodes = []string{
-	"AD",
-	"AE",
-	"AF",
-	"AG",
-	"AI",
-	"AL",
-	"AM",
-	"AO",
-	"AQ",
-	"AR",
-	"AS",
-	"AT",
-	"AU",
-	"AW",
-	"AX",
-	"AZ",
-	"BA",
-	"BB",
-	"BD",
-	"BE",
-	"BF",
-	"BG",
-	"BH",
-	"BI",
-	"BJ",
-	"BL",
-	"BM",
-	"BN",
-	"BO",
-	"BQ",
-	"BR",
-	"BS",
-	"BT",
-	"BV",
-	"BW",
-	"BY",
-	"BZ",
-	"CA",
-	"CC",
-	"CD",
-	"CF",
-	"CG",
-	"CH",
-	"CI",
-	"CK",
-	"CL",
-	"CM",
-	"CN",
-	"CO",
-	"CR",
-	"CU",
-	"CV",
-	"CW",
-	"CX",
-	"CY",
-	"CZ",
-	"DE",
-	"DJ",
-	"DK",
-	"DM",
-	"DO",
-	"DZ",
-	"EC",
-	"EE",
-	"EG",
-	"EH",
-	"ER",
-	"ES",
-	"ET",
-	"FI",
-	"FJ",
-	"FK",
-	"FM",
-	"FO",
-	"FR",
-	"GA",
-	"GB",
-	"GD",
-	"GE",
-	"GF",
-	"GG",
-	"GH",
-	"GI",
-	"GL",
-	"GM",
-	"GN",
-	"GP",
-	"GQ",
-	"GR",
-	"GS",
-	"GT",
-	"GU",
-	"GW",
-	"GY",
-	"HK",
-	"HM",
-	"HN",
-	"HR",
-	"HT",
-	"HU",
-	"ID",
-	"IE",
-	"IL",
-	"IM",
-	"IN",
-	"IO",
-	"IQ",
-	"IR",
-	"IS",
-	"IT",
-	"JE",
-	"JM",
-	"JO",
-	"JP",
-	"KE",
-	"KG",
-	"KH",
-	"KI",
-	"KM",
-	"KN",
-	"KP",
-	"KR",
-	"KW",
-	"KY",
-	"KZ",
-	"LA",
-	"LB",
-	"LC",
-	"LI",
-	"LK",
-	"LR",
-	"LS",
-	"LT",
-	"LU",
-	"LV",
-	"LY",
-	"MA",
-	"MC",
-	"MD",
-	"ME",
-	"MF",
-	"MG",
-	"MH",
-	"MK",
-	"ML",
-	"MM",
-	"MN",
-	"MO",
-	"MP",
-	"MQ",
-	"MR",
-	"MS",
-	"MT",
-	"MU",
-	"MV",
-	"MW",
-	"MX",
-	"MY",
-	"MZ",
-	"NA",
-	"NC",
-	"NE",
-	"NF",
-	"NG",
-	"NI",
-	"NL",
-	"NO",
-	"NP",
-	"NR",
-	"NU",
-	"NZ",
-	"OM",
-	"PA",
-	"PE",
-	"PF",
-	"PG",
-	"PH",
-	"PK",
-	"PL",
-	"PM",
-	"PN",
-	"PR",
-	"PS",
-	"PT",
-	"PW",
-	"PY",
-	"QA",
-	"RE",
-	"RO",
-	"RS",
-	"RU",
-	"RW",
-	"SA",
-	"SB",
-	"SC",
-	"SD",
-	"SE",
-	"SG",
-	"SH",
-	"SI",
-	"SJ",
-	"SK",
-	"SL",
-	"SM",
-	"SN",
-	"SO",
-	"SR",
-	"SS",
-	"ST",
-	"SV",
-	"SX",
-	"SY",
-	"SZ",
-	"TC",
-	"TD",
-	"TF",
-	"TG",
-	"TH",
-	"TJ",
-	"TK",
-	"TL",
-	"TM",
-	"TN",
-	"TO",
-	"TR",
-	"TT",
-	"TV",
-	"TW",
-	"TZ",
-	"UA",
-	"UG",
-	"UM",
-	"US",
-	"UY",
-	"UZ",
-	"VA",
-	"VC",
-	"VE",
-	"VG",
-	"VI",
-	"VN",
-	"VU",
-	"WF",
-	"WS",
-	"YE",
-	"YT",
-	"ZA",
-	"ZM",
-	"ZW",
-	"AC",
-	"CP",
-	"DG",
-	"EA",
-	"EU",
-	"EZ",
-	"FX",
-	"IC",
-	"SU",
-	"TA",
-	"UK",
-	"UN",
+var Countries = []*Country{
+	&Country{"AD", "AND", "020", "Andorra", 77, "Europe", "376"},
+	&Country{"AE", "ARE", "784", "United Arab Emirates", 9890, "Asia", "971"},
+	&Country{"AF", "AFG", "004", "Afghanistan", 38928, "Asia", "93"},
+	&Country{"AG", "ATG", "028", "Antigua and Barbuda", 98, "North America", "1"},
+	&Country{"AI", "AIA", "660", "Anguilla", 15, "North America", "1"},
+	&Country{"AL", "ALB", "008", "Albania", 2878, "Europe", "355"},
+	&Country{"AM", "ARM", "051", "Armenia", 2963, "Asia", "374"},
+	&Country{"AO", "AGO", "024", "Angola", 32866, "Africa", "244"},
+	&Country{"AQ", "ATA", "010", "Antarctica", 0, "Antarctica", "672"},
+	&Country{"AR", "ARG", "032", "Argentina", 45196, "South America", "54"},
+	&Country{"AS", "ASM", "016", "American Samoa", 55, "Oceania", "1"},
+	&Country{"AT", "AUT", "040", "Austria", 9006, "Europe", "43"},
+	&Country{"AU", "AUS", "036", "Australia", 25500, "Oceania", "61"},
+	&Country{"AW", "ABW", "533", "Aruba", 107, "North America", "297"},
+	&Country{"AX", "ALA", "248", "Åland Islands", 30, "Europe", "358"},
+	&Country{"AZ", "AZE", "031", "Azerbaijan", 10139, "Asia", "994"},
+	&Country{"BA", "BIH", "070", "Bosnia and Herzegovina", 3281, "Europe", "387"},
+	&Country{"BB", "BRB", "052", "Barbados", 287, "North America", "1"},
+	&Country{"BD", "BGD", "050", "Bangladesh", 164689, "Asia", "880"},
+	&Country{"BE", "BEL", "056", "Belgium", 11590, "Europe", "32"},
+	&Country{"BF", "BFA", "854", "Burkina Faso", 20903, "Africa", "226"},
+	&Country{"BG", "BGR", "100", "Bulgaria", 6948, "Europe", "359"},
+	&Country{"BH", "BHR", "048", "Bahrain", 1702, "Asia", "973"},
+	&Country{"BI", "BDI", "108", "Burundi", 11891, "Africa", "257"},
+	&Country{"BJ", "BEN", "204", "Benin", 12123, "Africa", "229"},
+	&Country{"BL", "BLM", "652", "Saint Barthélemy", 10, "North America", "590"},
+	&Country{"BM", "BMU", "060", "Bermuda", 62, "North America", "1"},
+	&Country{"BN", "BRN", "096", "Brunei Darussalam", 437, "Asia", "673"},
+	&Country{"BO", "BOL", "068", "Bolivia", 11673, "South America", "591"},
+	&Country{"BQ", "BES", "535", "Bonaire, Sint Eustatius and Saba", 26, "North America", "599"},
+	&Country{"BR", "BRA", "076", "Brazil", 212559, "South America", "55"},
+	&Country{"BS", "BHS", "044", "Bahamas", 393, "North America", "1"},
+	&Country{"BT", "BTN", "064", "Bhutan", 772, "Asia", "975"},
+	&Country{"BV", "BVT", "074", "Bouvet Island", 0, "Antarctica", "47"},
+	&Country{"BW", "BWA", "072", "Botswana", 2352, "Africa", "267"},
+	&Country{"BY", "BLR", "112", "Belarus", 9449, "Europe", "375"},
+	&Country{"BZ", "BLZ", "084", "Belize", 398, "North America", "501"},
+	&Country{"CA", "CAN", "124", "Canada", 37742, "North America", "1"},
+	&Country{"CC", "CCK", "166", "Cocos (Keeling) Islands", 1, "Asia", "61"},
+	&Country{"CD", "COD", "180", "Congo, Democratic Republic of the", 89561, "Africa", "243"},
+	&Country{"CF", "CAF", "140", "Central African Republic", 4830, "Africa", "236"},
+	&Country{"CG", "COG", "178", "Congo", 5518, "Africa", "242"},
+	&Country{"CH", "CHE", "756", "Switzerland", 8655, "Europe", "41"},
+	&Country{"CI", "CIV", "384", "Côte d'Ivoire", 26378, "Africa", "225"},
+	&Country{"CK", "COK", "184", "Cook Islands", 18, "Oceania", "682"},
+	&Country{"CL", "CHL", "152", "Chile", 19116, "South America", "56"},
+	&Country{"CM", "CMR", "120", "Cameroon", 26546, "Africa", "237"},
+	&Country{"CN", "CHN", "156", "China", 1439324, "Asia", "86"},
+	&Country{"CO", "COL", "170", "Colombia", 50883, "South America", "57"},
+	&Country{"CR", "CRI", "188", "Costa Rica", 5094, "North America", "506"},
+	&Country{"CU", "CUB", "192", "Cuba", 11327, "North America", "53"},
+	&Country{"CV", "CPV", "132", "Cabo Verde", 556, "Africa", "238"},
+	&Country{"CW", "CUW", "531", "Curaçao", 164, "North America", "599"},
+	&Country{"CX", "CXR", "162", "Christmas Island", 2, "Asia", "61"},
+	&Country{"CY", "CYP", "196", "Cyprus", 1207, "Asia", "357"},
+	&Country{"CZ", "CZE", "203", "Czechia", 10709, "Europe", "420"},
+	&Country{"DE", "DEU", "276", "Germany", 83784, "Europe", "49"},
+	&Country{"DJ", "DJI", "262", "Djibouti", 988, "Africa", "253"},
+	&Country{"DK", "DNK", "208", "Denmark", 5792, "Europe", "45"},
+	&Country{"DM", "DMA", "212", "Dominica", 72, "North America", "1"},
+	&Country{"DO", "DOM", "214", "Dominican Republic", 10848, "North America", "1"},
+	&Country{"DZ", "DZA", "012", "Algeria", 43851, "Africa", "213"},
+	&Country{"EC", "ECU", "218", "Ecuador", 17643, "South America", "593"},
+	&Country{"EE", "EST", "233", "Estonia", 1327, "Europe", "372"},
+	&Country{"EG", "EGY", "818", "Egypt", 102334, "Africa", "20"},
+	&Country{"EH", "ESH", "732", "Western Sahara", 597, "Africa", "212"},
+	&Country{"ER", "ERI", "232", "Eritrea", 3546, "Africa", "291"},
+	&Country{"ES", "ESP", "724", "Spain", 46755, "Europe", "34"},
+	&Country{"ET", "ETH", "231", "Ethiopia", 114964, "Africa", "251"},
+	&Country{"FI", "FIN", "246", "Finland", 5541, "Europe", "358"},
+	&Country{"FJ", "FJI", "242", "Fiji", 896, "Oceania", "679"},
+	&Country{"FK", "FLK", "238", "Falkland Islands (Malvinas)", 3, "South America", "500"},
+	&Country{"FM", "FSM", "583", "Micronesia", 115, "Oceania", "691"},
+	&Country{"FO", "FRO", "234", "Faroe Islands", 49, "Europe", "298"},
+	&Country{"FR", "FRA", "250", "France", 65274, "Europe", "33"},
+	&Country{"GA", "GAB", "266", "Gabon", 2226, "Africa", "241"},
+	&Country{"GB", "GBR", "826", "United Kingdom", 67886, "Europe", "44"},
+	&Country{"GD", "GRD", "308", "Grenada", 113, "North America", "1"},
+	&Country{"GE", "GEO", "268", "Georgia", 3989, "Asia", "995"},
+	&Country{"GF", "GUF", "254", "French Guiana", 299, "South America", "594"},
+	&Country{"GG", "GGY", "831", "Guernsey", 63, "Europe", "44"},
+	&Country{"GH", "GHA", "288", "Ghana", 31073, "Africa", "233"},
+	&Country{"GI", "GIB", "292", "Gibraltar", 34, "Europe", "350"},
+	&Country{"GL", "GRL", "304", "Greenland", 57, "North America", "299"},
+	&Country{"GM", "GMB", "270", "Gambia", 2417, "Africa", "220"},
+	&Country{"GN", "GIN", "324", "Guinea", 13133, "Africa", "224"},
+	&Country{"GP", "GLP", "312", "Guadeloupe", 400, "North America", "590"},
+	&Country{"GQ", "GNQ", "226", "Equatorial Guinea", 1403, "Africa", "240"},
+	&Country{"GR", "GRC", "300", "Greece", 10423, "Europe", "30"},
+	&Country{"GS", "SGS", "239", "South Georgia and the South Sandwich Islands", 0, "Antarctica", "500"},
+	&Country{"GT", "GTM", "320", "Guatemala", 17916, "North America", "502"},
+	&Country{"GU", "GUM", "316", "Guam", 169, "Oceania", "1"},
+	&Country{"GW", "GNB", "624", "Guinea-Bissau", 1968, "Africa", "245"},
+	&Country{"GY", "GUY", "328", "Guyana", 787, "South America", "592"},
+	&Country{"HK", "HKG", "344", "Hong Kong", 7497, "Asia", "852"},
+	&Country{"HM", "HMD", "334", "Heard Island and McDonald Islands", 0, "Antarctica", "672"},
+	&Country{"HN", "HND", "340", "Honduras", 9905, "North America", "504"},
+	&Country{"HR", "HRV", "191", "Croatia", 4105, "Europe", "385"},
+	&Country{"HT", "HTI", "332", "Haiti", 11403, "North America", "509"},
+	&Country{"HU", "HUN", "348", "Hungary", 9660, "Europe", "36"},
+	&Country{"ID", "IDN", "360", "Indonesia", 273524, "Asia", "62"},
+	&Country{"IE", "IRL", "372", "Ireland", 4938, "Europe", "353"},
+	&Country{"IL", "ISR", "376", "Israel", 8656, "Asia", "972"},
+	&Country{"IM", "IMN", "833", "Isle of Man", 85, "Europe", "44"},
+	&Country{"IN", "IND", "356", "India", 1380004, "Asia", "91"},
+	&Country{"IO", "IOT", "086", "British Indian Ocean Territory", 3, "Asia", "246"},
+	&Country{"IQ", "IRQ", "368", "Iraq", 40223, "Asia", "964"},
+	&Country{"IR", "IRN", "364", "Iran", 83993, "Asia", "98"},
+	&Country{"IS", "ISL", "352", "Iceland", 341, "Europe", "354"},
+	&Country{"IT", "ITA", "380", "Italy", 60462, "Europe", "39"},
+	&Country{"JE", "JEY", "832", "Jersey", 101, "Europe", "44"},
+	&Country{"JM", "JAM", "388", "Jamaica", 2961, "North America", "1"},
+	&Country{"JO", "JOR", "400", "Jordan", 10203, "Asia", "962"},
+	&Country{"JP", "JPN", "392", "Japan", 126476, "Asia", "81"},
+	&Country{"KE", "KEN", "404", "Kenya", 53771, "Africa", "254"},
+	&Country{"KG", "KGZ", "417", "Kyrgyzstan", 6524, "Asia", "996"},
+	&Country{"KH", "KHM", "116", "Cambodia", 16719, "Asia", "855"},
+	&Country{"KI", "KIR", "296", "Kiribati", 119, "Oceania", "686"},
+	&Country{"KM", "COM", "174", "Comoros", 870, "Africa", "269"},
+	&Country{"KN", "KNA", "659", "Saint Kitts and Nevis", 53, "North America", "1"},
+	&Country{"KP", "PRK", "408", "Korea, Democratic People's Republic of", 25779, "Asia", "850"},
+	&Country{"KR", "KOR", "410", "Korea, Republic of", 51269, "Asia", "82"},
+	&Country{"KW", "KWT", "414", "Kuwait", 4271, "Asia", "965"},
+	&Country{"KY", "CYM", "136", "Cayman Islands", 66, "North America", "1"},
+	&Country{"KZ", "KAZ", "398", "Kazakhstan", 18777, "Asia", "7"},
+	&Country{"LA", "LAO", "418", "Lao People's Democratic Republic", 7276, "Asia", "856"},
+	&Country{"LB", "LBN", "422", "Lebanon", 6825, "Asia", "961"},
+	&Country{"LC", "LCA", "662", "Saint Lucia", 184, "North America", "1"},
+	&Country{"LI", "LIE", "438", "Liechtenstein", 38, "Europe", "423"},
+	&Country{"LK", "LKA", "144", "Sri Lanka", 21413, "Asia", "94"},
+	&Country{"LR", "LBR", "430", "Liberia", 5058, "Africa", "231"},
+	&Country{"LS", "LSO", "426", "Lesotho", 2142, "Africa", "266"},
+	&Country{"LT", "LTU", "440", "Lithuania", 2722, "Europe", "370"},
+	&Country{"LU", "LUX", "442", "Luxembourg", 626, "Europe", "352"},
+	&Country{"LV", "LVA", "428", "Latvia", 1886, "Europe", "371"},
+	&Country{"LY", "LBY", "434", "Libya", 6871, "Africa", "218"},
+	&Country{"MA", "MAR", "504", "Morocco", 36911, "Africa", "212"},
+	&Country{"MC", "MCO", "492", "Monaco", 39, "Europe", "377"},
+	&Country{"MD", "MDA", "498", "Moldova, Republic of", 4034, "Europe", "373"},
+	&Country{"ME", "MNE", "499", "Montenegro", 628, "Europe", "382"},
+	&Country{"MF", "MAF", "663", "Saint Martin (French part)", 39, "North America", "590"},
+	&Country{"MG", "MDG", "450", "Madagascar", 27691, "Africa", "261"},
+	&Country{"MH", "MHL", "584", "Marshall Islands", 59, "Oceania", "692"},
+	&Country{"MK", "MKD", "807", "North Macedonia", 2083, "Europe", "389"},
+	&Country{"ML", "MLI", "466", "Mali", 20251, "Africa", "223"},
+	&Country{"MM", "MMR", "104", "Myanmar", 54410, "Asia", "95"},
+	&Country{"MN", "MNG", "496", "Mongolia", 3278, "Asia", "976"},
+	&Country{"MO", "MAC", "446", "Macao", 649, "Asia", "853"},
+	&Country{"MP", "MNP", "580", "Northern Mariana Islands", 58, "Oceania", "1"},
+	&Country{"MQ", "MTQ", "474", "Martinique", 375, "North America", "596"},
+	&Country{"MR", "MRT", "478", "Mauritania", 4650, "Africa", "222"},
+	&Country{"MS", "MSR", "500", "Montserrat", 5, "North America", "1"},
+	&Country{"MT", "MLT", "470", "Malta", 442, "Europe", "356"},
+	&Country{"MU", "MUS", "480", "Mauritius", 1272, "Africa", "230"},
+	&Country{"MV", "MDV", "462", "Maldives", 541, "Asia", "960"},
+	&Country{"MW", "MWI", "454", "Malawi", 19130, "Africa", "265"},
+	&Country{"MX", "MEX", "484", "Mexico", 128933, "North America", "52"},
+	&Country{"MY", "MYS", "458", "Malaysia", 32366, "Asia", "60"},
+	&Country{"MZ", "MOZ", "508", "Mozambique", 31255, "Africa", "258"},
+	&Country{"NA", "NAM", "516", "Namibia", 2541, "Africa", "264"},
+	&Country{"NC", "NCL", "540", "New Caledonia", 285, "Oceania", "687"},
+	&Country{"NE", "NER", "562", "Niger", 24207, "Africa", "227"},
+	&Country{"NF", "NFK", "574", "Norfolk Island", 2, "Oceania", "672"},
+	&Country{"NG", "NGA", "566", "Nigeria", 206140, "Africa", "234"},
+	&Country{"NI", "NIC", "558", "Nicaragua", 6625, "North America", "505"},
+	&Country{"NL", "NLD", "528", "Netherlands", 17135, "Europe", "31"},
+	&Country{"NO", "NOR", "578", "Norway", 5421, "Europe", "47"},
+	&Country{"NP", "NPL", "524", "Nepal", 29137, "Asia", "977"},
+	&Country{"NR", "NRU", "520", "Nauru", 11, "Oceania", "674"},
+	&Country{"NU", "NIU", "570", "Niue", 2, "Oceania", "683"},
+	&Country{"NZ", "NZL", "554", "New Zealand", 4822, "Oceania", "64"},
+	&Country{"OM", "OMN", "512", "Oman", 5107, "Asia", "968"},
+	&Country{"PA", "PAN", "591", "Panama", 4315, "North America", "507"},
+	&Country{"PE", "PER", "604", "Peru", 32972, "South America", "51"},
+	&Country{"PF", "PYF", "258", "French Polynesia", 281, "Oceania", "689"},
+	&Country{"PG", "PNG", "598", "Papua New Guinea", 8947, "Oceania", "675"},
+	&Country{"PH", "PHL", "608", "Philippines", 109581, "Asia", "63"},
+	&Country{"PK", "PAK", "586", "Pakistan", 220892, "Asia", "92"},
+	&Country{"PL", "POL", "616", "Poland", 37847, "Europe", "48"},
+	&Country{"PM", "SPM", "666", "Saint Pierre and Miquelon", 6, "North America", "508"},
+	&Country{"PN", "PCN", "612", "Pitcairn", 0, "Oceania", "64"},
+	&Country{"PR", "PRI", "630", "Puerto Rico", 2861, "North America", "1"},
+	&Country{"PS", "PSE", "275", "Palestine, State of", 5101, "Asia", "970"},
+	&Country{"PT", "PRT", "620", "Portugal", 10197, "Europe", "351"},
+	&Country{"PW", "PLW", "585", "Palau", 18, "Oceania", "680"},
+	&Country{"PY", "PRY", "600", "Paraguay", 7133, "South America", "595"},
+	&Country{"QA", "QAT", "634", "Qatar", 2881, "Asia", "974"},
+	&Country{"RE", "REU", "638", "Réunion", 895, "Africa", "262"},
+	&Country{"RO", "ROU", "642", "Romania", 19238, "Europe", "40"},
+	&Country{"RS", "SRB", "688", "Serbia", 8737, "Europe", "381"},
+	&Country{"RU", "RUS", "643", "Russian Federation", 145934, "Europe", "7"},
+	&Country{"RW", "RWA", "646", "Rwanda", 12952, "Africa", "250"},
+	&Country{"SA", "SAU", "682", "Saudi Arabia", 34814, "Asia", "966"},
+	&Country{"SB", "SLB", "090", "Solomon Islands", 687, "Oceania", "677"},
+	&Country{"SC", "SYC", "690", "Seychelles", 98, "Africa", "248"},
+	&Country{"SD", "SDN", "729", "Sudan", 43849, "Africa", "249"},
+	&Country{"SE", "SWE", "752", "Sweden", 10099, "Europe", "46"},
+	&Country{"SG", "SGP", "702", "Singapore", 5850, "Asia", "65"},
+	&Country{"SH", "SHN", "654", "Saint Helena, Ascension and Tristan da Cunha", 6, "Africa", "290"},
+	&Country{"SI", "SVN", "705", "Slovenia", 2079, "Europe", "386"},
+	&Country{"SJ", "SJM", "744", "Svalbard and Jan Mayen", 3, "Europe", "47"},
+	&Country{"SK", "SVK", "703", "Slovakia", 5460, "Europe", "421"},
+	&Country{"SL", "SLE", "694", "Sierra Leone", 7977, "Africa", "232"},
+	&Country{"SM", "SMR", "674", "San Marino", 34, "Europe", "378"},
+	&Country{"SN", "SEN", "686", "Senegal", 16744, "Africa", "221"},
+	&Country{"SO", "SOM", "706", "Somalia", 15893, "Africa", "252"},
+	&Country{"SR", "SUR", "740", "Suriname", 587, "South America", "597"},
+	&Country{"SS", "SSD", "728", "South Sudan", 11194, "Africa", "211"},
+	&Country{"ST", "STP", "678", "Sao Tome and Principe", 219, "Africa", "239"},
+	&Country{"SV", "SLV", "222", "El Salvador", 6486, "North America", "503"},
+	&Country{"SX", "SXM", "534", "Sint Maarten (Dutch part)", 43, "North America", "1"},
+	&Country{"SY", "SYR", "760", "Syrian Arab Republic", 17501, "Asia", "963"},
+	&Country{"SZ", "SWZ", "748", "Eswatini", 1160, "Africa", "268"},
+	&Country{"TC", "TCA", "796", "Turks and Caicos Islands", 39, "North America", "1"},
+	&Country{"TD", "TCD", "148", "Chad", 16426, "Africa", "235"},
+	&Country{"TF", "ATF", "260", "French Southern Territories", 0, "Antarctica", "262"},
+	&Country{"TG", "TGO", "768", "Togo", 8279, "Africa", "228"},
+	&Country{"TH", "THA", "764", "Thailand", 69800, "Asia", "66"},
+	&Country{"TJ", "TJK", "762", "Tajikistan", 9538, "Asia", "992"},
+	&Country{"TK", "TKL", "772", "Tokelau", 1, "Oceania", "690"},
+	&Country{"TL", "TLS", "626", "Timor-Leste", 1318, "Asia", "670"},
+	&Country{"TM", "TKM", "795", "Turkmenistan", 6031, "Asia", "993"},
+	&Country{"TN", "TUN", "788", "Tunisia", 11819, "Africa", "216"},
+	&Country{"TO", "TON", "776", "Tonga", 106, "Oceania", "676"},
+	&Country{"TR", "TUR", "792", "Turkey", 84339, "Asia", "90"},
+	&Country{"TT", "TTO", "780", "Trinidad and Tobago", 1399, "North America", "1"},
+	&Country{"TV", "TUV", "798", "Tuvalu", 12, "Oceania", "688"},
+	&Country{"TW", "TWN", "158", "Taiwan, Province of China", 23817, "Asia", "886"},
+	&Country{"TZ", "TZA", "834", "Tanzania, United Republic of", 59734, "Africa", "255"},
+	&Country{"UA", "UKR", "804", "Ukraine", 43734, "Europe", "380"},
+	&Country{"UG", "UGA", "800", "Uganda", 45741, "Africa", "256"},
+	&Country{"UM", "UMI", "581", "United States Minor Outlying Islands", 0, "Oceania", "1"},
+	&Country{"US", "USA", "840", "United States of America", 331003, "North America", "1"},
+	&Country{"UY", "URY", "858", "Uruguay", 3474, "South America", "598"},
+	&Country{"UZ", "UZB", "860", "Uzbekistan", 33469, "Asia", "998"},
+	&Country{"VA", "VAT", "336", "Holy See", 1, "Europe", "39"},
+	&Country{"VC", "VCT", "670", "Saint Vincent and the Grenadines", 111, "North America", "1"},
+	&Country{"VE", "VEN", "862", "Venezuela", 28436, "South America", "58"},
+	&Country{"VG", "VGB", "092", "Virgin Islands (British)", 30, "North America", "1"},
+	&Country{"VI", "VIR", "850", "Virgin Islands (U.S.)", 104, "North America", "1"},
+	&Country{"VN", "VNM", "704", "Viet Nam", 97339, "Asia", "84"},
+	&Country{"VU", "VUT", "548", "Vanuatu", 307, "Oceania", "678"},
+	&Country{"WF", "WLF", "876", "Wallis and Futuna", 11, "Oceania", "681"},
+	&Country{"WS", "WSM", "882", "Samoa", 198, "Oceania", "685"},
+	&Country{"YE", "YEM", "887", "Yemen", 29826, "Asia", "967"},
+	&Country{"YT", "MYT", "175", "Mayotte", 273, "Africa", "262"},
+	&Country{"ZA", "ZAF", "710", "South Africa", 59309, "Africa", "27"},
+	&Country{"ZM", "ZMB", "894", "Zambia", 18384, "Africa", "260"},
+	&Country{"ZW", "ZWE", "716", "Zimbabwe", 14863, "Africa", "263"},
+	&Country{"AC", "", "", "Ascension Island", 1, "Africa", "247"},
+	&Country{"CP", "", "", "Clipperton Island", 0, "North America", ""},
+	&Country{"DG", "", "", "Diego Garcia", 3, "Asia", "246"},
+	&Country{"EA", "", "", "Ceuta and Melilla", 171, "Africa", "34"},
+	&Country{"EU", "", "", "European Union", 0, "Europe", ""},
+	&Country{"EZ", "", "", "Eurozone", 0, "Europe", ""},
+	&Country{"FX", "FXX", "249", "France, Metropolitan", 0, "Europe", "33"},
+	&Country{"IC", "", "", "Canary Islands", 2207, "Africa", "34"},
+	&Country{"SU", "SUN", "810", "Union of Soviet Socialist Republics", 0, "Europe", "7"},
+	&Country{"TA", "", "", "Tristan da Cunha", 0, "Africa", "290"},
+	&Country{"UK", "", "", "United Kingdom", 0, "Europe", "44"},
+	&Country{"UN", "", "", "United Nations", 0, "", ""},
+}
+
+// CountryCodes is the list of ISO 3166-1 alpha-2 codes for every entry in Countries
+var CountryCodes = alpha2Codes(Countries)
+
+// CountryPopulations is the Population of every entry in Countries, in the same order,
+// ready to be used as weights
+var CountryPopulations = populations(Countries)
+
+func populations(countries []*Country) []float64 {
+	weights := make([]float64, len(countries))
+	for i, c := range countries {
+		weights[i] = float64(c.Population)
+	}
+	return weights
+}
+
+func alpha2Codes(countries []*Country) []string {
+	codes := make([]string, len(countries))
+	for i, c := range countries {
+		codes[i] = c.Alpha2
+	}
+	return codes
 }
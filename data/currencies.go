@@ -0,0 +1,62 @@
+package data
+
+// Currency describes how an amount of money in a given ISO 4217 currency is written
+// out - the symbol to place in front of the amount, and how many digits belong after
+// the decimal point (the currencies "minor unit").
+type Currency struct {
+	Code     string
+	Symbol   string
+	Decimals int
+}
+
+// Currencies is a lookup map of ISO 4217 currency codes to their metadata, gathered
+// from here:
+// https://en.wikipedia.org/wiki/ISO_4217#Active_codes
+// If you'd like to see a currency added, please open a PR.
+var Currencies = map[string]*Currency{
+	"USD": &Currency{"USD", "$", 2},
+	"EUR": &Currency{"EUR", "€", 2},
+	"JPY": &Currency{"JPY", "¥", 0},
+	"GBP": &Currency{"GBP", "£", 2},
+	"CNY": &Currency{"CNY", "CN¥", 2},
+	"INR": &Currency{"INR", "₹", 2},
+	"KRW": &Currency{"KRW", "₩", 0},
+	"CAD": &Currency{"CAD", "CA$", 2},
+	"AUD": &Currency{"AUD", "A$", 2},
+	"CHF": &Currency{"CHF", "CHF ", 2},
+	"BRL": &Currency{"BRL", "R$", 2},
+	"MXN": &Currency{"MXN", "MX$", 2},
+	"RUB": &Currency{"RUB", "₽", 2},
+	"KWD": &Currency{"KWD", "KD ", 3},
+}
+
+// MoneyFormat describes how a locale writes an amount of money - the characters which
+// group the digits into thousands and mark the decimal point, and which side of the
+// amount the symbol goes on
+type MoneyFormat struct {
+	Group   string
+	Decimal string
+	// SymbolAfter is whether the symbol follows the amount, as in "12,50 €", rather
+	// than coming before it, as in "€12.50"
+	SymbolAfter bool
+	// Space is whether the symbol is kept apart from the amount by a space
+	Space bool
+}
+
+// MoneyFormats is a lookup map of locales, written as a language and region such as
+// "en-US", to how they write an amount of money, gathered from the Unicode CLDR:
+// http://cldr.unicode.org
+var MoneyFormats = map[string]*MoneyFormat{
+	"en-US": &MoneyFormat{",", ".", false, false},
+	"en-GB": &MoneyFormat{",", ".", false, false},
+	"ja-JP": &MoneyFormat{",", ".", false, false},
+	"de-DE": &MoneyFormat{".", ",", true, true},
+	"es-ES": &MoneyFormat{".", ",", true, true},
+	"it-IT": &MoneyFormat{".", ",", true, true},
+	"nl-NL": &MoneyFormat{".", ",", false, true},
+	"pt-BR": &MoneyFormat{".", ",", false, true},
+	"de-CH": &MoneyFormat{"’", ".", false, true},
+	// French groups with a narrow no-break space, so an amount is never split across
+	// two lines
+	"fr-FR": &MoneyFormat{"\u202f", ",", true, true},
+}
@@ -0,0 +1,16 @@
+package data
+
+// IMEIReportingBodies are the first two digits of the Type Allocation Code of an IMEI,
+// which say which body allocated it. They're weighted by roughly how many of the devices
+// in use today were allocated by each, as nearly all are from BABT or TAF.
+var IMEIReportingBodies = []WeightedValue{
+	// BABT, the British Approvals Board for Telecommunications
+	{"35", 60},
+	// TAF, the Telecommunication Terminal Testing and Authentication Forum of China
+	{"86", 30},
+	// PTCRB, in North America
+	{"01", 8},
+	// Also allocated by BABT
+	{"44", 1},
+	{"49", 1},
+}
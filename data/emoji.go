@@ -0,0 +1,29 @@
+package data
+
+// EmojiRanges is a lookup map of emoji categories to the regions in Unicode holding
+// emoji of that kind. Unlike PrintableRanges, both ends of each region are included.
+// Every codepoint in a region should be an emoji which displays on it's own, without
+// needing a variation selector.
+var EmojiRanges = map[string][][]int{
+	"faces": {
+		// Smileys, from grinning face to face with medical mask
+		{0x1f600, 0x1f637},
+		// Frowning, smiling, upside down and eye rolling faces
+		{0x1f641, 0x1f644},
+	},
+	"animals": {
+		// Rat through to panda face
+		{0x1f400, 0x1f43c},
+		// Crab, lion, scorpion, turkey, and unicorn
+		{0x1f980, 0x1f984},
+	},
+	"food": {
+		// Hot dog, taco, and burrito
+		{0x1f32d, 0x1f32f},
+		// Tomato through to baby bottle
+		{0x1f345, 0x1f37c},
+	},
+}
+
+// EmojiCategories are the names of the categories in EmojiRanges, in a fixed order
+var EmojiCategories = []string{"faces", "animals", "food"}
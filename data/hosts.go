@@ -0,0 +1,22 @@
+package data
+
+// HostRoles are the roles servers are commonly named after, such as the web01 and db02
+// of a data center
+var HostRoles = []string{
+	"web", "app", "api", "db", "cache", "queue", "worker", "mail", "proxy", "lb",
+	"search", "build", "log", "monitor", "auth", "files", "backup", "dns", "vpn", "git",
+}
+
+// VendorOUIs are some of the Organizationally Unique Identifiers assigned by the IEEE to
+// each vendor, which make up the first three octets of the MAC addresses of the network
+// interfaces they make
+var VendorOUIs = map[string][]string{
+	"apple":       {"00:03:93", "00:0a:95", "00:1b:63"},
+	"cisco":       {"00:00:0c", "00:01:42", "00:01:43"},
+	"dell":        {"00:06:5b", "00:08:74", "00:14:22"},
+	"intel":       {"00:02:b3", "00:03:47", "00:1b:21"},
+	"raspberrypi": {"b8:27:eb", "dc:a6:32", "e4:5f:01"},
+	"virtualbox":  {"08:00:27"},
+	"vmware":      {"00:05:69", "00:0c:29", "00:50:56"},
+	"xen":         {"00:16:3e"},
+}
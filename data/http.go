@@ -0,0 +1,13 @@
+package data
+
+// HTTPStatusCodes are the status codes commonly returned by web servers and APIs
+var HTTPStatusCodes = []int{
+	100, 101,
+	200, 201, 202, 204, 206,
+	301, 302, 303, 304, 307, 308,
+	400, 401, 403, 404, 405, 406, 408, 409, 410, 412, 415, 422, 429,
+	500, 501, 502, 503, 504,
+}
+
+// HTTPMethods are the request methods commonly used by APIs
+var HTTPMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
@@ -0,0 +1,21 @@
+package data
+
+// IBANFormats is a lookup map of country codes to the layout of the national account
+// number held in an IBAN for that country, after the country code and check digits. In
+// each layout, # is a digit and @ is an upper case letter.
+var IBANFormats = map[string]string{
+	"AT": "################",
+	"BE": "############",
+	"CH": "#################",
+	"DE": "##################",
+	"ES": "####################",
+	"FR": "#######################",
+	"GB": "@@@@##############",
+	"IE": "@@@@##############",
+	"IT": "@######################",
+	"NL": "@@@@##########",
+	"NO": "###########",
+	"PL": "########################",
+	"PT": "#####################",
+	"SE": "####################",
+}
@@ -0,0 +1,49 @@
+package data
+
+// LoremWords are the words of the traditional lorem ipsum placeholder text, used to
+// build text which should look like words without meaning anything
+var LoremWords = []string{
+	"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed",
+	"do", "eiusmod", "tempor", "incididunt", "ut", "labore", "et", "dolore", "magna",
+	"aliqua", "enim", "ad", "minim", "veniam", "quis", "nostrud", "exercitation", "ullamco",
+	"laboris", "nisi", "aliquip", "ex", "ea", "commodo", "consequat", "duis", "aute", "irure",
+	"in", "reprehenderit", "voluptate", "velit", "esse", "cillum", "eu", "fugiat", "nulla",
+	"pariatur", "excepteur", "sint", "occaecat", "cupidatat", "non", "proident", "sunt",
+	"culpa", "qui", "officia", "deserunt", "mollit", "anim", "id", "est", "laborum",
+}
+
+// LoremLanguages are lists of filler words for each language which lorem text can be
+// written in. Other than Latin, these are ordinary words of the language, picked for
+// their accents and scripts, so that text which isn't plain ASCII can be generated.
+var LoremLanguages = map[string][]string{
+	Latin: LoremWords,
+	French: []string{
+		"été", "forêt", "château", "élève", "fenêtre", "où", "déjà", "très", "ça", "garçon",
+		"français", "hôpital", "île", "noël", "cœur", "œuvre", "frère", "mère", "père",
+		"théâtre", "café", "crème", "bientôt", "âge", "goût", "août", "rivière", "clé",
+		"soleil", "maison", "jardin", "livre", "chemin", "lumière", "années", "première",
+	},
+	German: []string{
+		"über", "schön", "grün", "mädchen", "straße", "größe", "fuß", "käse", "müde",
+		"brötchen", "häuser", "tür", "öffnen", "früh", "glück", "bäume", "wäsche", "heiß",
+		"wald", "stadt", "fluss", "sonne", "himmel", "garten", "brücke", "küche", "zurück",
+		"hören", "schlüssel", "frühling", "gemütlich", "löwe", "märchen", "süß",
+	},
+	Spanish: []string{
+		"niño", "año", "mañana", "señor", "corazón", "canción", "árbol", "está", "después",
+		"también", "río", "jardín", "música", "pájaro", "montaña", "sueño", "camión",
+		"fácil", "difícil", "cielo", "tierra", "agua", "fuego", "ciudad", "lápiz", "país",
+		"médico", "último", "azúcar", "pequeño", "señora", "aquí", "allá",
+	},
+	Russian: []string{
+		"съешь", "же", "ещё", "этих", "мягких", "французских", "булок", "да", "выпей", "чаю",
+		"город", "река", "солнце", "дерево", "книга", "окно", "дорога", "небо", "море",
+		"снег", "зима", "лето", "весна", "осень", "утро", "вечер", "ночь", "день", "дом",
+		"сад", "лес", "поле", "мир", "слово", "время", "жизнь", "рука", "голос",
+	},
+	Greek: []string{
+		"θάλασσα", "ήλιος", "ουρανός", "βουνό", "δέντρο", "νερό", "φως", "σπίτι", "δρόμος",
+		"πόλη", "χρόνος", "λόγος", "ψυχή", "καρδιά", "φίλος", "αγάπη", "μέρα", "νύχτα",
+		"άνεμος", "πέτρα", "λουλούδι", "βιβλίο", "γη", "φωτιά", "ποτάμι", "κήπος",
+	},
+}
@@ -0,0 +1,17 @@
+package data
+
+// LicensePlateFormats is a lookup map of regions to the layouts of the license plates
+// issued there. Regions are ISO 3166 codes, with a subdivision for the US states, which
+// each issue their own plates. In each layout, # is a digit and @ is an upper case letter.
+var LicensePlateFormats = map[string][]string{
+	"US-CA": []string{"#@@@###"},
+	"US-NY": []string{"@@@-####"},
+	"US-TX": []string{"@@@-####", "@@#-@###"},
+	"US-FL": []string{"@@@-@##", "###-@@@"},
+	"UK":    []string{"@@## @@@"},
+	"DE":    []string{"@ @@ ####", "@@ @ ###", "@@@ @@ ##", "@@ @@ ####"},
+	"FR":    []string{"@@-###-@@"},
+	"IT":    []string{"@@ ###@@"},
+	"ES":    []string{"#### @@@"},
+	"NL":    []string{"@@-###-@", "#-@@@-##", "@@-##-@@"},
+}
@@ -0,0 +1,43 @@
+package data
+
+// WeightedValue is a value which is picked in proportion to it's weight, relative to the
+// weights of the others in the same set
+type WeightedValue struct {
+	Value  string
+	Weight float64
+}
+
+// StatusSets is a lookup map of names to sets of weighted values, for categorical columns
+// such as the status of an order. The weights of each set add up to 100, so that they
+// read as a percentage.
+var StatusSets = map[string][]WeightedValue{
+	"order": []WeightedValue{
+		{"pending", 40},
+		{"shipped", 50},
+		{"cancelled", 10},
+	},
+	"payment": []WeightedValue{
+		{"authorized", 15},
+		{"captured", 70},
+		{"refunded", 5},
+		{"declined", 10},
+	},
+	"ticket": []WeightedValue{
+		{"open", 25},
+		{"in progress", 20},
+		{"resolved", 40},
+		{"closed", 15},
+	},
+	"priority": []WeightedValue{
+		{"low", 30},
+		{"medium", 45},
+		{"high", 20},
+		{"critical", 5},
+	},
+	"httpclass": []WeightedValue{
+		{"2xx", 80},
+		{"3xx", 5},
+		{"4xx", 12},
+		{"5xx", 3},
+	},
+}
@@ -0,0 +1,79 @@
+package data
+
+// TimeZoneRegion is a rough box around a region which keeps the time of a single IANA
+// time zone. The boxes are coarse, and are meant to give a plausible zone for a random
+// coordinate, not to draw borders.
+type TimeZoneRegion struct {
+	Zone   string
+	MinLat float64
+	MaxLat float64
+	MinLng float64
+	MaxLng float64
+}
+
+// TimeZoneRegions are checked in order, and the first box holding a coordinate gives it's
+// zone, so smaller regions come before larger ones which overlap them. Coordinates outside
+// of every box fall back to a zone from their longitude alone.
+var TimeZoneRegions = []*TimeZoneRegion{
+	// North America
+	&TimeZoneRegion{"Pacific/Honolulu", 18, 23, -161, -154},
+	&TimeZoneRegion{"America/Anchorage", 51, 72, -170, -130},
+	&TimeZoneRegion{"America/Los_Angeles", 32, 49, -125, -114},
+	&TimeZoneRegion{"America/Denver", 31, 49, -114, -102},
+	&TimeZoneRegion{"America/Chicago", 25, 49, -102, -87},
+	&TimeZoneRegion{"America/New_York", 24, 47.5, -87, -66},
+	&TimeZoneRegion{"America/Vancouver", 49, 60, -139, -114},
+	&TimeZoneRegion{"America/Edmonton", 49, 60, -114, -102},
+	&TimeZoneRegion{"America/Winnipeg", 49, 60, -102, -89},
+	&TimeZoneRegion{"America/Toronto", 47.5, 57, -89, -74},
+	&TimeZoneRegion{"America/Mexico_City", 14, 25, -106, -86},
+	// South America
+	&TimeZoneRegion{"America/Bogota", 0, 12, -79, -67},
+	&TimeZoneRegion{"America/Lima", -18, 0, -81, -68},
+	&TimeZoneRegion{"America/Sao_Paulo", -34, 5, -58, -34},
+	&TimeZoneRegion{"America/Santiago", -56, -17.5, -76, -70},
+	&TimeZoneRegion{"America/Argentina/Buenos_Aires", -55, -22, -73, -53},
+	// Europe
+	&TimeZoneRegion{"Europe/Dublin", 51.4, 55.4, -10.5, -6},
+	&TimeZoneRegion{"Europe/London", 49.9, 60.9, -8.2, 1.8},
+	&TimeZoneRegion{"Europe/Lisbon", 36.9, 42.2, -9.5, -6.2},
+	&TimeZoneRegion{"Europe/Madrid", 36, 43.8, -6.2, 3.3},
+	&TimeZoneRegion{"Europe/Paris", 42.3, 51.1, -4.8, 8.2},
+	&TimeZoneRegion{"Europe/Berlin", 47.3, 55.1, 5.9, 15},
+	&TimeZoneRegion{"Europe/Rome", 36.6, 47.1, 6.6, 18.5},
+	&TimeZoneRegion{"Europe/Warsaw", 49, 54.9, 14.1, 24.2},
+	&TimeZoneRegion{"Europe/Oslo", 58, 71, 4.5, 11},
+	&TimeZoneRegion{"Europe/Helsinki", 59.8, 70.1, 24, 31.6},
+	&TimeZoneRegion{"Europe/Stockholm", 55, 69, 11, 24},
+	&TimeZoneRegion{"Europe/Athens", 34.8, 41.8, 19.4, 26},
+	&TimeZoneRegion{"Europe/Bucharest", 43.6, 48.3, 22, 29.7},
+	&TimeZoneRegion{"Europe/Kiev", 44.3, 52.4, 24.2, 40.2},
+	&TimeZoneRegion{"Asia/Tehran", 33, 39.8, 44.5, 48.5},
+	&TimeZoneRegion{"Asia/Tehran", 25.5, 39.8, 48.5, 61.5},
+	&TimeZoneRegion{"Europe/Istanbul", 36, 42.1, 26, 45},
+	&TimeZoneRegion{"Europe/Moscow", 41, 70, 27, 60},
+	// Africa
+	&TimeZoneRegion{"Africa/Cairo", 22, 31.7, 24.7, 36.9},
+	&TimeZoneRegion{"Africa/Lagos", 4, 14, 2.7, 14.7},
+	&TimeZoneRegion{"Africa/Nairobi", -4.7, 5, 33.9, 41.9},
+	&TimeZoneRegion{"Africa/Johannesburg", -35, -22, 16.5, 32.9},
+	// Asia
+	&TimeZoneRegion{"Asia/Dubai", 22.6, 26.1, 51.5, 56.4},
+	&TimeZoneRegion{"Asia/Karachi", 23.6, 37.1, 60.9, 74.6},
+	&TimeZoneRegion{"Asia/Kolkata", 6.7, 35.5, 68.1, 97.4},
+	&TimeZoneRegion{"Asia/Singapore", 1.1, 1.5, 103.6, 104.1},
+	&TimeZoneRegion{"Asia/Kuala_Lumpur", 1.5, 6.8, 99.6, 104.5},
+	&TimeZoneRegion{"Asia/Bangkok", 5.6, 20.5, 97.3, 105.6},
+	&TimeZoneRegion{"Asia/Jakarta", -11, 6, 95, 115},
+	&TimeZoneRegion{"Asia/Seoul", 33, 38.6, 124.6, 130.9},
+	&TimeZoneRegion{"Asia/Tokyo", 24, 45.6, 129, 153.9},
+	&TimeZoneRegion{"Asia/Shanghai", 18, 53.6, 73.5, 134.8},
+	// Oceania
+	&TimeZoneRegion{"Australia/Perth", -35, -13.7, 112.9, 129},
+	&TimeZoneRegion{"Australia/Darwin", -26, -10.9, 129, 138},
+	&TimeZoneRegion{"Australia/Adelaide", -38, -26, 129, 141},
+	&TimeZoneRegion{"Australia/Melbourne", -39.2, -34, 141, 150},
+	&TimeZoneRegion{"Australia/Sydney", -37.5, -28, 141, 153.7},
+	&TimeZoneRegion{"Australia/Brisbane", -29, -10, 138, 153.6},
+	&TimeZoneRegion{"Pacific/Auckland", -47.3, -34.4, 166.4, 178.6},
+}
@@ -0,0 +1,188 @@
+package data
+
+// Words are common English words, sorted by length, for values such as tags, keywords,
+// and search terms which should be real words
+var Words = []string{
+	"a", "an", "as", "at", "be", "by", "do", "go", "he", "if", "in", "is", "it", "me",
+	"my", "no", "of", "on", "or", "so", "to", "up", "us", "we", "act", "age", "air",
+	"all", "and", "any", "arm", "art", "ask", "bad", "bag", "bed", "big", "bit", "box",
+	"boy", "bus", "buy", "can", "car", "cat", "cup", "cut", "day", "dog", "dry", "ear",
+	"eat", "egg", "end", "eye", "far", "fat", "few", "fit", "fly", "for", "fun", "gas",
+	"get", "god", "gun", "hat", "her", "him", "his", "hit", "hot", "how", "ice", "job",
+	"key", "kid", "lab", "law", "lay", "leg", "let", "lie", "lip", "lot", "low", "man",
+	"map", "may", "mix", "mom", "new", "not", "now", "odd", "off", "oil", "old", "one",
+	"our", "out", "own", "pay", "pen", "per", "pet", "pie", "pot", "put", "raw", "red",
+	"rid", "row", "run", "sad", "say", "sea", "see", "set", "she", "shy", "sit", "six",
+	"sky", "son", "sun", "tax", "tea", "ten", "the", "tie", "tip", "toe", "top", "toy",
+	"try", "two", "use", "van", "war", "way", "web", "wet", "who", "why", "win", "yes",
+	"yet", "you", "zoo", "able", "also", "area", "army", "away", "baby", "back", "bake",
+	"ball", "band", "bank", "base", "bath", "bear", "beat", "bell", "belt", "best",
+	"bird", "blow", "blue", "boat", "body", "bone", "book", "boot", "born", "boss",
+	"both", "bowl", "burn", "busy", "cake", "call", "calm", "camp", "card", "care",
+	"case", "cash", "cell", "chef", "chip", "city", "clay", "club", "coal", "coat",
+	"code", "cold", "cook", "cool", "copy", "corn", "cost", "crew", "crop", "dark",
+	"data", "date", "dawn", "deal", "deep", "desk", "diet", "dirt", "door", "down",
+	"draw", "drop", "drum", "duck", "dust", "duty", "each", "earn", "east", "easy",
+	"edge", "else", "even", "ever", "exit", "face", "fact", "fair", "fall", "farm",
+	"fast", "fear", "feel", "file", "film", "fire", "fish", "flag", "flat", "flow",
+	"food", "foot", "form", "free", "frog", "fuel", "full", "game", "gate", "gift",
+	"girl", "glad", "goal", "gold", "golf", "good", "gray", "grow", "hair", "half",
+	"hall", "hand", "hard", "harm", "head", "hear", "heat", "help", "hero", "hill",
+	"hint", "hole", "home", "hope", "horn", "host", "hour", "huge", "idea", "iron",
+	"item", "join", "joke", "jump", "jury", "keen", "kind", "king", "kiss", "knee",
+	"knot", "lady", "lake", "lamp", "land", "lane", "last", "late", "lawn", "lead",
+	"leaf", "left", "lens", "life", "lift", "line", "link", "lion", "list", "load",
+	"loan", "lock", "long", "loop", "love", "luck", "mail", "main", "make", "mark",
+	"mask", "meal", "meat", "menu", "milk", "mind", "mine", "mode", "moon", "more",
+	"most", "move", "name", "navy", "near", "neck", "need", "nest", "news", "next",
+	"nice", "node", "nose", "note", "oven", "pack", "page", "pain", "pair", "palm",
+	"park", "part", "past", "path", "peak", "pick", "pink", "pipe", "plan", "play",
+	"plot", "poem", "pole", "pool", "port", "post", "quiz", "race", "rain", "rank",
+	"rate", "read", "rice", "rich", "ride", "ring", "rise", "risk", "road", "rock",
+	"role", "roof", "room", "root", "rope", "rose", "rule", "safe", "sail", "salt",
+	"sand", "save", "seat", "seed", "self", "sell", "ship", "shoe", "shop", "side",
+	"sign", "silk", "sing", "site", "size", "skin", "slow", "snow", "soap", "sock",
+	"soft", "soil", "song", "sort", "soup", "spot", "star", "stay", "step", "stop",
+	"suit", "swim", "tail", "talk", "tall", "tank", "task", "team", "tent", "term",
+	"test", "text", "tide", "time", "tone", "tool", "tour", "town", "tree", "trip",
+	"true", "tube", "tune", "turn", "unit", "user", "vast", "view", "vote", "wage",
+	"wait", "walk", "wall", "warm", "wave", "wear", "week", "well", "west", "wide",
+	"wife", "wild", "wind", "wine", "wing", "wire", "wise", "wish", "wolf", "wood",
+	"wool", "word", "work", "yard", "year", "zero", "zone", "about", "above", "acorn",
+	"actor", "adult", "agent", "alarm", "album", "alert", "alive", "angle", "apple",
+	"apron", "arena", "armor", "arrow", "audio", "award", "badge", "basic", "beach",
+	"begin", "bench", "berry", "bible", "bikes", "blade", "blank", "blend", "block",
+	"bloom", "board", "bonus", "brain", "brave", "bread", "brick", "bride", "brief",
+	"brown", "brush", "build", "cabin", "cable", "camel", "candy", "canoe", "cargo",
+	"chair", "chalk", "charm", "chart", "chase", "cheap", "check", "chess", "chest",
+	"chief", "child", "chord", "civil", "claim", "class", "clean", "clerk", "click",
+	"cliff", "climb", "clock", "cloud", "coach", "coast", "color", "coral", "couch",
+	"count", "court", "cover", "craft", "crane", "crowd", "crown", "curve", "cycle",
+	"daily", "dance", "delay", "depth", "diary", "dream", "dress", "drink", "drive",
+	"eagle", "early", "earth", "elbow", "empty", "enemy", "enjoy", "entry", "equal",
+	"error", "event", "exact", "extra", "fable", "faith", "fancy", "feast", "fence",
+	"fever", "fiber", "field", "final", "flame", "flash", "fleet", "float", "flour",
+	"fluid", "focus", "force", "forge", "frame", "fresh", "front", "frost", "fruit",
+	"funny", "giant", "glass", "globe", "grace", "grain", "grape", "graph", "grass",
+	"green", "group", "guard", "guest", "guide", "habit", "happy", "heart", "heavy",
+	"hobby", "honey", "horse", "hotel", "house", "human", "humor", "image", "index",
+	"inner", "input", "ivory", "jelly", "jewel", "judge", "juice", "knife", "label",
+	"labor", "laser", "later", "layer", "lemon", "level", "light", "limit", "linen",
+	"liver", "local", "logic", "lucky", "lunch", "magic", "major", "maple", "march",
+	"match", "mayor", "medal", "metal", "meter", "model", "money", "month", "motor",
+	"mouse", "mouth", "movie", "music", "nerve", "night", "noble", "noise", "north",
+	"novel", "nurse", "ocean", "offer", "olive", "onion", "opera", "orbit", "order",
+	"organ", "other", "owner", "paint", "panel", "paper", "party", "pasta", "patch",
+	"peace", "pearl", "pedal", "phase", "phone", "photo", "piano", "pilot", "pitch",
+	"pizza", "place", "plain", "plane", "plant", "plate", "point", "polar", "pouch",
+	"power", "press", "price", "pride", "prime", "print", "prize", "proof", "proud",
+	"pulse", "punch", "queen", "quick", "quiet", "radio", "raise", "range", "rapid",
+	"ratio", "reach", "ready", "realm", "relay", "reply", "rider", "ridge", "right",
+	"river", "robot", "rough", "round", "route", "royal", "ruler", "rural", "salad",
+	"sauce", "scale", "scene", "scope", "score", "sense", "serve", "shade", "shape",
+	"share", "sheep", "shelf", "shell", "shift", "shine", "shirt", "short", "skill",
+	"sleep", "slice", "slope", "small", "smart", "smile", "smoke", "snack", "solar",
+	"solid", "sound", "south", "space", "spark", "speed", "spice", "spine", "split",
+	"spoon", "sport", "staff", "stage", "stair", "stamp", "stand", "steam", "steel",
+	"stick", "stone", "storm", "story", "stove", "sugar", "sunny", "super", "sweet",
+	"table", "taste", "teach", "thumb", "tiger", "title", "toast", "token", "tooth",
+	"topic", "torch", "total", "tower", "track", "trade", "trail", "train", "treat",
+	"trend", "trial", "tribe", "truck", "trust", "truth", "uncle", "union", "upper",
+	"urban", "usage", "valid", "value", "valve", "vapor", "video", "visit", "vital",
+	"voice", "water", "whale", "wheat", "wheel", "white", "whole", "woman", "world",
+	"worth", "youth", "absorb", "access", "action", "active", "advice", "afford",
+	"agency", "almond", "amount", "anchor", "animal", "answer", "archer", "arctic",
+	"artist", "aspect", "assist", "attach", "august", "autumn", "avenue", "badger",
+	"ballet", "bamboo", "banana", "banner", "barrel", "basket", "battle", "beacon",
+	"beauty", "beaver", "became", "before", "belong", "bishop", "border", "bottle",
+	"bounce", "branch", "breeze", "bridge", "bright", "broken", "bronze", "bubble",
+	"bucket", "budget", "bundle", "butter", "button", "cactus", "camera", "candle",
+	"canvas", "carbon", "carpet", "carrot", "castle", "casual", "cattle", "center",
+	"cereal", "chance", "change", "cherry", "choice", "circle", "clever", "client",
+	"closet", "cobalt", "coffee", "collar", "colony", "column", "comedy", "common",
+	"copper", "corner", "cotton", "county", "cousin", "credit", "cruise", "custom",
+	"dancer", "debate", "decade", "decent", "degree", "desert", "design", "detail",
+	"device", "dinner", "direct", "doctor", "dollar", "donkey", "double", "dragon",
+	"drawer", "driver", "editor", "effect", "effort", "either", "empire", "energy",
+	"engine", "escape", "estate", "fabric", "falcon", "family", "farmer", "father",
+	"fellow", "figure", "finger", "finish", "flight", "flower", "folder", "forest",
+	"formal", "fossil", "frozen", "future", "galaxy", "garage", "garden", "garlic",
+	"gentle", "ginger", "global", "golden", "growth", "guitar", "hammer", "handle",
+	"harbor", "health", "helmet", "hidden", "hockey", "honest", "impact", "income",
+	"indoor", "insect", "island", "jacket", "jersey", "jungle", "junior", "kettle",
+	"kitten", "ladder", "launch", "lawyer", "leader", "legend", "lesson", "letter",
+	"liquid", "little", "lizard", "locker", "lumber", "magnet", "manner", "marble",
+	"margin", "market", "master", "meadow", "member", "memory", "mentor", "method",
+	"middle", "mirror", "mobile", "modern", "moment", "monkey", "mother", "motion",
+	"museum", "narrow", "nature", "needle", "number", "object", "office", "orange",
+	"origin", "output", "oxygen", "oyster", "palace", "pantry", "parade", "parcel",
+	"parent", "parrot", "pastel", "pencil", "people", "pepper", "period", "pickle",
+	"pigeon", "planet", "player", "pocket", "poetry", "policy", "potato", "powder",
+	"prince", "prison", "profit", "public", "puzzle", "rabbit", "random", "reader",
+	"record", "region", "remote", "rescue", "result", "ribbon", "rocket", "rubber",
+	"saddle", "safety", "salmon", "sample", "school", "screen", "season", "second",
+	"secret", "select", "senior", "shadow", "silver", "simple", "singer", "sister",
+	"sketch", "smooth", "soccer", "socket", "spider", "spirit", "spring", "square",
+	"stable", "statue", "stream", "street", "string", "studio", "summer", "sunset",
+	"supply", "switch", "symbol", "tablet", "talent", "target", "temple", "tennis",
+	"thread", "ticket", "timber", "tomato", "travel", "tunnel", "turtle", "unique",
+	"update", "valley", "velvet", "vendor", "violet", "violin", "vision", "volume",
+	"walnut", "wealth", "weekly", "window", "winter", "wisdom", "wonder", "worker",
+	"yellow", "zipper", "account", "address", "airport", "ancient", "apricot", "arrival",
+	"balance", "balloon", "bargain", "battery", "bedroom", "benefit", "bicycle",
+	"biscuit", "blanket", "blossom", "cabinet", "capital", "captain", "caravan",
+	"cartoon", "century", "chamber", "channel", "chapter", "chicken", "circuit",
+	"citizen", "climate", "cluster", "coconut", "collect", "comfort", "company",
+	"compass", "concert", "cottage", "council", "country", "courage", "crystal",
+	"culture", "current", "cushion", "cutlery", "diamond", "digital", "dolphin",
+	"drawing", "eastern", "economy", "element", "emerald", "engrave", "evening",
+	"example", "factory", "fashion", "feather", "fiction", "flannel", "foliage",
+	"forward", "freedom", "gallery", "garment", "general", "genuine", "giraffe",
+	"glacier", "harmony", "harvest", "heading", "highway", "history", "holiday",
+	"horizon", "husband", "imagine", "journal", "journey", "justice", "kitchen",
+	"lantern", "leather", "library", "license", "lobster", "machine", "mammoth",
+	"mansion", "measure", "meeting", "message", "mineral", "minimum", "mission",
+	"morning", "mustard", "mystery", "natural", "network", "nothing", "october",
+	"officer", "orchard", "organic", "outdoor", "painter", "panther", "parking",
+	"partner", "passage", "pattern", "pelican", "penguin", "pension", "picture",
+	"pilgrim", "pioneer", "plastic", "pottery", "poultry", "premium", "primary",
+	"problem", "produce", "product", "program", "project", "promise", "quality",
+	"quarter", "rainbow", "readers", "receipt", "recycle", "reptile", "respect",
+	"rooster", "sailing", "science", "scooter", "seaside", "section", "session",
+	"shelter", "shuttle", "silence", "society", "soldier", "sparrow", "speaker",
+	"station", "storage", "student", "subject", "success", "sunrise", "surface",
+	"teacher", "theater", "thunder", "tourist", "traffic", "trumpet", "uniform",
+	"upgrade", "utensil", "vehicle", "venture", "version", "village", "vintage",
+	"volcano", "walking", "warrior", "weather", "website", "wedding", "welcome",
+	"western", "whisper", "whistle", "witness", "absolute", "accuracy", "activity",
+	"airplane", "alphabet", "aluminum", "ambition", "analysis", "antelope", "anything",
+	"appetite", "approach", "argument", "audience", "aviation", "backpack", "baseball",
+	"basement", "birthday", "blizzard", "bookcase", "boundary", "bracelet", "building",
+	"business", "calendar", "campaign", "campfire", "cardinal", "ceremony", "champion",
+	"chemical", "children", "chipmunk", "climbing", "clothing", "coaching", "complete",
+	"compound", "computer", "concrete", "constant", "contract", "cucumber", "customer",
+	"daughter", "daylight", "decision", "delivery", "designer", "dinosaur", "director",
+	"discount", "distance", "document", "doorbell", "dumpling", "electric", "elephant",
+	"employee", "engineer", "envelope", "equation", "estimate", "evidence", "exercise",
+	"explorer", "facility", "festival", "flamingo", "football", "fountain", "friendly",
+	"frontier", "function", "generous", "gigantic", "goldfish", "graphite", "guidance",
+	"hardware", "hedgehog", "heritage", "homework", "hospital", "identity", "incident",
+	"industry", "innocent", "interest", "interval", "iterator", "keyboard", "kindness",
+	"language", "lavender", "learning", "lemonade", "lifetime", "location", "magazine",
+	"mandarin", "marathon", "material", "meantime", "medicine", "midnight", "molecule",
+	"mosquito", "mountain", "musician", "national", "neighbor", "notebook", "occasion",
+	"offering", "operator", "ordinary", "organism", "overview", "painting", "pancakes",
+	"paradise", "parallel", "particle", "passport", "peaceful", "pedestal", "periodic",
+	"physical", "platform", "pleasant", "plumbing", "politics", "position", "possible",
+	"practice", "precious", "presence", "pressure", "princess", "progress", "property",
+	"protocol", "provider", "quantity", "question", "raincoat", "reaction", "recovery",
+	"regional", "register", "relation", "remember", "research", "resource", "response",
+	"sandwich", "scenario", "schedule", "seahorse", "shepherd", "shoulder", "sidewalk",
+	"skeleton", "snowball", "software", "solution", "specimen", "squirrel", "standard",
+	"starfish", "strategy", "strength", "struggle", "sunlight", "sunshine", "surprise",
+	"swimming", "teaspoon", "terminal", "thousand", "together", "tomorrow", "tortoise",
+	"tracking", "treasure", "triangle", "umbrella", "universe", "vacation", "valuable",
+	"variable", "velocity", "vineyard", "wildlife", "woodland", "workshop", "yourself",
+	"angelfish", "chocolate", "condition", "continent", "dandelion", "dragonfly",
+	"fireworks", "furniture", "hairbrush", "invention", "pineapple",
+}
@@ -0,0 +1,94 @@
+package moldova
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+// LoadDefaults reads a file of defaults, such as the conventions shared by a team, and
+// changes each of them on the Callstack with SetDefault. The file is either a JSON object
+// of tokens to their options:
+//
+//	{"int": {"min": 0, "max": 1000}, "time": {"zone": "America/New_York"}}
+//
+// or lines of token.option=value, where blank lines and lines starting with # are
+// skipped:
+//
+//	# ids are never negative
+//	int.min=0
+//	int.max=1000
+//	time.zone=America/New_York
+//
+// It returns an error for the first default which can't be read or set, after setting
+// those before it.
+func (c *Callstack) LoadDefaults(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 && trimmed[0] == '{' {
+		return c.loadJSONDefaults(trimmed)
+	}
+	s := bufio.NewScanner(bytes.NewReader(b))
+	for line := 1; s.Scan(); line++ {
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		kv := strings.SplitN(text, "=", 2)
+		key := strings.SplitN(strings.TrimSpace(kv[0]), ".", 2)
+		if len(kv) != 2 || len(key) != 2 {
+			return InvalidArgumentError(fmt.Sprintf("line %d of the defaults, %q, must be written as token.option=value", line, text))
+		}
+		if err := c.SetDefault(key[0], key[1], strings.TrimSpace(kv[1])); err != nil {
+			return InvalidArgumentError(fmt.Sprintf("line %d of the defaults: %s", line, err))
+		}
+	}
+	return s.Err()
+}
+
+// loadJSONDefaults sets the defaults in a JSON object of tokens to their options. Values
+// may be strings, numbers, or booleans, as they are all written the same in a template.
+// The tokens and options are set in order, so the same file always fails the same way.
+func (c *Callstack) loadJSONDefaults(b []byte) error {
+	d := json.NewDecoder(bytes.NewReader(b))
+	// Keep numbers as they were written, so 1000 isn't turned into 1e+03
+	d.UseNumber()
+	var defaults map[string]map[string]interface{}
+	if err := d.Decode(&defaults); err != nil {
+		return InvalidArgumentError(fmt.Sprintf("the defaults are not a JSON object of tokens to their options: %s", err))
+	}
+	tokens := make([]string, 0, len(defaults))
+	for name := range defaults {
+		tokens = append(tokens, name)
+	}
+	sort.Strings(tokens)
+	for _, name := range tokens {
+		options := make([]string, 0, len(defaults[name]))
+		for option := range defaults[name] {
+			options = append(options, option)
+		}
+		sort.Strings(options)
+		for _, option := range options {
+			var value string
+			switch v := defaults[name][option].(type) {
+			case string:
+				value = v
+			case json.Number, bool:
+				value = fmt.Sprint(v)
+			default:
+				return InvalidArgumentError(fmt.Sprintf("the default for %s.%s must be a string, number, or boolean", name, option))
+			}
+			if err := c.SetDefault(name, option, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -1,5 +1,10 @@
 package moldova
 
+import (
+	"fmt"
+	"strings"
+)
+
 // UnsupportedTokenError is returned from the parser when it encounters an unknown token
 type UnsupportedTokenError string
 
@@ -16,3 +21,16 @@ type InvalidArgumentError string
 func (e InvalidArgumentError) Error() string {
 	return string(e)
 }
+
+// WriteErrors is returned from Write when the Callstack collects errors, holding the
+// error from each token which failed, in the order they were written
+type WriteErrors []error
+
+// Error implmenets the error interface
+func (e WriteErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d tokens failed to write: %s", len(e), strings.Join(msgs, "; "))
+}
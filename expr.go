@@ -0,0 +1,244 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// expr works out a small arithmetic expression over whole numbers. The expression can
+// use the numbers stored by other tokens with the as option, by their name, and the
+// ints generated so far, by their ordinal written as $0, $1, and so on. It supports
+// +, -, *, /, and %, with the usual precedence, and parenthesis.
+func expr(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["expr"]
+		cache := c.([]int)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "expr", len(cache))
+		}
+		return strconv.Itoa(cache[ord]), nil
+	}
+
+	p := &exprParser{input: opts["value"], oc: oc}
+	n, err := p.parseSum()
+	if err != nil {
+		return "", err
+	}
+	if p.skipSpaces(); p.pos < len(p.input) {
+		return "", p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	// store it in the cache
+	ca := oc["expr"]
+	cache := ca.([]int)
+	oc["expr"] = append(cache, n)
+	oc.setNamed(opts["as"], n)
+
+	return strconv.Itoa(n), nil
+}
+
+// exprParser is a recursive descent parser for the value of an expr token, which works
+// out the result as it goes
+type exprParser struct {
+	input string
+	pos   int
+	oc    objectCache
+}
+
+func (p *exprParser) errorf(format string, args ...interface{}) error {
+	return InvalidArgumentError(fmt.Sprintf("value: %s at character %d of the expression %q. Please check your input string", fmt.Sprintf(format, args...), p.pos, p.input))
+}
+
+func (p *exprParser) skipSpaces() {
+	for p.pos < len(p.input) && p.input[p.pos] == ' ' {
+		p.pos++
+	}
+}
+
+// peek returns the next character which is not a space, or 0 at the end of the input
+func (p *exprParser) peek() byte {
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// parseSum handles + and -, which bind the loosest
+func (p *exprParser) parseSum() (int, error) {
+	n, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '+' && op != '-' {
+			return n, nil
+		}
+		p.pos++
+		m, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		var ok bool
+		if op == '+' {
+			n, ok = addInts(n, m)
+		} else {
+			n, ok = subtractInts(n, m)
+		}
+		if !ok {
+			return 0, p.errorf("the result of %c is too large to be a whole number", op)
+		}
+	}
+}
+
+// parseProduct handles *, /, and %
+func (p *exprParser) parseProduct() (int, error) {
+	n, err := p.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if op != '*' && op != '/' && op != '%' {
+			return n, nil
+		}
+		p.pos++
+		m, err := p.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			var ok bool
+			if n, ok = multiplyInts(n, m); !ok {
+				return 0, p.errorf("the result of * is too large to be a whole number")
+			}
+		case '/', '%':
+			if m == 0 {
+				return 0, p.errorf("division by zero")
+			}
+			if op == '/' {
+				// The one division which overflows, as the smallest int has no positive
+				// counterpart
+				if n == minInt && m == -1 {
+					return 0, p.errorf("the result of / is too large to be a whole number")
+				}
+				n /= m
+			} else {
+				n %= m
+			}
+		}
+	}
+}
+
+// parseFactor handles numbers, references, negation, and parenthesis
+func (p *exprParser) parseFactor() (int, error) {
+	c := p.peek()
+	switch {
+	case c == '-':
+		p.pos++
+		n, err := p.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+		if n == minInt {
+			return 0, p.errorf("the result of - is too large to be a whole number")
+		}
+		return -n, nil
+	case c == '(':
+		p.pos++
+		n, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, p.errorf("missing )")
+		}
+		p.pos++
+		return n, nil
+	case c >= '0' && c <= '9':
+		digits := p.scan(isDigit)
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return 0, p.errorf("%s is too large to be a whole number", digits)
+		}
+		return n, nil
+	case c == '$':
+		p.pos++
+		ord, err := strconv.Atoi(p.scan(isDigit))
+		if err != nil {
+			return 0, p.errorf("$ must be followed by the ordinal of an int")
+		}
+		cache := p.oc["int"].([]int)
+		if len(cache)-1 < ord {
+			return 0, ordinalError(ord, "int", len(cache))
+		}
+		return cache[ord], nil
+	case isNameChar(c):
+		name := p.scan(isNameChar)
+		v, err := p.oc.getNamed(name)
+		if err != nil {
+			return 0, p.errorf("%s has not yet been defined with the as option", name)
+		}
+		n, ok := v.(int)
+		if !ok {
+			return 0, InvalidArgumentError(fmt.Sprintf("value: %s does not refer to a whole number. Please check your input string", name))
+		}
+		return n, nil
+	case c == 0:
+		return 0, p.errorf("unexpected end")
+	}
+	return 0, p.errorf("unexpected %q", c)
+}
+
+// minInt is the smallest int, which can't be negated
+const minInt = -1 << (strconv.IntSize - 1)
+
+// addInts returns a + b, and false if the sum overflows
+func addInts(a, b int) (int, bool) {
+	c := a + b
+	return c, (c > a) == (b > 0)
+}
+
+// subtractInts returns a - b, and false if the difference overflows
+func subtractInts(a, b int) (int, bool) {
+	c := a - b
+	return c, (c < a) == (b > 0)
+}
+
+// multiplyInts returns a * b, and false if the product overflows
+func multiplyInts(a, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	c := a * b
+	if (a == -1 && b == minInt) || (b == -1 && a == minInt) || c/b != a {
+		return c, false
+	}
+	return c, true
+}
+
+// scan moves past every character matching the function, and returns them
+func (p *exprParser) scan(match func(byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.input) && match(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' || isDigit(c) || strings.IndexByte("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ", c) >= 0
+}
@@ -0,0 +1,143 @@
+package moldova
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+)
+
+// ratio is a fraction as it was generated, so that a reference can write it in a
+// different format
+type ratio struct {
+	num int
+	den int
+}
+
+func fraction(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format := opts["format"]
+	if format != "fraction" && format != "percent" {
+		return "", InvalidArgumentError(fmt.Sprintf("format: %s is not a known fraction format. Use either fraction or percent", format))
+	}
+	prec, err := opts.getInt("precision")
+	if err != nil {
+		return "", err
+	} else if prec < 0 {
+		return "", InvalidArgumentError("You have specified a precision which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["fraction"]
+		cache := c.([]*ratio)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "fraction", len(cache))
+		}
+		return formatRatio(cache[ord], format, prec), nil
+	}
+
+	den, err := opts.getInt("denominator")
+	if err != nil {
+		return "", err
+	} else if den <= 0 {
+		return "", InvalidArgumentError("You have specified a denominator which is not a number greater than zero. Please check your input string")
+	}
+	min, err := opts.getFloat("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getFloat("max")
+	if err != nil {
+		return "", err
+	}
+	if min < 0 || max > 1 || min > max {
+		return "", InvalidArgumentError("You have specified a min and max which are not numbers from 0 to 1, with the min no greater than the max. Please check your input string")
+	}
+
+	// Only numerators which keep the fraction within min and max can be picked. The
+	// small allowance keeps a bound like 0.3 from missing 3/10 to rounding.
+	lo := int(math.Ceil(min*float64(den) - 1e-9))
+	hi := int(math.Floor(max*float64(den) + 1e-9))
+	if lo > hi {
+		return "", InvalidArgumentError(fmt.Sprintf("There is no fraction over %d from %v to %v. Please check your input string", den, min, max))
+	}
+	r := &ratio{num: lo + rnd.Intn(hi-lo+1), den: den}
+
+	// store it in the cache
+	ca := oc["fraction"]
+	cache := ca.([]*ratio)
+	oc["fraction"] = append(cache, r)
+
+	return formatRatio(r, format, prec), nil
+}
+
+// formatRatio writes the fraction as num/den, or as a percentage with prec decimal places
+func formatRatio(r *ratio, format string, prec int) string {
+	if format == "percent" {
+		return strconv.FormatFloat(float64(r.num)*100/float64(r.den), 'f', prec, 64) + "%"
+	}
+	return strconv.Itoa(r.num) + "/" + strconv.Itoa(r.den)
+}
+
+func percentage(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	prec, err := opts.getInt("precision")
+	if err != nil {
+		return "", err
+	} else if prec < 0 {
+		return "", InvalidArgumentError("You have specified a precision which is not a number greater than or equal to zero. Please check your input string")
+	}
+	symbol, err := opts.getBool("symbol")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["percentage"]
+		cache := c.([]float64)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "percentage", len(cache))
+		}
+		return formatPercentage(cache[ord], prec, symbol), nil
+	}
+
+	min, err := opts.getFloat("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getFloat("max")
+	if err != nil {
+		return "", err
+	}
+	if min < 0 || max > 100 || min > max {
+		return "", InvalidArgumentError("You have specified a min and max which are not numbers from 0 to 100, with the min no greater than the max. Please check your input string")
+	}
+	// Both ends are included, so that 100% can come up
+	n := min + unitFloat(rnd, true)*(max-min)
+	if n > max {
+		n = max
+	}
+
+	// store it in the cache
+	ca := oc["percentage"]
+	cache := ca.([]float64)
+	oc["percentage"] = append(cache, n)
+
+	return formatPercentage(n, prec, symbol), nil
+}
+
+// formatPercentage writes the percentage with prec decimal places, and a % sign if symbol
+// is set
+func formatPercentage(n float64, prec int, symbol bool) string {
+	s := strconv.FormatFloat(n, 'f', prec, 64)
+	if symbol {
+		s += "%"
+	}
+	return s
+}
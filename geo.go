@@ -0,0 +1,205 @@
+package moldova
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+// point is a coordinate kept with the as option, so other tokens can be placed there
+type point struct {
+	lat float64
+	lng float64
+}
+
+func latlng(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	prec, err := opts.getInt("precision")
+	if err != nil {
+		return "", err
+	} else if prec < 0 {
+		return "", InvalidArgumentError("You have specified a precision which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["latlng"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "latlng", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	bounds := []string{opts["minlng"], opts["minlat"], opts["maxlng"], opts["maxlat"]}
+	// The bounding box overrides the individual bounds, and follows the GeoJSON order of
+	// west, south, east, north
+	if bbox := opts["bbox"]; bbox != "" {
+		if bounds = strings.Split(bbox, ","); len(bounds) != 4 {
+			return "", InvalidArgumentError(fmt.Sprintf("bbox: %s is not in the form minlng,minlat,maxlng,maxlat. Please check your input string", bbox))
+		}
+	}
+	lat, err := coordinate(rnd, "lat", bounds[1], bounds[3], 90)
+	if err != nil {
+		return "", err
+	}
+	lng, err := coordinate(rnd, "lng", bounds[0], bounds[2], 180)
+	if err != nil {
+		return "", err
+	}
+	result := strconv.FormatFloat(lat, 'f', prec, 64) + "," + strconv.FormatFloat(lng, 'f', prec, 64)
+
+	// store it in the cache
+	ca := oc["latlng"]
+	cache := ca.([]string)
+	oc["latlng"] = append(cache, result)
+	oc.setNamed(opts["as"], &point{lat: lat, lng: lng})
+
+	return result, nil
+}
+
+// coordinate picks a random latitude or longitude between min and max, which cannot go
+// beyond plus or minus the limit
+func coordinate(rnd *rand.Rand, axis string, minValue string, maxValue string, limit float64) (float64, error) {
+	min, err := strconv.ParseFloat(strings.TrimSpace(minValue), 64)
+	if err != nil {
+		return 0, err
+	}
+	max, err := strconv.ParseFloat(strings.TrimSpace(maxValue), 64)
+	if err != nil {
+		return 0, err
+	}
+	if min < -limit || max > limit {
+		return 0, InvalidArgumentError(fmt.Sprintf("You cannot generate a %s outside of -%g to %g. Please check your input string", axis, limit, limit))
+	} else if min > max {
+		return 0, InvalidArgumentError(fmt.Sprintf("You cannot generate a random %s whose lower bound is greater than it's upper bound. Please check your input string", axis))
+	}
+	return min + rnd.Float64()*(max-min), nil
+}
+
+func timezone(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["timezone"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "timezone", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	var zone string
+	if ref := opts["ref"]; ref != "" {
+		v, err := oc.getNamed(ref)
+		if err != nil {
+			return "", err
+		}
+		p, ok := v.(*point)
+		if !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to a latlng. Please check your input string", ref))
+		}
+		zone = zoneAt(p)
+	} else {
+		zone = TimeZoneRegions[rnd.Intn(len(TimeZoneRegions))].Zone
+	}
+
+	// store it in the cache
+	ca := oc["timezone"]
+	cache := ca.([]string)
+	oc["timezone"] = append(cache, zone)
+
+	return zone, nil
+}
+
+func callingCode(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format := opts["format"]
+	if format != "plus" && format != "digits" && format != "idd" {
+		return "", InvalidArgumentError(fmt.Sprintf("format: %s is not one of plus, digits, or idd. Please check your input string", format))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["callingcode"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "callingcode", len(cache))
+		}
+		return formatCallingCode(cache[ord], format), nil
+	}
+
+	var code string
+	if ref := opts["ref"]; ref != "" {
+		v, err := oc.getNamed(ref)
+		if err != nil {
+			return "", err
+		}
+		c, ok := v.(*Country)
+		if !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to a country. Please check your input string", ref))
+		}
+		// A few entries, like the European Union, have no telephone network of their
+		// own, and are left blank
+		code = c.CallingCode
+	} else {
+		// Weight the codes by how many people live in each country, so that +1 and
+		// +86 come up far more often than +290
+		for code == "" {
+			code = Countries[weightedIndex(rnd, CountryPopulations)].CallingCode
+		}
+	}
+
+	// store it in the cache
+	ca := oc["callingcode"]
+	cache := ca.([]string)
+	oc["callingcode"] = append(cache, code)
+
+	return formatCallingCode(code, format), nil
+}
+
+// formatCallingCode writes the calling code with a +, as in +44, on it's own, or after
+// the 00 most countries dial before an international number, as in 0044
+func formatCallingCode(code string, format string) string {
+	if code == "" {
+		return ""
+	}
+	switch format {
+	case "plus":
+		return "+" + code
+	case "idd":
+		return "00" + code
+	}
+	return code
+}
+
+// zoneAt returns a plausible time zone for the point. Outside of the known regions, which
+// is mostly the oceans, it's the nautical zone for the longitude, one for every 15 degrees.
+func zoneAt(p *point) string {
+	for _, r := range TimeZoneRegions {
+		if p.lat >= r.MinLat && p.lat <= r.MaxLat && p.lng >= r.MinLng && p.lng <= r.MaxLng {
+			return r.Zone
+		}
+	}
+	offset := int(math.Floor(p.lng/15 + 0.5))
+	switch {
+	case offset == 0:
+		return "Etc/GMT"
+	case offset > 0:
+		// The Etc zones use the POSIX sign, which is the opposite of the offset from UTC
+		return "Etc/GMT-" + strconv.Itoa(offset)
+	}
+	return "Etc/GMT+" + strconv.Itoa(-offset)
+}
@@ -0,0 +1,118 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func httpstatus(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	class := strings.ToLower(opts["class"])
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["httpstatus"]
+		cache := c.([]int)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "httpstatus", len(cache))
+		}
+		return strconv.Itoa(cache[ord]), nil
+	}
+
+	codes := HTTPStatusCodes
+	if class != "any" {
+		if len(class) != 3 || class[0] < '1' || class[0] > '5' || class[1:] != "xx" {
+			return "", InvalidArgumentError(fmt.Sprintf("class: %s is not a known class of status codes. Use one of any, 1xx, 2xx, 3xx, 4xx, or 5xx", class))
+		}
+		// The class is the hundreds digit of the codes
+		hundreds := int(class[0]-'0') * 100
+		codes = make([]int, 0)
+		for _, code := range HTTPStatusCodes {
+			if code >= hundreds && code < hundreds+100 {
+				codes = append(codes, code)
+			}
+		}
+	}
+	code := codes[rnd.Intn(len(codes))]
+
+	// store it in the cache
+	ca := oc["httpstatus"]
+	cache := ca.([]int)
+	oc["httpstatus"] = append(cache, code)
+
+	return strconv.Itoa(code), nil
+}
+
+func httpmethod(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["httpmethod"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "httpmethod", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	i := rnd.Intn(len(HTTPMethods))
+	if w := opts["weights"]; w != "" {
+		weights, err := methodWeights(w)
+		if err != nil {
+			return "", err
+		}
+		i = weightedIndex(rnd, weights)
+	}
+	method := HTTPMethods[i]
+
+	// store it in the cache
+	ca := oc["httpmethod"]
+	cache := ca.([]string)
+	oc["httpmethod"] = append(cache, method)
+
+	return method, nil
+}
+
+// methodWeights parses a list of weights such as "GET=5,POST=2" into a weight for each
+// of the HTTPMethods, in order. Methods which are not listed are never chosen.
+func methodWeights(w string) ([]float64, error) {
+	weights := make([]float64, len(HTTPMethods))
+	total := 0.0
+	for _, pair := range strings.Split(w, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, InvalidArgumentError(fmt.Sprintf("weights: %s is not in the form METHOD=weight. Please check your input string", pair))
+		}
+		found := false
+		for i, m := range HTTPMethods {
+			if strings.EqualFold(m, kv[0]) {
+				f, err := strconv.ParseFloat(kv[1], 64)
+				if err != nil {
+					return nil, err
+				} else if f < 0 {
+					return nil, InvalidArgumentError(fmt.Sprintf("weights: %s has a weight which is less than zero. Please check your input string", pair))
+				}
+				weights[i] = f
+				total += f
+				found = true
+			}
+		}
+		if !found {
+			return nil, InvalidArgumentError(fmt.Sprintf("weights: %s is not a known HTTP method. Use any of %s", kv[0], strings.Join(HTTPMethods, ", ")))
+		}
+	}
+	if total <= 0 {
+		return nil, InvalidArgumentError("weights: At least one method must have a weight greater than zero. Please check your input string")
+	}
+	return weights, nil
+}
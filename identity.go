@@ -0,0 +1,486 @@
+package moldova
+
+import (
+	crand "crypto/rand"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func username(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	style := opts["style"]
+	digits, err := opts.getInt("digits")
+	if err != nil {
+		return "", err
+	} else if digits < 0 {
+		return "", InvalidArgumentError("You have specified a number of digits which is not a number greater than or equal to zero. Please check your input string")
+	}
+	maxLength, err := opts.getInt("maxlength")
+	if err != nil {
+		return "", err
+	} else if maxLength < 0 {
+		return "", InvalidArgumentError("You have specified a maximum length which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["username"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "username", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	first, err := usernamePart(rnd, "firstname", FirstNames, oc, opts)
+	if err != nil {
+		return "", err
+	}
+	last, err := usernamePart(rnd, "lastname", LastNames, oc, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var result string
+	switch style {
+	case "firstlast":
+		result = first + last
+	case "flast":
+		result = first[:1] + last
+	case "first_last":
+		result = first + "_" + last
+	case "first.last":
+		result = first + "." + last
+	default:
+		return "", InvalidArgumentError(fmt.Sprintf("style: %s is not a known username style. Use one of firstlast, flast, first_last, or first.last", style))
+	}
+	for i := 0; i < digits; i++ {
+		result += string(rune('0' + rnd.Intn(10)))
+	}
+	if maxLength > 0 {
+		result = truncateRunes(result, maxLength)
+	}
+
+	// store it in the cache
+	ca := oc["username"]
+	cache := ca.([]string)
+	oc["username"] = append(cache, result)
+
+	return result, nil
+}
+
+// usernamePart returns the name to build a username from. If the option matching the
+// name type is set, it is the ordinal of a name that has already been generated,
+// otherwise a new name is picked.
+func usernamePart(rnd *rand.Rand, nameType string, names []*Name, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt(nameType)
+	if err != nil {
+		return "", err
+	}
+	var name string
+	if ord < 0 {
+		name = sanitizeUsername(names[rnd.Intn(len(names))].GetSpelling(English))
+	} else {
+		c := oc[nameType]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, nameType, len(cache))
+		}
+		name = sanitizeUsername(cache[ord])
+	}
+	if name == "" {
+		// The name was spelled entirely with characters that don't belong in a username
+		name = "user"
+	}
+	return name, nil
+}
+
+// sanitizeUsername lower cases the name, and removes anything other than ascii letters
+// and digits
+func sanitizeUsername(name string) string {
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, strings.ToLower(name))
+}
+
+// The character classes a password is built from
+const (
+	passwordUpper   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	passwordLower   = "abcdefghijklmnopqrstuvwxyz"
+	passwordDigits  = "0123456789"
+	passwordSymbols = "!@#$%^&*()-_=+[]{};:,.<>?/~"
+)
+
+// password generates a password meeting the minimum number of characters from each
+// class. Since these resemble secrets, they are drawn from crypto/rand rather than the
+// random source of the Callstack, and so are not affected by it's seed.
+func password(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	length, err := opts.getInt("length")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["password"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "password", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	if length < 0 {
+		return "", InvalidArgumentError("length: You have specified a length which is not a number greater than or equal to zero. Please check your input string")
+	}
+	classes := []struct {
+		option string
+		chars  string
+		min    int
+	}{
+		{"upper", passwordUpper, 0},
+		{"lower", passwordLower, 0},
+		{"digits", passwordDigits, 0},
+		{"symbols", passwordSymbols, 0},
+	}
+	// Check the minimum counts fit in the length before making room for the password
+	total := 0
+	for i, class := range classes {
+		min, err := opts.getInt(class.option)
+		if err != nil {
+			return "", err
+		} else if min < 0 {
+			return "", InvalidArgumentError(fmt.Sprintf("%s: You have specified a minimum count which is not a number greater than or equal to zero. Please check your input string", class.option))
+		}
+		classes[i].min = min
+		total += min
+	}
+	if total > length {
+		return "", InvalidArgumentError(fmt.Sprintf("The minimum counts for the password add up to %d, which is more than the length of %d. Please check your input string", total, length))
+	}
+
+	// Start with the minimum number of characters from each class
+	b := make([]byte, 0, length)
+	for _, class := range classes {
+		for i := 0; i < class.min; i++ {
+			c, err := cryptoChar(class.chars)
+			if err != nil {
+				return "", err
+			}
+			b = append(b, c)
+		}
+	}
+	// Fill in the rest from any class
+	for len(b) < length {
+		c, err := cryptoChar(passwordUpper + passwordLower + passwordDigits + passwordSymbols)
+		if err != nil {
+			return "", err
+		}
+		b = append(b, c)
+	}
+	// Shuffle, so the required characters are not always at the front
+	for i := len(b) - 1; i > 0; i-- {
+		j, err := cryptoIntn(i + 1)
+		if err != nil {
+			return "", err
+		}
+		b[i], b[j] = b[j], b[i]
+	}
+	result := string(b)
+
+	// store it in the cache
+	ca := oc["password"]
+	cache := ca.([]string)
+	oc["password"] = append(cache, result)
+
+	return result, nil
+}
+
+// cryptoIntn returns a number from 0 to n, not including n, from crypto/rand
+func cryptoIntn(n int) (int, error) {
+	i, err := crand.Int(crand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
+
+// cryptoChar returns a single character from chars, chosen with crypto/rand
+func cryptoChar(chars string) (byte, error) {
+	i, err := cryptoIntn(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+// age generates a birthdate for someone whose age today falls within the bounds, and
+// writes out either their age, their birth year, or the birthdate itself. The birthdate
+// is what goes in the cache, so that later ordinals can write it out in another format
+// and still agree with each other.
+func age(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	min, err := opts.getInt("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getInt("max")
+	if err != nil {
+		return "", err
+	}
+	format := opts["format"]
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if ord >= 0 {
+		c := oc["age"]
+		cache := c.([]time.Time)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "age", len(cache))
+		}
+		return formatAge(cache[ord], today, format)
+	}
+
+	if min < 0 {
+		return "", InvalidArgumentError("You have specified a minimum age which is not a number greater than or equal to zero. Please check your input string")
+	} else if min > max {
+		return "", InvalidArgumentError("You cannot generate a random age whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+
+	years := min + rnd.Intn(max-min+1)
+	// The latest birthdate for someone this age is exactly that many years ago, and the
+	// earliest is the day after one more year ago. Leap days can push either of these
+	// out by a day, so they are walked back into range.
+	latest := today.AddDate(-years, 0, 0)
+	for ageOn(latest, today) < years {
+		latest = latest.AddDate(0, 0, -1)
+	}
+	earliest := today.AddDate(-years-1, 0, 1)
+	for ageOn(earliest, today) > years {
+		earliest = earliest.AddDate(0, 0, 1)
+	}
+	days := int(latest.Sub(earliest).Hours() / 24)
+	birthdate := earliest.AddDate(0, 0, rnd.Intn(days+1))
+
+	// store it in the cache
+	ca := oc["age"]
+	cache := ca.([]time.Time)
+	oc["age"] = append(cache, birthdate)
+
+	return formatAge(birthdate, today, format)
+}
+
+// ageOn returns how many full years old someone born on the birthdate is on the given day
+func ageOn(birthdate time.Time, day time.Time) int {
+	years := day.Year() - birthdate.Year()
+	if day.Month() < birthdate.Month() || (day.Month() == birthdate.Month() && day.Day() < birthdate.Day()) {
+		years--
+	}
+	return years
+}
+
+func formatAge(birthdate time.Time, today time.Time, format string) (string, error) {
+	switch format {
+	case "age":
+		return strconv.Itoa(ageOn(birthdate, today)), nil
+	case "birthyear":
+		return strconv.Itoa(birthdate.Year()), nil
+	case "birthdate":
+		return birthdate.Format("2006-01-02"), nil
+	}
+	return "", InvalidArgumentError(fmt.Sprintf("format: %s is not a known age format. Use one of age, birthyear, or birthdate", format))
+}
+
+// gender is kept in the cache, so that a later token can write the same value in a
+// different format
+type gender struct {
+	value    string
+	position int
+}
+
+// genderAbbreviations are the short forms of the default genders which aren't just the
+// first letter of the value. X is the marker used on passports.
+var genderAbbreviations = map[string]string{"nonbinary": "X"}
+
+func genderToken(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format := opts["format"]
+	if format != "long" && format != "short" && format != "numeric" {
+		return "", InvalidArgumentError(fmt.Sprintf("format: %s is not one of long, short, or numeric. Please check your input string", format))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["gender"]
+		cache := c.([]*gender)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "gender", len(cache))
+		}
+		return formatGender(cache[ord], format), nil
+	}
+
+	values := strings.Split(opts["values"], ",")
+	for _, v := range values {
+		if v == "" {
+			return "", InvalidArgumentError("values: Each value must be at least one character long. Please check your input string")
+		}
+	}
+	i := rnd.Intn(len(values))
+	if w := opts["weights"]; w != "" {
+		weights, err := genderWeights(w, len(values))
+		if err != nil {
+			return "", err
+		}
+		i = weightedIndex(rnd, weights)
+	}
+	g := &gender{value: values[i], position: i + 1}
+
+	// store it in the cache
+	ca := oc["gender"]
+	cache := ca.([]*gender)
+	oc["gender"] = append(cache, g)
+
+	return formatGender(g, format), nil
+}
+
+func formatGender(g *gender, format string) string {
+	switch format {
+	case "short":
+		if s, ok := genderAbbreviations[strings.ToLower(g.value)]; ok {
+			return s
+		}
+		r := []rune(g.value)
+		return strings.ToUpper(string(r[0]))
+	case "numeric":
+		return strconv.Itoa(g.position)
+	}
+	return g.value
+}
+
+func genderWeights(w string, count int) ([]float64, error) {
+	parts := strings.Split(w, ",")
+	if len(parts) != count {
+		return nil, InvalidArgumentError(fmt.Sprintf("weights: There are %d weights for %d values. Please check your input string", len(parts), count))
+	}
+	weights := make([]float64, count)
+	total := 0.0
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil, err
+		} else if f < 0 {
+			return nil, InvalidArgumentError(fmt.Sprintf("weights: %s is less than zero. Please check your input string", p))
+		}
+		weights[i] = f
+		total += f
+	}
+	if total <= 0 {
+		return nil, InvalidArgumentError("weights: At least one value must have a weight greater than zero. Please check your input string")
+	}
+	return weights, nil
+}
+
+func ssn(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format := opts["format"]
+	if format != "dashed" && format != "plain" {
+		return "", InvalidArgumentError(fmt.Sprintf("format: %s is not one of dashed or plain. Please check your input string", format))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["ssn"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "ssn", len(cache))
+		}
+		return formatSSN(cache[ord], format), nil
+	}
+
+	// The SSA never assigns an area of 000, 666, or 900 and above, a group of 00, or a
+	// serial of 0000
+	area := 1 + rnd.Intn(898)
+	if area >= 666 {
+		area++
+	}
+	group := 1 + rnd.Intn(99)
+	serial := 1 + rnd.Intn(9999)
+	result := fmt.Sprintf("%03d%02d%04d", area, group, serial)
+
+	// store it in the cache
+	ca := oc["ssn"]
+	cache := ca.([]string)
+	oc["ssn"] = append(cache, result)
+
+	return formatSSN(result, format), nil
+}
+
+func formatSSN(s string, format string) string {
+	if format == "dashed" {
+		return s[:3] + "-" + s[3:5] + "-" + s[5:]
+	}
+	return s
+}
+
+func bloodtype(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format := opts["format"]
+	if format != "full" && format != "abo" {
+		return "", InvalidArgumentError(fmt.Sprintf("format: %s is not one of full or abo. Please check your input string", format))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["bloodtype"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "bloodtype", len(cache))
+		}
+		return formatBloodType(cache[ord], format), nil
+	}
+
+	weights := make([]float64, len(BloodTypes))
+	for i, b := range BloodTypes {
+		weights[i] = b.Weight
+	}
+	result := BloodTypes[weightedIndex(rnd, weights)].Value
+
+	// store it in the cache
+	ca := oc["bloodtype"]
+	cache := ca.([]string)
+	oc["bloodtype"] = append(cache, result)
+
+	return formatBloodType(result, format), nil
+}
+
+// formatBloodType writes the blood type with it's Rh factor, or only the ABO group
+func formatBloodType(b string, format string) string {
+	if format == "abo" {
+		return strings.TrimRight(b, "+-")
+	}
+	return b
+}
@@ -0,0 +1,134 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// measureUnit is a unit of measurement. Every unit of a quantity, such as temperature,
+// converts to the same base unit with base = value*scale + offset.
+type measureUnit struct {
+	quantity string
+	symbol   string
+	scale    float64
+	offset   float64
+}
+
+// measureUnits are the units {measure} knows, by name. The base units are celsius,
+// meters, and kilograms.
+var measureUnits = map[string]measureUnit{
+	"celsius":    {"temperature", "°C", 1, 0},
+	"fahrenheit": {"temperature", "°F", 5.0 / 9, -160.0 / 9},
+	"kelvin":     {"temperature", "K", 1, -273.15},
+	"millimeter": {"length", "mm", 0.001, 0},
+	"centimeter": {"length", "cm", 0.01, 0},
+	"meter":      {"length", "m", 1, 0},
+	"kilometer":  {"length", "km", 1000, 0},
+	"inch":       {"length", "in", 0.0254, 0},
+	"foot":       {"length", "ft", 0.3048, 0},
+	"mile":       {"length", "mi", 1609.344, 0},
+	"gram":       {"mass", "g", 0.001, 0},
+	"kilogram":   {"mass", "kg", 1, 0},
+	"ounce":      {"mass", "oz", 0.028349523125, 0},
+	"pound":      {"mass", "lb", 0.45359237, 0},
+}
+
+// measurement is a value in the base unit of it's quantity, so that a reference can
+// write it in any unit of the same quantity
+type measurement struct {
+	quantity string
+	base     float64
+}
+
+func measure(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	unit, err := getMeasureUnit(opts["unit"])
+	if err != nil {
+		return "", err
+	}
+	prec, err := opts.getInt("precision")
+	if err != nil {
+		return "", err
+	} else if prec < 0 {
+		return "", InvalidArgumentError("You have specified a precision which is not a number greater than or equal to zero. Please check your input string")
+	}
+	symbol, err := opts.getBool("symbol")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["measure"]
+		cache := c.([]*measurement)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "measure", len(cache))
+		}
+		m := cache[ord]
+		if m.quantity != unit.quantity {
+			return "", InvalidArgumentError(fmt.Sprintf("unit: %s is a unit of %s, but the measure at ordinal %d is a %s. Please check your input string", opts["unit"], unit.quantity, ord, m.quantity))
+		}
+		return formatMeasure(m.base, unit, prec, symbol), nil
+	}
+
+	// The range is in the unit the value is written in, unless the in option says
+	// otherwise, in which case it's converted
+	in := unit
+	if opts["in"] != "" {
+		if in, err = getMeasureUnit(opts["in"]); err != nil {
+			return "", err
+		} else if in.quantity != unit.quantity {
+			return "", InvalidArgumentError(fmt.Sprintf("in: %s is a unit of %s, which can't be converted to %s. Please check your input string", opts["in"], in.quantity, opts["unit"]))
+		}
+	}
+	min, err := opts.getFloat("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getFloat("max")
+	if err != nil {
+		return "", err
+	}
+	if min > max {
+		return "", InvalidArgumentError("You cannot generate a random number whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+	n := min + unitFloat(rnd, true)*(max-min)
+	if n > max {
+		n = max
+	}
+	m := &measurement{quantity: in.quantity, base: n*in.scale + in.offset}
+
+	// store it in the cache
+	ca := oc["measure"]
+	cache := ca.([]*measurement)
+	oc["measure"] = append(cache, m)
+
+	return formatMeasure(m.base, unit, prec, symbol), nil
+}
+
+// getMeasureUnit returns the unit with the given name
+func getMeasureUnit(name string) (measureUnit, error) {
+	unit, ok := measureUnits[strings.ToLower(name)]
+	if !ok {
+		return measureUnit{}, InvalidArgumentError(fmt.Sprintf("unit: %s is not a known unit. Use one of celsius, fahrenheit, kelvin, millimeter, centimeter, meter, kilometer, inch, foot, mile, gram, kilogram, ounce, or pound", name))
+	}
+	return unit, nil
+}
+
+// formatMeasure converts the value from the base unit to the given unit, and writes it
+// with prec decimal places. With symbol set, the unit's symbol follows it, after a space
+// unless it's a degree sign, as the SI writes them.
+func formatMeasure(base float64, unit measureUnit, prec int, symbol bool) string {
+	s := strconv.FormatFloat((base-unit.offset)/unit.scale, 'f', prec, 64)
+	if !symbol {
+		return s
+	}
+	if strings.HasPrefix(unit.symbol, "°") {
+		return s + unit.symbol
+	}
+	return s + " " + unit.symbol
+}
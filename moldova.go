@@ -6,13 +6,19 @@ package moldova
 import (
 	"bytes"
 	crand "crypto/rand"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"log"
+	"math"
 	"math/rand"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	// I want to keep files that only exist to help provide sources of data or are
 	// helpers to Moldova in their own subdirectory, for organization reasons. Go
@@ -26,6 +32,35 @@ import (
 type cmdOptions map[string]string
 type objectCache map[string]interface{}
 
+// namedKey is the entry in the objectCache holding values stored with the as option, so
+// that other tokens can refer back to them by name with the ref option
+const namedKey = "named"
+
+// rowKey is the entry in the objectCache holding the number of the row being written,
+// counting from 1
+const rowKey = "row"
+
+// rangesKey is the entry in the objectCache holding the sets of Unicode ranges added to
+// the Callstack with AddRanges, by name
+const rangesKey = "ranges"
+
+// keyedKey is the entry in the objectCache holding the values of tokens with the key
+// option, by key, for WriteMap
+const keyedKey = "keyed"
+
+// distinctKey is the entry in the objectCache holding the values written in this row by
+// the tokens with the distinct option, by the name of the token. It's only made once one
+// is written.
+const distinctKey = "distinct"
+
+// nestedKey is the entry in the objectCache holding the Callstack of the nested template
+// of the token being written, such as the tpl of {repeat}
+const nestedKey = "nested"
+
+// nestedErrorsKey is the entry in the objectCache holding the errors collected while
+// writing nested templates, to be returned along with those of the Callstack itself
+const nestedErrorsKey = "nestederrors"
+
 // TokenWriter is a closure that wraps a call to generate random data, and places
 // the result into the provided buffer
 type tokenWriter func(*bytes.Buffer, objectCache) error
@@ -34,16 +69,310 @@ type tokenWriter func(*bytes.Buffer, objectCache) error
 // parsed template. Callstack is a FIFO implementation, making it more akin to a queue
 // than a stack.
 type Callstack struct {
-	stack []tokenWriter
-	cache objectCache
+	stack  []tokenWriter
+	tokens []*token
+	cache  objectCache
+	rand   *rand.Rand
+	secure *rand.Rand
+	escape Escaper
+	rows   int
+	ranges map[string][][]int
+	// seen holds every value written by each token with the unique option
+	seen map[*token]map[string]bool
+	// collect is whether Write keeps going past tokens which fail, writing the
+	// placeholder in their place
+	collect     bool
+	placeholder string
+	// depth is how many templates this one is nested inside of
+	depth int
+	// changes are the changes made to the tokens since the Callstack was built, such as
+	// new defaults, so that they can be made again to a nested template built later
+	changes []func(*Callstack) error
+}
+
+// maxNesting is how many templates deep a template can be nested inside of others. It
+// stops a default tpl holding a {repeat} from nesting itself forever.
+const maxNesting = 16
+
+// uniqueTries is how many times a token with the unique option is generated, looking
+// for a value it hasn't written before, before giving up
+const uniqueTries = 100
+
+// Escaper is applied to the value of each token before it is written, so that the values
+// cannot break the format of the text around them. The text of the template itself is
+// left as it is.
+type Escaper func(string) string
+
+// token is a single token parsed out of the template. The options it was given in the
+// template are kept apart from the full set, so that changing a default only affects
+// the options it did not set itself.
+type token struct {
+	name     string
+	pos      int
+	explicit cmdOptions
+	opts     cmdOptions
+	// sub is the Callstack of the nested template in the tpl option, if the token has one
+	sub *Callstack
+}
+
+// wrapError adds the name and position of the token to an error it ran into, so that it
+// can be found in a long template
+func (t *token) wrapError(err error) error {
+	if _, ok := err.(UnsupportedTokenError); ok {
+		// These already say where the token is
+		return err
+	}
+	return InvalidArgumentError(fmt.Sprintf("token %q at offset %d: %s", t.name, t.pos, err))
 }
 
 func newCallstack() *Callstack {
 	return &Callstack{
-		stack: make([]tokenWriter, 0),
+		stack:  make([]tokenWriter, 0),
+		tokens: make([]*token, 0),
+		rand:   rand.New(rand.NewSource(time.Now().UnixNano())),
+		secure: rand.New(CryptoSource{}),
+	}
+}
+
+// CryptoSource is a rand.Source which draws from crypto/rand. It is much slower than the
+// default source, but the values can't be predicted. Pass it to SetSource to use it for
+// every token in a Callstack, or set the secure option on a single token. Since it can't
+// be seeded, Seed does nothing.
+type CryptoSource struct{}
+
+// Int63 returns a non-negative random 63-bit integer as an int64
+func (CryptoSource) Int63() int64 {
+	return int64(CryptoSource{}.Uint64() & (1<<63 - 1))
+}
+
+// Uint64 returns a random 64-bit integer
+func (CryptoSource) Uint64() uint64 {
+	b := randomBytes(8)
+	return uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32 |
+		uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])
+}
+
+// Seed does nothing, as crypto/rand can't be seeded
+func (CryptoSource) Seed(int64) {}
+
+// Seed will reset the source of random values for the Callstack to a fixed point, so
+// that the same seed will always produce the same sequence of results. Tokens which rely
+// on crypto/rand, such as {guid}, are not affected.
+func (c *Callstack) Seed(seed int64) {
+	c.rand.Seed(seed)
+}
+
+// SetSource will replace the source of random values for the Callstack. By default, each
+// Callstack has it's own source, seeded with the time it was created.
+func (c *Callstack) SetSource(src rand.Source) {
+	c.rand = rand.New(src)
+}
+
+// SetEscaper will apply the Escaper to the value of every token in the Callstack. Use
+// nil to write the values as they are, which is the default.
+func (c *Callstack) SetEscaper(e Escaper) {
+	c.escape = e
+}
+
+// SetCollectErrors changes what Write does when a token fails. By default it stops, and
+// returns the error. When collecting errors, it writes the placeholder in place of the
+// token and keeps going, then returns a WriteErrors holding every error once the rest of
+// the result is written. This suits bulk generation, where one bad value shouldn't stop
+// the rest. Since a failed value isn't stored, ordinals referring to it fail as well.
+func (c *Callstack) SetCollectErrors(collect bool, placeholder string) {
+	c.collect = collect
+	c.placeholder = placeholder
+	for _, t := range c.tokens {
+		if t.sub != nil {
+			t.sub.SetCollectErrors(collect, placeholder)
+		}
 	}
 }
 
+// SetNullRate will replace the value of every token in the Callstack with the value, at
+// the given rate from 0 to 1, to simulate data with values missing across many columns at
+// once. Each token is replaced on it's own, so a rate of 0.1 leaves around a tenth of the
+// values in each row missing. It sets the nullprob and nullvalue options of every token
+// which does not set them itself, so a token can opt out with nullprob:0. Since the
+// choice is made with the random values of the Callstack, Seed makes it repeatable.
+func (c *Callstack) SetNullRate(rate float64, value string) error {
+	if rate < 0 || rate > 1 {
+		return InvalidArgumentError(fmt.Sprintf("The null rate %g is not a number from 0 to 1", rate))
+	}
+	for _, t := range c.tokens {
+		if _, ok := t.explicit["nullprob"]; !ok {
+			t.opts["nullprob"] = strconv.FormatFloat(rate, 'g', -1, 64)
+		}
+		if _, ok := t.explicit["nullvalue"]; !ok {
+			t.opts["nullvalue"] = value
+		}
+		if t.sub != nil {
+			if err := t.sub.SetNullRate(rate, value); err != nil {
+				return err
+			}
+		}
+	}
+	c.changes = append(c.changes, func(sub *Callstack) error {
+		return sub.SetNullRate(rate, value)
+	})
+	return nil
+}
+
+// AddRanges will add a set of Unicode ranges to the Callstack under the given name, for
+// {unicode} to generate characters from with the ranges option. Like PrintableRanges,
+// each range is the first code point followed by the one after the last. Adding a set
+// under a name which is already in use replaces it.
+func (c *Callstack) AddRanges(name string, ranges [][]int) error {
+	if name == "" {
+		return InvalidArgumentError("You must provide a name for the set of ranges")
+	}
+	if len(ranges) == 0 {
+		return InvalidArgumentError(fmt.Sprintf("The set of ranges %s must have at least one range in it", name))
+	}
+	set := make([][]int, len(ranges))
+	for i, r := range ranges {
+		if len(r) != 2 {
+			return InvalidArgumentError(fmt.Sprintf("Range %d of %s must have a first and last code point, but has %d values", i, name, len(r)))
+		}
+		if r[0] < 0 || r[0] >= r[1] || r[1] > utf8.MaxRune+1 {
+			return InvalidArgumentError(fmt.Sprintf("Range %d of %s, %#x to %#x, is not a range of Unicode code points", i, name, r[0], r[1]))
+		}
+		// Copy the range, so the caller changing theirs later can't affect the Callstack
+		set[i] = []int{r[0], r[1]}
+	}
+	if c.ranges == nil {
+		c.ranges = make(map[string][][]int)
+	}
+	c.ranges[name] = set
+	return nil
+}
+
+// EscapeCSV is an Escaper for values written as fields of a CSV file. Values holding a
+// comma, quote, or line break are quoted, following RFC 4180.
+func EscapeCSV(v string) string {
+	if v == "" {
+		return v
+	}
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	// Writing to a bytes.Buffer can't fail
+	w.Write([]string{v})
+	w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// EscapeSQL is an Escaper for values written inside of a quoted SQL string literal, such
+// as '{lastname}'. Single quotes are doubled, following the SQL standard, so a name like
+// O'Brien can't end the literal early.
+func EscapeSQL(v string) string {
+	return strings.Replace(v, "'", "''", -1)
+}
+
+// EscapeJSON is an Escaper for values written inside of a quoted JSON string, as in
+// \{"name": "{lastname}"\}. Quotes, backslashes, and control characters are escaped with
+// encoding/json, following RFC 8259. Characters like < and > are left as they are.
+func EscapeJSON(v string) string {
+	b := &bytes.Buffer{}
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	// Encoding a string can't fail
+	e.Encode(v)
+	// Drop the quotes around the string, and the newline Encode writes after it
+	quoted := b.String()
+	return quoted[1 : len(quoted)-2]
+}
+
+// markdownEscaper escapes the characters which would end a markdown table cell early
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// EscapeMarkdown is an Escaper for values written as the cells of a markdown table. Pipes
+// are escaped, so they can't start a new cell, and line breaks become <br>, as a table
+// row must fit on a single line.
+func EscapeMarkdown(v string) string {
+	return markdownEscaper.Replace(v)
+}
+
+// SetDefault will change the default value of an option for every instance of the token
+// in the Callstack which does not set that option itself. It returns an error if the
+// token or option is not known, or if the option must be a number and the value isn't
+// one. Whether the value is in range is checked when the Callstack is written.
+func (c *Callstack) SetDefault(tokenName string, option string, value string) error {
+	if _, ok := defaultOptions[tokenName]; !ok {
+		return UnsupportedTokenError(fmt.Sprintf("the token %s is not recognized, check for typos", tokenName))
+	}
+	if !knownOption(tokenName, option) {
+		return InvalidArgumentError(fmt.Sprintf("%s is not a known option for the token %s", option, tokenName))
+	}
+	if err := checkOptionKind(tokenName, option, value); err != nil {
+		return InvalidArgumentError(fmt.Sprintf("the default for the option %s of the token %s %s", option, tokenName, err))
+	}
+	for _, t := range c.tokens {
+		if t.name != tokenName {
+			continue
+		}
+		if _, ok := t.explicit[option]; !ok {
+			t.opts[option] = value
+			if templateOptions[option] {
+				sub, err := c.nest(value)
+				if err != nil {
+					return err
+				}
+				t.sub = sub
+			}
+		}
+	}
+	for _, t := range c.tokens {
+		if t.sub != nil {
+			if err := t.sub.SetDefault(tokenName, option, value); err != nil {
+				return err
+			}
+		}
+	}
+	c.changes = append(c.changes, func(sub *Callstack) error {
+		return sub.SetDefault(tokenName, option, value)
+	})
+	return nil
+}
+
+// nest builds the Callstack of a template nested inside of this one, and makes every
+// change made to this one to it as well, so the nested template is written the same way.
+// The Escaper is left to this one, which escapes everything the nested template writes.
+func (c *Callstack) nest(inputTemplate string) (*Callstack, error) {
+	sub, err := buildCallstack(inputTemplate, c.depth+1)
+	if err != nil {
+		return nil, err
+	}
+	sub.SetCollectErrors(c.collect, c.placeholder)
+	for _, change := range c.changes {
+		if err := change(sub); err != nil {
+			return nil, err
+		}
+	}
+	return sub, nil
+}
+
+// resolveToken generates the value of the token, cut down to the maxlength option, and
+// stores it under the as option
+func (c *Callstack) resolveToken(rnd *rand.Rand, cache objectCache, t *token) (string, error) {
+	val, err := resolveWord(rnd, cache, t.name, t.pos, t.opts)
+	if err != nil {
+		return "", err
+	}
+	remember(cache, t.name, val, t.opts)
+	return truncate(val, t.opts)
+}
+
+// see records that the token has written the value, for the unique option
+func (c *Callstack) see(t *token, val string) {
+	if c.seen == nil {
+		c.seen = make(map[*token]map[string]bool)
+	}
+	if c.seen[t] == nil {
+		c.seen[t] = make(map[string]bool)
+	}
+	c.seen[t][val] = true
+}
+
 // Push will place the given tokenWriter function onto the stack. The first function
 // placed onto the stack will be the first one called when Write is called
 func (c *Callstack) Push(t tokenWriter) {
@@ -53,52 +382,409 @@ func (c *Callstack) Push(t tokenWriter) {
 // Write will take a bytes.Buffer pointer and fill it with the results of calling
 // each known function on the Callstack.
 func (c *Callstack) Write(result *bytes.Buffer) error {
-	c.cache = newObjectCache()
+	c.rows++
+	// Only tokens use the cache, so there's no need to build one when there are none
+	if len(c.tokens) > 0 {
+		c.cache = newObjectCache()
+		c.cache[rowKey] = c.rows
+		c.cache[rangesKey] = c.ranges
+	}
+	return c.writeStack(result)
+}
+
+// writeNested writes the Callstack of a nested template once, as part of the row being
+// written with the cache of the template around it. The values stored with the as option
+// are shared between the two, so each can refer to the other's.
+func (c *Callstack) writeNested(result *bytes.Buffer, outer objectCache) error {
+	c.rows++
+	if len(c.tokens) > 0 {
+		c.cache = newObjectCache()
+		c.cache[rowKey] = c.rows
+		c.cache[rangesKey] = outer[rangesKey]
+		c.cache[namedKey] = outer[namedKey]
+	}
+	return c.writeStack(result)
+}
+
+// writeStack calls each function on the stack in order, with the cache of the row
+func (c *Callstack) writeStack(result *bytes.Buffer) error {
+	var errs WriteErrors
 	for _, f := range c.stack {
 		if err := f(result, c.cache); err != nil {
+			if !c.collect {
+				return err
+			}
+			result.WriteString(c.placeholder)
+			errs = append(errs, err)
+		}
+		if nested, ok := c.cache[nestedErrorsKey].(WriteErrors); ok {
+			errs = append(errs, nested...)
+			delete(c.cache, nestedErrorsKey)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// WriteMap will write the Callstack once, and return the value of each token with the key
+// option, by key, rather than the whole result. A template of {guid:key:id},{int:key:qty}
+// gives a map of id and qty, ready to fill in a struct without parsing the result again.
+// The values are as they would be written, but without the Escaper, and the text around
+// the tokens is left out. Tokens inside of {repeat} are not included. When the Callstack
+// collects errors, the map is returned along with the WriteErrors, with the placeholder
+// for each token which failed.
+func (c *Callstack) WriteMap() (map[string]string, error) {
+	err := c.Write(&bytes.Buffer{})
+	if _, collected := err.(WriteErrors); err != nil && !collected {
+		return nil, err
+	}
+	if len(c.tokens) == 0 {
+		return map[string]string{}, nil
+	}
+	// The cache is made again for each result, so the map can be handed out as it is
+	return c.cache[keyedKey].(map[string]string), err
+}
+
+// WriteAll will write the results of the Callstack n times to w, with sep between each
+// of them. An empty sep is taken to be a newline. Each result is built in a buffer which
+// is reused, and written to w once it is whole, so a result which fails part way through
+// is never written. The first error from the Callstack or from w stops the writing and
+// is returned. When the Callstack collects errors, results with placeholders in them are
+// written like any other, and every error from them is returned together at the end.
+func (c *Callstack) WriteAll(w io.Writer, n int, sep string) error {
+	if sep == "" {
+		sep = "\n"
+	}
+	var errs WriteErrors
+	err := c.WriteEach(n, func(i int, result *bytes.Buffer, err error) error {
+		if err != nil {
+			collected, ok := err.(WriteErrors)
+			if !ok {
+				return err
+			}
+			errs = append(errs, collected...)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, sep); err != nil {
+				return err
+			}
+		}
+		_, err = result.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// WriteEach will write the Callstack n times, and call fn with the index of each result,
+// the result, and the error from writing it, if any, so the caller can decide what to do
+// with each one. The buffer is reused for the next result once fn returns. The first
+// error fn returns stops the writing, and is returned.
+func (c *Callstack) WriteEach(n int, fn func(i int, result *bytes.Buffer, err error) error) error {
+	result := &bytes.Buffer{}
+	for i := 0; i < n; i++ {
+		result.Reset()
+		if err := fn(i, result, c.Write(result)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// Stores a value under the given name, so it can be referred to later. Values with no
+// name are not stored.
+func (oc objectCache) setNamed(name string, v interface{}) {
+	if name == "" {
+		return
+	}
+	named := oc[namedKey].(map[string]interface{})
+	named[name] = v
+}
+
+// Returns the value stored under the given name
+func (oc objectCache) getNamed(name string) (interface{}, error) {
+	named := oc[namedKey].(map[string]interface{})
+	v, ok := named[name]
+	if !ok {
+		return nil, InvalidArgumentError(fmt.Sprintf("ref: %s has not yet been defined with the as option. Please check your input string", name))
+	}
+	return v, nil
+}
+
+// Returns whether a token of the given name with the distinct option has already written
+// the value in this row
+func (oc objectCache) distinctSeen(name string, val string) bool {
+	seen, _ := oc[distinctKey].(map[string]map[string]bool)
+	return seen[name][val]
+}
+
+// Records that a token of the given name with the distinct option has written the value
+// in this row
+func (oc objectCache) seeDistinct(name string, val string) {
+	seen, _ := oc[distinctKey].(map[string]map[string]bool)
+	if seen == nil {
+		seen = make(map[string]map[string]bool)
+		oc[distinctKey] = seen
+	}
+	if seen[name] == nil {
+		seen[name] = make(map[string]bool)
+	}
+	seen[name][val] = true
+}
+
+// isReference returns whether the options make the token write a value which was already
+// generated, with either the ordinal or ref option, rather than a new one
+func isReference(opts cmdOptions) bool {
+	if ord, err := opts.getInt("ordinal"); err == nil && ord >= 0 {
+		return true
+	}
+	return opts["ref"] != ""
+}
+
+// Returns the value stored under the given name as text, for options such as from and
+// seedref. Numbers are written out in full, without any formatting options.
+func (oc objectCache) getNamedText(option string, name string) (string, error) {
+	v, err := oc.getNamed(name)
+	if err != nil {
+		return "", err
+	}
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case int:
+		return strconv.Itoa(t), nil
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64), nil
+	}
+	return "", InvalidArgumentError(fmt.Sprintf("%s: %s does not refer to text. Please check your input string", option, name))
+}
+
+// ordinalError explains why an ordinal can't be used. Each type of token numbers it's own
+// values from 0, starting over on each line, so an ordinal can't refer back to a token of
+// a different type, as in {int} {guid:ordinal:0}. count is how many values of the type
+// have been generated so far in the line.
+func ordinalError(ord int, token string, count int) error {
+	var found string
+	switch count {
+	case 0:
+		found = fmt.Sprintf("no {%s} has been generated yet in this line", token)
+	case 1:
+		found = fmt.Sprintf("only 1 {%s} has been generated so far in this line, at ordinal 0", token)
+	default:
+		found = fmt.Sprintf("only %d {%s} have been generated so far in this line, at ordinals 0 to %d", count, token, count-1)
+	}
+	return InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for {%s}: %s. Ordinals count the values of each type of token separately, so they can only refer back to another {%s}. Please check your input string", ord, token, found, token))
+}
+
+// runeLength returns the length of v in runes. Every option which sets a length counts
+// it this way, so that a length of 5 is 5 characters no matter which script they're in.
+func runeLength(v string) int {
+	return utf8.RuneCountInString(v)
+}
+
+// truncateRunes cuts v down to at most n runes, never splitting a character in half
+func truncateRunes(v string, n int) string {
+	i := 0
+	for pos := range v {
+		if i == n {
+			return v[:pos]
+		}
+		i++
+	}
+	return v
+}
+
+// applyCase changes the case of the value to match the case option, "up" or "down",
+// and leaves it as is for anything else
+func applyCase(v string, cCase string) string {
+	if cCase == "up" {
+		return strings.ToUpper(v)
+	} else if cCase == "down" {
+		return strings.ToLower(v)
+	}
+	return v
+}
+
 // Returns option value as integer
 func (cmd cmdOptions) getInt(n string) (int, error) {
 	v := cmd[n]
 	return strconv.Atoi(v)
 }
 
+// Returns option value as bool
+func (cmd cmdOptions) getBool(n string) (bool, error) {
+	v := cmd[n]
+	return strconv.ParseBool(v)
+}
+
 // Returns option value as float64
 func (cmd cmdOptions) getFloat(n string) (float64, error) {
 	v := cmd[n]
 	return strconv.ParseFloat(v, 64)
 }
 
+// genericOptions are the options which every token accepts, on top of it's own
+var genericOptions = cmdOptions{"nullprob": "0", "nullvalue": "NULL", "secure": "false", "maxlength": "0", "prefix": "", "suffix": "", "as": "", "unique": "false", "key": "", "jsonescape": "false", "seed": "", "seedref": "", "distinct": "false"}
+
 var defaultOptions = map[string]cmdOptions{
-	"guid":      cmdOptions{"ordinal": "-1"},
-	"now":       cmdOptions{"ordinal": "-1", "format": "simple", "zone": "UTC"},
-	"time":      cmdOptions{"ordinal": "-1", "format": "simple", "min": "0", "max": "1455512165", "zone": "UTC"},
-	"int":       cmdOptions{"min": "0", "max": "100", "ordinal": "-1"},
-	"float":     cmdOptions{"min": "0.0", "max": "100.0", "ordinal": "-1"},
-	"ascii":     cmdOptions{"length": "2", "case": "down", "ordinal": "-1"},
-	"unicode":   cmdOptions{"length": "2", "case": "down", "ordinal": "-1"},
-	"country":   cmdOptions{"ordinal": "-1", "case": "up"},
+	"guid":      cmdOptions{"ordinal": "-1", "format": "dashed"},
+	"now":       cmdOptions{"ordinal": "-1", "format": "simple", "zone": "UTC", "truncate": ""},
+	"time":      cmdOptions{"ordinal": "-1", "format": "simple", "min": "0", "max": "1455512165", "zone": "UTC", "truncate": ""},
+	"int":       cmdOptions{"min": "0", "max": "100", "inclusive": "true", "step": "1", "ordinal": "-1", "as": "", "distribution": "uniform", "mean": "", "stddev": "", "count": "1", "sep": ","},
+	"float":     cmdOptions{"min": "0.0", "max": "100.0", "inclusive": "true", "ordinal": "-1", "format": "f", "precision": "6", "distribution": "uniform", "mean": "", "stddev": "", "quantize": "0", "count": "1", "sep": ",", "as": "", "ref": ""},
+	"ascii":     cmdOptions{"length": "2", "minlen": "0", "maxlen": "0", "case": "down", "ordinal": "-1"},
+	"unicode":   cmdOptions{"length": "2", "minlen": "0", "maxlen": "0", "case": "down", "ranges": "", "ordinal": "-1"},
+	"country":   cmdOptions{"ordinal": "-1", "case": "", "format": "iso2", "weight": "uniform", "as": ""},
 	"firstname": cmdOptions{"ordinal": "-1", "language": English},
 	"lastname":  cmdOptions{"ordinal": "-1", "language": English},
+	"currency":  cmdOptions{"ordinal": "-1", "code": "USD", "min": "0.0", "max": "1000.0"},
+	"weekday":   cmdOptions{"ordinal": "-1", "case": "", "abbrev": "false", "number": "false", "language": English},
+	"month":     cmdOptions{"ordinal": "-1", "case": "", "abbrev": "false", "number": "false", "language": English},
+
+	"streetaddress": cmdOptions{"ordinal": "-1", "country": "US", "as": "", "ref": ""},
+	"city":          cmdOptions{"ordinal": "-1", "country": "US", "as": "", "ref": ""},
+	"state":         cmdOptions{"ordinal": "-1", "country": "US", "as": "", "ref": ""},
+	"zipcode":       cmdOptions{"ordinal": "-1", "country": "US", "as": "", "ref": ""},
+
+	"company":  cmdOptions{"ordinal": "-1", "case": "", "legal": "true"},
+	"jobtitle": cmdOptions{"ordinal": "-1", "case": "", "level": "any"},
+	"username": cmdOptions{"ordinal": "-1", "style": "firstlast", "digits": "0", "maxlength": "0", "firstname": "-1", "lastname": "-1"},
+	"password": cmdOptions{"ordinal": "-1", "length": "12", "upper": "1", "lower": "1", "digits": "1", "symbols": "1"},
+	"age":      cmdOptions{"ordinal": "-1", "min": "18", "max": "90", "format": "age"},
+	"objectid": cmdOptions{"ordinal": "-1", "time": ""},
+	"semver":   cmdOptions{"ordinal": "-1", "maxmajor": "10", "maxminor": "20", "maxpatch": "50", "prerelease": "false", "build": "false"},
+
+	"httpstatus": cmdOptions{"ordinal": "-1", "class": "any"},
+	"httpmethod": cmdOptions{"ordinal": "-1", "weights": ""},
+
+	"duration":     cmdOptions{"ordinal": "-1", "min": "0s", "max": "24h", "resolution": "1s", "format": "string"},
+	"latlng":       cmdOptions{"ordinal": "-1", "minlat": "-90", "maxlat": "90", "minlng": "-180", "maxlng": "180", "precision": "6", "bbox": "", "as": ""},
+	"slug":         cmdOptions{"ordinal": "-1", "words": "3", "maxlength": "0", "from": ""},
+	"filename":     cmdOptions{"ordinal": "-1", "ext": "txt,pdf,jpg,png,csv,json", "words": "2", "case": "down", "path": "0"},
+	"emoji":        cmdOptions{"ordinal": "-1", "count": "1", "category": "any"},
+	"iban":         cmdOptions{"ordinal": "-1", "country": "DE"},
+	"isbn":         cmdOptions{"ordinal": "-1", "version": "13", "hyphenate": "false"},
+	"repeat":       cmdOptions{"ordinal": "-1", "count": "1", "sep": "", "tpl": ""},
+	"expr":         cmdOptions{"ordinal": "-1", "value": "", "as": ""},
+	"creditcard":   cmdOptions{"ordinal": "-1", "network": "any", "as": ""},
+	"cardtype":     cmdOptions{"ordinal": "-1", "ref": ""},
+	"gender":       cmdOptions{"ordinal": "-1", "format": "long", "values": "male,female,nonbinary", "weights": ""},
+	"port":         cmdOptions{"ordinal": "-1", "min": "1", "max": "65535", "preset": "any"},
+	"word":         cmdOptions{"ordinal": "-1", "minlen": "0", "maxlen": "0", "case": ""},
+	"nanoid":       cmdOptions{"ordinal": "-1", "size": "21", "alphabet": nanoidAlphabet},
+	"continent":    cmdOptions{"ordinal": "-1", "case": "", "ref": ""},
+	"rownum":       cmdOptions{"start": "1", "step": "1", "jitter": "0", "pad": "0"},
+	"bool":         cmdOptions{"ordinal": "-1", "probability": "0.5", "format": "word", "as": ""},
+	"maybe":        cmdOptions{"ref": "", "then": "", "else": ""},
+	"ssn":          cmdOptions{"ordinal": "-1", "format": "dashed"},
+	"hashtag":      cmdOptions{"ordinal": "-1", "count": "1", "words": "1", "from": ""},
+	"lorem":        cmdOptions{"ordinal": "-1", "unit": "word", "count": "1", "words": "8", "sentences": "4", "wrap": "", "language": Latin, "case": ""},
+	"vin":          cmdOptions{"ordinal": "-1"},
+	"licenseplate": cmdOptions{"ordinal": "-1", "region": "US-CA"},
+	"timezone":     cmdOptions{"ordinal": "-1", "ref": ""},
+	"status":       cmdOptions{"set": "order", "case": "", "ordinal": "-1"},
+	"bloodtype":    cmdOptions{"format": "full", "ordinal": "-1"},
+	"gitsha":       cmdOptions{"short": "false", "ordinal": "-1"},
+	"base36":       cmdOptions{"length": "8", "case": "down", "ordinal": "-1"},
+	"base62":       cmdOptions{"length": "8", "ordinal": "-1"},
+	"imei":         cmdOptions{"tac": "random", "ordinal": "-1"},
+	"hostname":     cmdOptions{"style": "words", "domain": "", "ordinal": "-1"},
+	"jwt":          cmdOptions{"alg": "HS256", "payload": "64", "ordinal": "-1"},
+	"fraction":     cmdOptions{"min": "0", "max": "1", "denominator": "4", "format": "fraction", "precision": "0", "ordinal": "-1"},
+	"percentage":   cmdOptions{"min": "0", "max": "100", "precision": "2", "symbol": "false", "ordinal": "-1"},
+	"ean13":        cmdOptions{"company": "", "ordinal": "-1"},
+	"upc":          cmdOptions{"company": "", "ordinal": "-1"},
+	"latency":      cmdOptions{"p50": "100", "p99": "1000", "precision": "0", "ordinal": "-1"},
+	"measure":      cmdOptions{"unit": "celsius", "in": "", "min": "0", "max": "100", "precision": "1", "symbol": "false", "ordinal": "-1"},
+	"macaddr":      cmdOptions{"vendor": "", "case": "down", "ordinal": "-1"},
+	"sentence":     cmdOptions{"min": "4", "max": "12", "distribution": "uniform", "mean": "", "stddev": "", "end": ".", "language": "english", "case": "", "ordinal": "-1"},
+	"money":        cmdOptions{"code": "USD", "locale": "en-US", "min": "0", "max": "1000", "ordinal": "-1"},
+	"callingcode":  cmdOptions{"format": "plus", "ref": "", "ordinal": "-1"},
 }
 
 func newObjectCache() objectCache {
 	return objectCache{
 		"guid":      make([]string, 0),
-		"now":       make([]string, 0),
-		"time":      make([]string, 0),
-		"country":   make([]string, 0),
+		"now":       make([]time.Time, 0),
+		"time":      make([]time.Time, 0),
+		"country":   make([]int, 0),
 		"unicode":   make([]string, 0),
 		"ascii":     make([]string, 0),
 		"int":       make([]int, 0),
 		"float":     make([]float64, 0),
 		"firstname": make([]string, 0),
 		"lastname":  make([]string, 0),
+		"currency":  make([]string, 0),
+		"weekday":   make([]int, 0),
+		"month":     make([]int, 0),
+
+		"streetaddress": make([]string, 0),
+		"city":          make([]string, 0),
+		"state":         make([]string, 0),
+		"zipcode":       make([]string, 0),
+
+		"company":  make([]string, 0),
+		"jobtitle": make([]string, 0),
+		"username": make([]string, 0),
+		"password": make([]string, 0),
+		"age":      make([]time.Time, 0),
+		"objectid": make([]string, 0),
+		"semver":   make([]string, 0),
+
+		"httpstatus": make([]int, 0),
+		"httpmethod": make([]string, 0),
+
+		"duration":     make([]time.Duration, 0),
+		"latlng":       make([]string, 0),
+		"slug":         make([]string, 0),
+		"filename":     make([]string, 0),
+		"emoji":        make([]string, 0),
+		"iban":         make([]string, 0),
+		"isbn":         make([]string, 0),
+		"repeat":       make([]string, 0),
+		"expr":         make([]int, 0),
+		"creditcard":   make([]string, 0),
+		"cardtype":     make([]string, 0),
+		"gender":       make([]*gender, 0),
+		"port":         make([]int, 0),
+		"word":         make([]string, 0),
+		"nanoid":       make([]string, 0),
+		"continent":    make([]string, 0),
+		"bool":         make([]bool, 0),
+		"ssn":          make([]string, 0),
+		"hashtag":      make([]string, 0),
+		"lorem":        make([][]string, 0),
+		"vin":          make([]string, 0),
+		"licenseplate": make([]string, 0),
+		"timezone":     make([]string, 0),
+		"status":       make([]string, 0),
+		"bloodtype":    make([]string, 0),
+		"gitsha":       make([]string, 0),
+		"base36":       make([]string, 0),
+		"base62":       make([]string, 0),
+		"imei":         make([]string, 0),
+		"hostname":     make([]string, 0),
+		"jwt":          make([]string, 0),
+		"fraction":     make([]*ratio, 0),
+		"percentage":   make([]float64, 0),
+		"ean13":        make([]string, 0),
+		"upc":          make([]string, 0),
+		"latency":      make([]float64, 0),
+		"measure":      make([]*measurement, 0),
+		"macaddr":      make([]string, 0),
+		"sentence":     make([]string, 0),
+		"money":        make([]*amount, 0),
+		"callingcode":  make([]string, 0),
+
+		namedKey: make(map[string]interface{}),
+		keyedKey: make(map[string]string),
 	}
 }
 
@@ -106,16 +792,62 @@ func newObjectCache() objectCache {
 // invoke in order, which will produce static/random values that can be turned into
 // a string
 func BuildCallstack(inputTemplate string) (*Callstack, error) {
+	return buildCallstack(inputTemplate, 0)
+}
+
+// buildCallstack does the work of BuildCallstack, for a template nested inside of as many
+// others as the nesting. A nested template, one given to a token such as repeat, has already had its
+// backslashes kept for it by the template around it, so every escape in it is honored.
+func buildCallstack(inputTemplate string, nesting int) (*Callstack, error) {
+	if nesting > maxNesting {
+		return nil, InvalidArgumentError(fmt.Sprintf("templates can only be nested %d deep. Please check your input string", maxNesting))
+	}
+	nested := nesting > 0
 	stack := newCallstack()
+	stack.depth = nesting
+	// A template with no tokens or escapes in it is written out just as it is, so there's
+	// nothing to parse
+	if !strings.ContainsAny(inputTemplate, "{\\") {
+		stack.Push(func(result *bytes.Buffer, cache objectCache) error {
+			result.WriteString(inputTemplate)
+			return nil
+		})
+		return stack, nil
+	}
 	wordBuffer := &bytes.Buffer{}
-	foundWord := false
+	// How many braces deep we are. Tokens can hold whole templates as arguments, so a
+	// word only ends once every brace opened inside of it has been closed
+	depth := 0
+	escaped := false
+	// Positions are counted in runes rather than bytes, so that they match up with
+	// what a person sees when looking at the template
+	runes := []rune(inputTemplate)
 	wordStart := 0
-	for i, c := range inputTemplate {
-		if !foundWord && c == '{' {
+	for offset, c := range runes {
+		if escaped {
+			escaped = false
+			// Outside of a token, an escaped brace or backslash is written as it is.
+			// Inside of one, the escape is kept, so that the options or nested template
+			// can make sense of it
+			if depth > 0 || (c != '{' && c != '}' && c != '\\') {
+				wordBuffer.WriteRune('\\')
+			}
+			wordBuffer.WriteRune(c)
+		} else if c == '\\' && depth == 0 && !nested {
+			// Outside of a token, a backslash only escapes a brace, or a second backslash
+			// right before a brace, so that text such as C:\\dir is written as it is
+			if isBrace(runeAt(runes, offset+1)) || (runeAt(runes, offset+1) == '\\' && isBrace(runeAt(runes, offset+2))) {
+				escaped = true
+			} else {
+				wordBuffer.WriteRune(c)
+			}
+		} else if c == '\\' {
+			escaped = true
+		} else if depth == 0 && c == '{' {
 			// We're starting a word to parse
-			foundWord = true
+			depth = 1
 			// Track the position of where the word started, for potential error reporting
-			wordStart = i
+			wordStart = offset
 			// Dump the current buffer into a closure
 			// Assigning to 'cb', ClosureBuster, will get around this issue
 			// THANKS .NET PRIOR TO 4.0 FOR TEACHING ME ABOUT ACCESS TO A MODIFIED CLOSURE!
@@ -126,9 +858,23 @@ func BuildCallstack(inputTemplate string) (*Callstack, error) {
 				return nil
 			}
 			stack.Push(f)
-		} else if foundWord && c == '}' {
+		} else if depth > 0 && c == '{' {
+			depth++
+			wordBuffer.WriteRune(c)
+		} else if depth > 1 && c == '}' {
+			depth--
+			wordBuffer.WriteRune(c)
+		} else if depth == 1 && c == '}' {
 			// We're closing a word, so eval it and get the data to put in the string
-			foundWord = false
+			depth = 0
+			// Comments are only there for whoever reads the template, so they write nothing
+			if strings.HasPrefix(wordBuffer.String(), "#") {
+				stack.Push(func(result *bytes.Buffer, cache objectCache) error {
+					return nil
+				})
+				wordBuffer.Reset()
+				continue
+			}
 			// TODO I dislike this part of the grammer - i think the arguments list
 			// should begin with the |, or at least it's own demarcation, to avoid the
 			// ugly and dual-purpose : construct. I'm open to even changing the grammar
@@ -138,26 +884,121 @@ func BuildCallstack(inputTemplate string) (*Callstack, error) {
 			if len(parts) > 1 {
 				rawOpts = parts[1]
 			}
-			opts, err := optionsToMap(parts[0], rawOpts)
+			explicit, err := parseOptions(parts[0], rawOpts, wordStart)
 			if err != nil {
 				return nil, err
 			}
+			t := &token{name: parts[0], pos: wordStart, explicit: explicit, opts: mergeOptions(parts[0], explicit)}
+			if key := explicit["key"]; key != "" {
+				for _, other := range stack.tokens {
+					if other.explicit["key"] == key {
+						return nil, InvalidArgumentError(fmt.Sprintf("the key %s for the token %q at offset %d is already used by the token %q at offset %d. Please check your input string", key, t.name, t.pos, other.name, other.pos))
+					}
+				}
+			}
+			if tpl, ok := t.opts["tpl"]; ok {
+				// The nested template is built once, up front, so any problems with it
+				// are found now, and changes to this Callstack can be made to it too
+				if t.sub, err = stack.nest(tpl); err != nil {
+					return nil, err
+				}
+			}
+			stack.tokens = append(stack.tokens, t)
 			// Build the closure that will invoke resolveWord
 			f := func(result *bytes.Buffer, cache objectCache) error {
-				val := ""
-				if val, err = resolveWord(cache, parts[0], wordStart, opts); err != nil {
-					return err
+				rnd := stack.rand
+				secure, err := t.opts.getBool("secure")
+				if err != nil {
+					return t.wrapError(err)
+				} else if secure {
+					rnd = stack.secure
+				} else if seeded, err := seededRand(cache, t.opts); err != nil {
+					return t.wrapError(err)
+				} else if seeded != nil {
+					rnd = seeded
+				}
+				unique, err := t.opts.getBool("unique")
+				if err != nil {
+					return t.wrapError(err)
+				}
+				distinct, err := t.opts.getBool("distinct")
+				if err != nil {
+					return t.wrapError(err)
+				}
+				// A reference repeats a value on purpose, so it's never made distinct
+				distinct = distinct && !isReference(t.opts)
+				repeated := func(val string) bool {
+					return unique && stack.seen[t][val] || distinct && cache.distinctSeen(t.name, val)
+				}
+				if t.sub != nil {
+					cache[nestedKey] = t.sub
+				}
+				// A value which has been written before is thrown away, along with the
+				// entry it made in the cache, so that ordinals still line up
+				before := cache[t.name]
+				val, err := stack.resolveToken(rnd, cache, t)
+				for tries := 1; err == nil && repeated(val); tries++ {
+					if tries == uniqueTries {
+						option := "unique"
+						if !unique || !stack.seen[t][val] {
+							option = "distinct"
+						}
+						err = InvalidArgumentError(fmt.Sprintf("%s: no new value was found in %d tries, so there may be none left to find", option, uniqueTries))
+						break
+					}
+					cache[t.name] = before
+					val, err = stack.resolveToken(rnd, cache, t)
+				}
+				if err != nil {
+					return t.wrapError(err)
+				}
+				if unique {
+					stack.see(t, val)
+				}
+				if distinct {
+					cache.seeDistinct(t.name, val)
+				}
+				val = decorate(val, t.opts)
+				if val, err = nullify(stack.rand, val, t.opts); err != nil {
+					return t.wrapError(err)
+				}
+				if key := t.opts["key"]; key != "" {
+					cache[keyedKey].(map[string]string)[key] = val
+				}
+				jsonEscape, err := t.opts.getBool("jsonescape")
+				if err != nil {
+					return t.wrapError(err)
+				} else if jsonEscape {
+					val = EscapeJSON(val)
+				}
+				if stack.escape != nil {
+					val = stack.escape(val)
 				}
 				result.WriteString(val)
 				return nil
 			}
-			stack.Push(f)
+			stack.Push(func(result *bytes.Buffer, cache objectCache) error {
+				err := f(result, cache)
+				// The placeholder is written in place of the value, so it's what WriteMap
+				// gives for the key as well
+				if key := t.opts["key"]; err != nil && stack.collect && key != "" {
+					cache[keyedKey].(map[string]string)[key] = stack.placeholder
+				}
+				return err
+			})
 			wordBuffer.Reset()
 		} else {
 			// Straight pass through
 			wordBuffer.WriteRune(c)
 		}
 	}
+	if depth > 0 {
+		return nil, InvalidArgumentError(fmt.Sprintf("the { at offset %d is never closed with a }. Use \\{ to write a { which is not part of a token. Please check your input string", wordStart))
+	}
+	// A trailing backslash has nothing to escape
+	if escaped {
+		wordBuffer.WriteRune('\\')
+	}
 
 	// If there is anything remaining in word buffer, add the final call to the stack
 	s := wordBuffer.String()
@@ -170,6 +1011,21 @@ func BuildCallstack(inputTemplate string) (*Callstack, error) {
 	return stack, nil
 }
 
+// IsValidTemplate is a quick check of whether the template can be used. It parses the
+// template and writes it out once, since unknown tokens and bad arguments are only found
+// when a token is written. If the template is not valid, the error says why, and at what
+// offset into the template.
+func IsValidTemplate(inputTemplate string) (bool, error) {
+	cs, err := BuildCallstack(inputTemplate)
+	if err != nil {
+		return false, err
+	}
+	if err := cs.Write(&bytes.Buffer{}); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // This function was borrowed with permission from the following location
 // https://github.com/dgryski/trifles/blob/master/uuid/uuid.go
 // All credit / lawsuits can be forwarded to Damian Gryski and Russ Cox
@@ -180,70 +1036,630 @@ func uuidv4() string {
 		// probably "shouldn't happen"
 		log.Fatal(err)
 	}
-	b[6] = (b[6] & 0x0F) | 0x40
-	b[8] = (b[8] &^ 0x40) | 0x80
-	return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:])
+	b[6] = (b[6] & 0x0F) | 0x40
+	b[8] = (b[8] &^ 0x40) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// parseOptions turns the options of a token from the template into a lookup map. Each
+// option must be one the token knows about, so that typos are caught rather than
+// silently falling back to the default.
+func parseOptions(name string, options string, pos int) (cmdOptions, error) {
+	m := make(cmdOptions)
+	// If there were no options specified, there is nothing to parse
+	if len(options) == 0 {
+		return m, nil
+	}
+	parts := splitOptions(options)
+
+	// Unknown tokens are reported when the Callstack is written, so there is nothing
+	// to check their options against
+	_, known := defaultOptions[name]
+	for _, p := range parts {
+		// Some options, like format, can have : in them. Only split the first :, which
+		// should have the arg name, ad a value with an arbitrary number of : inside of it
+		opt := strings.SplitN(p, ":", 2)
+		if len(opt) != 2 {
+			return nil, InvalidArgumentError(fmt.Sprintf("the option %s for the token %q at offset %d has no value. Please check your input string", opt[0], name, pos))
+		}
+		if known && !knownOption(name, opt[0]) {
+			return nil, InvalidArgumentError(fmt.Sprintf("%s is not a known option for the token %q at offset %d, check for typos", opt[0], name, pos))
+		}
+		if known {
+			if err := checkOptionKind(name, opt[0], unescapeOption(opt[1])); err != nil {
+				return nil, InvalidArgumentError(fmt.Sprintf("the option %s for the token %q at offset %d %s. Please check your input string", opt[0], name, pos, err))
+			}
+		}
+		if templateOptions[opt[0]] {
+			// Nested templates are left as they are, escapes and all, to be parsed once
+			// the token is built
+			m[opt[0]] = opt[1]
+		} else {
+			m[opt[0]] = unescapeOption(opt[1])
+		}
+	}
+	return m, nil
+}
+
+// optionKind is the kind of number an option must be
+type optionKind int
+
+const (
+	intKind optionKind = iota
+	floatKind
+)
+
+// optionKinds are the options whose values must be numbers, so that a value which isn't
+// one is caught when the template is parsed, rather than when it is written. Options which
+// are the same kind of number for every token that has them are listed under "", and the
+// rest under the name of their token.
+var optionKinds = map[string]map[string]optionKind{
+	"": {
+		"ordinal": intKind, "maxlength": intKind, "minlen": intKind, "maxlen": intKind, "length": intKind,
+		"precision": intKind, "count": intKind, "words": intKind, "digits": intKind,
+		"size": intKind, "nullprob": floatKind,
+	},
+	"int":        {"min": intKind, "max": intKind, "step": intKind, "mean": floatKind, "stddev": floatKind},
+	"float":      {"min": floatKind, "max": floatKind, "mean": floatKind, "stddev": floatKind, "quantize": floatKind},
+	"currency":   {"min": floatKind, "max": floatKind},
+	"time":       {"min": intKind, "max": intKind},
+	"age":        {"min": intKind, "max": intKind},
+	"port":       {"min": intKind, "max": intKind},
+	"latlng":     {"minlat": floatKind, "maxlat": floatKind, "minlng": floatKind, "maxlng": floatKind},
+	"username":   {"firstname": intKind, "lastname": intKind},
+	"password":   {"upper": intKind, "lower": intKind, "symbols": intKind},
+	"semver":     {"maxmajor": intKind, "maxminor": intKind, "maxpatch": intKind},
+	"filename":   {"path": intKind},
+	"rownum":     {"start": intKind, "step": intKind, "jitter": intKind, "pad": intKind},
+	"bool":       {"probability": floatKind},
+	"lorem":      {"sentences": intKind},
+	"jwt":        {"payload": intKind},
+	"fraction":   {"min": floatKind, "max": floatKind, "denominator": intKind},
+	"percentage": {"min": floatKind, "max": floatKind},
+	"latency":    {"p50": floatKind, "p99": floatKind},
+	"measure":    {"min": floatKind, "max": floatKind},
+	"sentence":   {"min": intKind, "max": intKind, "mean": floatKind, "stddev": floatKind},
+	"money":      {"min": floatKind, "max": floatKind},
+}
+
+// checkOptionKind returns an error saying what the value should be, if the option must be
+// a number and the value isn't one. Whether the number is in range is left to the token.
+func checkOptionKind(name string, option string, value string) error {
+	kind, ok := optionKinds[name][option]
+	if !ok {
+		if kind, ok = optionKinds[""][option]; !ok {
+			return nil
+		}
+	}
+	if kind == intKind {
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("must be a whole number, not %q", value)
+		}
+	} else if _, err := strconv.ParseFloat(value, 64); err != nil {
+		return fmt.Errorf("must be a number, not %q", value)
+	}
+	return nil
+}
+
+// runeAt returns the rune at i, or 0 past the end of the runes
+func runeAt(runes []rune, i int) rune {
+	if i < len(runes) {
+		return runes[i]
+	}
+	return 0
+}
+
+// isBrace returns whether the rune opens or closes a token
+func isBrace(r rune) bool {
+	return r == '{' || r == '}'
+}
+
+// templateOptions are the options whose values are templates in their own right
+var templateOptions = map[string]bool{"tpl": true}
+
+// splitOptions splits the options of a token on each |, except where the | is escaped
+// or inside of a nested template
+func splitOptions(options string) []string {
+	parts := make([]string, 0)
+	depth := 0
+	escaped := false
+	start := 0
+	for i, c := range options {
+		if escaped {
+			escaped = false
+		} else if c == '\\' {
+			escaped = true
+		} else if c == '{' {
+			depth++
+		} else if c == '}' && depth > 0 {
+			depth--
+		} else if c == '|' && depth == 0 {
+			parts = append(parts, options[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, options[start:])
+}
+
+// unescapeOption removes the backslash from in front of any escaped {, }, |, or \ in the
+// value of an option. Any other backslash is left as it is.
+func unescapeOption(v string) string {
+	if !strings.Contains(v, "\\") {
+		return v
+	}
+	b := &bytes.Buffer{}
+	escaped := false
+	for _, c := range v {
+		if escaped {
+			escaped = false
+			if c != '{' && c != '}' && c != '|' && c != '\\' {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(c)
+		} else if c == '\\' {
+			escaped = true
+		} else {
+			b.WriteRune(c)
+		}
+	}
+	if escaped {
+		b.WriteRune('\\')
+	}
+	return b.String()
+}
+
+// truncate cuts the value down to the maxlength option, in runes, when it is set. It is
+// applied before the value is escaped, so that an escape sequence is never cut in half.
+// The value in the cache is left whole, for ordinals to refer to.
+func truncate(val string, opts cmdOptions) (string, error) {
+	n, err := opts.getInt("maxlength")
+	if err != nil {
+		return "", err
+	} else if n < 0 {
+		return "", InvalidArgumentError("You have specified a maxlength which is not a number greater than or equal to zero. Please check your input string")
+	}
+	if n > 0 {
+		return truncateRunes(val, n), nil
+	}
+	return val, nil
+}
+
+// decorate places the prefix and suffix options around the value
+func decorate(val string, opts cmdOptions) string {
+	return opts["prefix"] + val + opts["suffix"]
+}
+
+// remember stores the value under the as option, so that tokens which take a from option
+// can build on it. Tokens which define as themselves store something richer, such as a
+// number or an address, so they're left alone.
+func remember(oc objectCache, name string, val string, opts cmdOptions) {
+	if _, ok := defaultOptions[name]["as"]; !ok {
+		oc.setNamed(opts["as"], val)
+	}
+}
+
+// seededRand returns a source of random values seeded from a hash of the seed option, or
+// of the value stored under the name in the seedref option, so that the token always
+// writes the same value for the same seed, no matter what else is in the template. It
+// returns nil if neither option is set.
+func seededRand(oc objectCache, opts cmdOptions) (*rand.Rand, error) {
+	seed := opts["seed"]
+	if name := opts["seedref"]; name != "" {
+		if seed != "" {
+			return nil, InvalidArgumentError("You cannot provide both a seed and a seedref. Please check your input string")
+		}
+		v, err := oc.getNamedText("seedref", name)
+		if err != nil {
+			return nil, err
+		}
+		seed = v
+	} else if seed == "" {
+		return nil, nil
+	}
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	return rand.New(rand.NewSource(int64(h.Sum64()))), nil
+}
+
+// nullify replaces the value with the nullvalue option, as often as the nullprob option
+// asks for. The value is always generated first, so that ordinals line up the same
+// whether or not it was replaced.
+func nullify(rnd *rand.Rand, val string, opts cmdOptions) (string, error) {
+	p, err := opts.getFloat("nullprob")
+	if err != nil {
+		return "", err
+	} else if p < 0 || p > 1 {
+		return "", InvalidArgumentError("You have specified a nullprob which is not a number from 0 to 1. Please check your input string")
+	}
+	if p > 0 && rnd.Float64() < p {
+		return opts["nullvalue"], nil
+	}
+	return val, nil
+}
+
+// knownOption returns whether the option is one the token accepts
+func knownOption(name string, option string) bool {
+	if _, ok := genericOptions[option]; ok {
+		return true
+	}
+	_, ok := defaultOptions[name][option]
+	return ok
+}
+
+// mergeOptions returns the default options for the token, overridden by the options
+// that were set in the template
+func mergeOptions(name string, explicit cmdOptions) cmdOptions {
+	m := make(cmdOptions)
+	for k, v := range genericOptions {
+		m[k] = v
+	}
+	for k, v := range defaultOptions[name] {
+		m[k] = v
+	}
+	for k, v := range explicit {
+		m[k] = v
+	}
+	return m
+}
+
+func resolveWord(rnd *rand.Rand, oc objectCache, word string, pos int, opts cmdOptions) (string, error) {
+	// If there were options provided, convert them to a lookup map prior to invoking
+	// a randomizer.
+	switch word {
+	case "guid":
+		return guid(rnd, oc, opts)
+	case "int":
+		return integer(rnd, oc, opts)
+	case "now":
+		return now(rnd, oc, opts)
+	case "time":
+		return datetime(rnd, oc, opts)
+	case "float":
+		return float(rnd, oc, opts)
+	case "unicode":
+		return unicode(rnd, oc, opts)
+	case "ascii":
+		return ascii(rnd, oc, opts)
+	case "country":
+		return country(rnd, oc, opts)
+	case "firstname":
+		return firstname(rnd, oc, opts)
+	case "lastname":
+		return lastname(rnd, oc, opts)
+	case "currency":
+		return currency(rnd, oc, opts)
+	case "weekday":
+		return weekday(rnd, oc, opts)
+	case "month":
+		return month(rnd, oc, opts)
+	case "streetaddress":
+		return streetaddress(rnd, oc, opts)
+	case "city":
+		return city(rnd, oc, opts)
+	case "state":
+		return state(rnd, oc, opts)
+	case "zipcode":
+		return zipcode(rnd, oc, opts)
+	case "company":
+		return company(rnd, oc, opts)
+	case "jobtitle":
+		return jobtitle(rnd, oc, opts)
+	case "username":
+		return username(rnd, oc, opts)
+	case "password":
+		return password(rnd, oc, opts)
+	case "age":
+		return age(rnd, oc, opts)
+	case "objectid":
+		return objectid(rnd, oc, opts)
+	case "semver":
+		return semver(rnd, oc, opts)
+	case "httpstatus":
+		return httpstatus(rnd, oc, opts)
+	case "httpmethod":
+		return httpmethod(rnd, oc, opts)
+	case "latlng":
+		return latlng(rnd, oc, opts)
+	case "duration":
+		return duration(rnd, oc, opts)
+	case "slug":
+		return slug(rnd, oc, opts)
+	case "filename":
+		return filename(rnd, oc, opts)
+	case "emoji":
+		return emoji(rnd, oc, opts)
+	case "iban":
+		return iban(rnd, oc, opts)
+	case "isbn":
+		return isbn(rnd, oc, opts)
+	case "repeat":
+		return repeat(rnd, oc, opts)
+	case "expr":
+		return expr(rnd, oc, opts)
+	case "creditcard":
+		return creditcard(rnd, oc, opts)
+	case "cardtype":
+		return cardtype(rnd, oc, opts)
+	case "gender":
+		return genderToken(rnd, oc, opts)
+	case "port":
+		return port(rnd, oc, opts)
+	case "word":
+		return dictionaryWord(rnd, oc, opts)
+	case "nanoid":
+		return nanoid(rnd, oc, opts)
+	case "continent":
+		return continent(rnd, oc, opts)
+	case "rownum":
+		return rownum(rnd, oc, opts)
+	case "bool":
+		return boolean(rnd, oc, opts)
+	case "maybe":
+		return maybe(rnd, oc, opts)
+	case "ssn":
+		return ssn(rnd, oc, opts)
+	case "hashtag":
+		return hashtag(rnd, oc, opts)
+	case "lorem":
+		return lorem(rnd, oc, opts)
+	case "vin":
+		return vin(rnd, oc, opts)
+	case "licenseplate":
+		return licenseplate(rnd, oc, opts)
+	case "timezone":
+		return timezone(rnd, oc, opts)
+	case "status":
+		return status(rnd, oc, opts)
+	case "bloodtype":
+		return bloodtype(rnd, oc, opts)
+	case "gitsha":
+		return gitsha(rnd, oc, opts)
+	case "base36":
+		return base36(rnd, oc, opts)
+	case "base62":
+		return base62(rnd, oc, opts)
+	case "imei":
+		return imei(rnd, oc, opts)
+	case "hostname":
+		return hostname(rnd, oc, opts)
+	case "jwt":
+		return jwt(rnd, oc, opts)
+	case "fraction":
+		return fraction(rnd, oc, opts)
+	case "percentage":
+		return percentage(rnd, oc, opts)
+	case "ean13":
+		return ean13(rnd, oc, opts)
+	case "upc":
+		return upc(rnd, oc, opts)
+	case "latency":
+		return latency(rnd, oc, opts)
+	case "measure":
+		return measure(rnd, oc, opts)
+	case "macaddr":
+		return macaddr(rnd, oc, opts)
+	case "sentence":
+		return sentence(rnd, oc, opts)
+	case "money":
+		return money(rnd, oc, opts)
+	case "callingcode":
+		return callingCode(rnd, oc, opts)
+	}
+	return "", UnsupportedTokenError(fmt.Sprintf("the token %q at offset %d is not recognized, check for typos", word, pos))
+}
+
+// TODO All the below functions need way better commenting and parameter annotations
+// It's described in the readme, but I should probably make these public and then
+// give them proper comments, so that GoDoc can also document them
+
+func integer(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	min, err := opts.getInt("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getInt("max")
+	if err != nil {
+		return "", err
+	}
+	inclusive, err := opts.getBool("inclusive")
+	if err != nil {
+		return "", err
+	}
+	step, err := opts.getInt("step")
+	if err != nil {
+		return "", err
+	} else if step <= 0 {
+		return "", InvalidArgumentError("You have specified a step which is not a number greater than zero. Please check your input string")
+	}
+	count, err := getCount(opts)
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["int"]
+		cache := c.([]int)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "int", len(cache))
+		}
+		i := cache[ord]
+		return strconv.Itoa(i), nil
+	}
+
+	if min > max {
+		return "", InvalidArgumentError("You cannot generate a random number whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+
+	if step > 1 && (min%step != 0 || max%step != 0) {
+		return "", InvalidArgumentError(fmt.Sprintf("You cannot generate a random number in steps of %d, when the bounds %d and %d are not both divisible by it. Please check your input string", step, min, max))
+	}
+	if !inclusive {
+		if min == max {
+			return "", InvalidArgumentError("You cannot generate a random number which excludes it's upper bound, when it is the same as the lower bound. Please check your input string")
+		}
+		// The largest value left is the step below max
+		max -= step
+	}
+
+	normal, err := isNormal(opts)
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, count)
+	for i := range values {
+		var n int
+		if normal {
+			x, err := normalValue(rnd, opts, float64(min), float64(max))
+			if err != nil {
+				return "", err
+			}
+			// Round to the nearest step, which can't go past the bounds since they are
+			// both steps themselves
+			n = min + int(math.Floor((x-float64(min))/float64(step)+0.5))*step
+		} else {
+			if n, err = steppedInteger(rnd, min, max, step); err != nil {
+				return "", err
+			}
+		}
+
+		// store it in the cache
+		ca := oc["int"]
+		cache := ca.([]int)
+		oc["int"] = append(cache, n)
+		oc.setNamed(opts["as"], n)
+		values[i] = strconv.Itoa(n)
+	}
+
+	return strings.Join(values, opts["sep"]), nil
+}
+
+// getCount returns the count option of {int} and {float}, which is how many values to
+// write, joined by the sep option
+func getCount(opts cmdOptions) (int, error) {
+	count, err := opts.getInt("count")
+	if err != nil {
+		return 0, err
+	} else if count <= 0 {
+		return 0, InvalidArgumentError("You have specified a count which is not a number greater than zero. Please check your input string")
+	}
+	return count, nil
 }
 
-func optionsToMap(name string, options string) (map[string]string, error) {
-	m := make(map[string]string)
-	defaults := defaultOptions[name]
-	for k, v := range defaults {
-		m[k] = v
+// steppedInteger picks a random multiple of step, from min up to and including max. Both
+// bounds must themselves be multiples of the step. The number of steps is counted as a
+// uint64, so that a range as wide as 0 to math.MaxInt64 can't overflow.
+func steppedInteger(rnd *rand.Rand, min int, max int, step int) (int, error) {
+	steps := (uint64(max)-uint64(min))/uint64(step) + 1
+	if steps == 0 {
+		// Every int64 is in the range, which is one more value than a uint64 can count
+		return 0, InvalidArgumentError(fmt.Sprintf("You cannot generate a random number from %d to %d, as there are too many values in between to count. Please check your input string", min, max))
 	}
-	// If there were no options specified, just use defaults
-	if len(options) == 0 {
-		return m, nil
+	var k uint64
+	if steps <= math.MaxInt64 {
+		k = uint64(rnd.Int63n(int64(steps)))
+	} else {
+		// More than half of all uint64s are in range, so this rarely takes more than a
+		// couple of draws
+		for k = rnd.Uint64(); k >= steps; k = rnd.Uint64() {
+		}
 	}
-	parts := strings.Split(options, "|")
+	return int(uint64(min) + k*uint64(step)), nil
+}
 
-	for _, p := range parts {
-		// Some options, like format, can have : in them. Only split the first :, which
-		// should have the arg name, ad a value with an arbitrary number of : inside of it
-		opt := strings.SplitN(p, ":", 2)
-		m[opt[0]] = opt[1]
+// isNormal returns whether the distribution option asks for a normal distribution,
+// rather than a uniform one
+func isNormal(opts cmdOptions) (bool, error) {
+	switch opts["distribution"] {
+	case "uniform":
+		return false, nil
+	case "normal":
+		return true, nil
 	}
-	return m, nil
+	return false, InvalidArgumentError(fmt.Sprintf("distribution: %s is not a known distribution. Use either uniform or normal", opts["distribution"]))
 }
 
-func resolveWord(oc objectCache, word string, pos int, opts cmdOptions) (string, error) {
-	// If there were options provided, convert them to a lookup map prior to invoking
-	// a randomizer.
-	switch word {
-	case "guid":
-		return guid(oc, opts)
-	case "int":
-		return integer(oc, opts)
-	case "now":
-		return now(oc, opts)
-	case "time":
-		return datetime(oc, opts)
-	case "float":
-		return float(oc, opts)
-	case "unicode":
-		return unicode(oc, opts)
-	case "ascii":
-		return ascii(oc, opts)
-	case "country":
-		return country(oc, opts)
-	case "firstname":
-		return firstname(oc, opts)
-	case "lastname":
-		return lastname(oc, opts)
+// normalValue picks a number from a normal distribution, following the mean and stddev
+// options, and clamps it to be from min to max. The mean defaults to half way between
+// the bounds, and the stddev to a sixth of the range, so that nearly every value falls
+// in range without needing to be clamped.
+func normalValue(rnd *rand.Rand, opts cmdOptions, min float64, max float64) (float64, error) {
+	mean := min + (max-min)/2
+	if opts["mean"] != "" {
+		m, err := opts.getFloat("mean")
+		if err != nil {
+			return 0, err
+		}
+		mean = m
 	}
-	return "", UnsupportedTokenError(fmt.Sprintf("the token %s at position %d is not recognized, check for typos", word, pos))
+	stddev := (max - min) / 6
+	if opts["stddev"] != "" {
+		sd, err := opts.getFloat("stddev")
+		if err != nil {
+			return 0, err
+		} else if sd < 0 {
+			return 0, InvalidArgumentError("You have specified a stddev which is not a number greater than or equal to zero. Please check your input string")
+		}
+		stddev = sd
+	}
+	return math.Max(min, math.Min(max, normalFloat(rnd, mean, stddev))), nil
 }
 
-// TODO All the below functions need way better commenting and parameter annotations
-// It's described in the readme, but I should probably make these public and then
-// give them proper comments, so that GoDoc can also document them
+// normalFloat picks a number from the normal distribution with the given mean and stddev
+func normalFloat(rnd *rand.Rand, mean float64, stddev float64) float64 {
+	return rnd.NormFloat64()*stddev + mean
+}
 
-func integer(oc objectCache, opts cmdOptions) (string, error) {
-	min, err := opts.getInt("min")
+// quantizeFloat rounds n to the nearest multiple of step which is from min to max. Dividing
+// by a step such as 0.1 is rarely exact, so a bound within a hair of a multiple is taken
+// to be one, and the result is clamped back in bounds so it can't land just outside them.
+func quantizeFloat(n float64, step float64, min float64, max float64, inclusive bool) (float64, error) {
+	const tolerance = 1e-9
+	lo := math.Ceil(min/step - tolerance)
+	hi := math.Floor(max/step + tolerance)
+	if !inclusive && hi*step >= max-tolerance*step {
+		hi--
+	}
+	if lo > hi {
+		return 0, InvalidArgumentError(fmt.Sprintf("There is no multiple of %g between %g and %g. Please check your input string", step, min, max))
+	}
+	k := math.Max(lo, math.Min(hi, math.Round(n/step)))
+	return math.Max(min, math.Min(max, k*step)), nil
+}
+
+func float(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	verb, err := floatVerb(opts["format"])
 	if err != nil {
 		return "", err
 	}
-	max, err := opts.getInt("max")
+	prec, err := opts.getInt("precision")
+	if err != nil {
+		return "", err
+	} else if prec < 0 {
+		return "", InvalidArgumentError("You have specified a precision which is not a number greater than or equal to zero. Please check your input string")
+	}
+	min, err := opts.getFloat("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getFloat("max")
+	if err != nil {
+		return "", err
+	}
+	inclusive, err := opts.getBool("inclusive")
+	if err != nil {
+		return "", err
+	}
+	quantize, err := opts.getFloat("quantize")
+	if err != nil {
+		return "", err
+	} else if quantize < 0 {
+		return "", InvalidArgumentError("You have specified a quantize which is not a number greater than or equal to zero. Please check your input string")
+	}
+	count, err := getCount(opts)
 	if err != nil {
 		return "", err
 	}
@@ -253,50 +1669,107 @@ func integer(oc objectCache, opts cmdOptions) (string, error) {
 	}
 
 	if ord >= 0 {
-		c := oc["int"]
-		cache := c.([]int)
+		c := oc["float"]
+		cache := c.([]float64)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for integers. Please check your input string", ord))
+			return "", ordinalError(ord, "float", len(cache))
 		}
-		i := cache[ord]
-		return strconv.Itoa(i), nil
+		n := cache[ord]
+		return strconv.FormatFloat(n, verb, prec, 64), nil
+	}
+	if ref := opts["ref"]; ref != "" {
+		v, err := oc.getNamed(ref)
+		if err != nil {
+			return "", err
+		}
+		switch n := v.(type) {
+		case float64:
+			return strconv.FormatFloat(n, verb, prec, 64), nil
+		case int:
+			return strconv.FormatFloat(float64(n), verb, prec, 64), nil
+		}
+		return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to a number. Please check your input string", ref))
 	}
 
 	if min > max {
 		return "", InvalidArgumentError("You cannot generate a random number whose lower bound is greater than it's upper bound. Please check your input string")
+	} else if !inclusive && min == max {
+		return "", InvalidArgumentError("You cannot generate a random number which excludes it's upper bound, when it is the same as the lower bound. Please check your input string")
 	}
 
-	// Incase we need to tell the function to invert the case
-	negateResult := false
-	// get the difference between them
-	diff := max - min
-	// Since this supports negatives, need to handle some special corner cases?
-	if max < 0 && min <= 0 {
-		// if the range is entirely negative
-		negateResult = true
-		// Swap the min, diff will be the same, but minimum is now inverted vs it's old value
-		// trip the flag to negate the overall result
-		min = -max
-	}
-	// neg to pos ranges currently not supported
-	// else both are positive
-	// get a number from 0 to diff
-	n := rand.Intn(diff)
-	// add lowerbound to it - now it's between lower and upper
-	n += min
-	if negateResult {
-		n = -n
+	normal, err := isNormal(opts)
+	if err != nil {
+		return "", err
+	}
+	values := make([]string, count)
+	for i := range values {
+		var n float64
+		if normal {
+			n, err = normalValue(rnd, opts, min, max)
+			if err != nil {
+				return "", err
+			}
+			if !inclusive && n == max {
+				// Clamping is the only way to land exactly on max, so step back off of it
+				n = math.Nextafter(max, min)
+			}
+		} else {
+			n = min + unitFloat(rnd, inclusive)*(max-min)
+			// Rounding can carry a value just short of max up to it, or past it
+			if n > max || (!inclusive && n == max) {
+				n = math.Nextafter(max, min)
+				if inclusive {
+					n = max
+				}
+			}
+		}
+		if quantize > 0 {
+			if n, err = quantizeFloat(n, quantize, min, max, inclusive); err != nil {
+				return "", err
+			}
+		}
+
+		// store it in the cache
+		ca := oc["float"]
+		cache := ca.([]float64)
+		oc["float"] = append(cache, n)
+		oc.setNamed(opts["as"], n)
+		values[i] = strconv.FormatFloat(n, verb, prec, 64)
 	}
 
-	// store it in the cache
-	ca := oc["int"]
-	cache := ca.([]int)
-	oc["int"] = append(cache, n)
+	return strings.Join(values, opts["sep"]), nil
+}
+
+// unitFloat returns a random float64 from 0 up to 1, which includes 1 only if inclusive
+// is set. Float64 never returns 1, so an inclusive value is made from one of the 2^53+1
+// evenly spaced steps which a float64 can hold exactly between 0 and 1.
+func unitFloat(rnd *rand.Rand, inclusive bool) float64 {
+	if !inclusive {
+		return rnd.Float64()
+	}
+	return float64(rnd.Int63n(1<<53+1)) / (1 << 53)
+}
 
-	return strconv.Itoa(n), nil
+// floatVerb maps the format option of the float token to the verb understood by
+// strconv.FormatFloat
+func floatVerb(format string) (byte, error) {
+	switch format {
+	case "f", "fixed":
+		return 'f', nil
+	case "e", "sci":
+		return 'e', nil
+	case "g", "compact":
+		return 'g', nil
+	}
+	return 0, InvalidArgumentError(fmt.Sprintf("format: %s is not a known float format. Use one of f, e, or g", format))
 }
 
-func float(oc objectCache, opts cmdOptions) (string, error) {
+func currency(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	code := strings.ToUpper(opts["code"])
+	cur, ok := Currencies[code]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("code: %s is not a known currency code", code))
+	}
 	min, err := opts.getFloat("min")
 	if err != nil {
 		return "", err
@@ -311,49 +1784,40 @@ func float(oc objectCache, opts cmdOptions) (string, error) {
 	}
 
 	if ord >= 0 {
-		c := oc["float"]
-		cache := c.([]float64)
+		c := oc["currency"]
+		cache := c.([]string)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for floats. Please check your input string", ord))
+			return "", ordinalError(ord, "currency", len(cache))
 		}
-		n := cache[ord]
-		return fmt.Sprintf("%f", n), nil
+		return cache[ord], nil
 	}
 
 	if min > max {
-		return "", InvalidArgumentError("You cannot generate a random number whose lower bound is greater than it's upper bound. Please check your input string")
+		return "", InvalidArgumentError("You cannot generate a random amount whose lower bound is greater than it's upper bound. Please check your input string")
 	}
 
-	// Incase we need to tell the function to invert the case
-	negateResult := false
-	// get the difference between them
-	diff := max - min
-	// Since this supports negatives, need to handle some special corner cases?
-	if min < 0.0 && max <= 0.0 {
-		// if the range is entirely negative
-		negateResult = true
-		// Swap the min, diff will be the same, but minimum is now inverted vs it's old value
-		// trip the flag to negate the overall result
-		min = -max
-	}
-	// neg to pos ranges currently not supported
-	// else both are positive
-	// get a number from 0 to diff
-	n := (rand.Float64() * diff) + min
-	if negateResult {
+	// Round to the minor unit of the currency, so the amount is one that could
+	// actually be paid - there is no such thing as half a yen
+	unit := math.Pow10(cur.Decimals)
+	n := math.Floor((min+rnd.Float64()*(max-min))*unit+0.5) / unit
+	sign := ""
+	if n < 0 {
+		sign = "-"
 		n = -n
 	}
+	result := sign + cur.Symbol + strconv.FormatFloat(n, 'f', cur.Decimals, 64)
 
 	// store it in the cache
-	ca := oc["float"]
-	cache := ca.([]float64)
-	oc["float"] = append(cache, n)
+	ca := oc["currency"]
+	cache := ca.([]string)
+	oc["currency"] = append(cache, result)
 
-	return fmt.Sprintf("%f", n), nil
+	return result, nil
 }
 
-func country(oc objectCache, opts cmdOptions) (string, error) {
+func country(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
 	cCase := opts["case"]
+	format := opts["format"]
 	ord, err := opts.getInt("ordinal")
 	if err != nil {
 		return "", err
@@ -361,38 +1825,140 @@ func country(oc objectCache, opts cmdOptions) (string, error) {
 
 	if ord >= 0 {
 		c := oc["country"]
-		cache := c.([]string)
+		cache := c.([]int)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for countries. Please check your input string", ord))
+			return "", ordinalError(ord, "country", len(cache))
 		}
-		country := cache[ord]
-		// Countries go into the cache upper case, only check for lowering it
-		if cCase == "down" {
-			return strings.ToLower(country), nil
+		// The cache holds the index of the country, so that a reference can render
+		// it in a different format than the original
+		return formatCountry(Countries[cache[ord]], format, cCase)
+	}
+	if _, err := countryField(Countries[0], format); err != nil {
+		return "", err
+	}
+	var pick func() int
+	switch opts["weight"] {
+	case "uniform":
+		pick = func() int { return rnd.Intn(len(Countries)) }
+	case "population":
+		pick = func() int { return weightedIndex(rnd, CountryPopulations) }
+	default:
+		return "", InvalidArgumentError(fmt.Sprintf("weight: %s is not a known country weighting. Use one of uniform or population", opts["weight"]))
+	}
+	// Generate a new one. Not every country has every representation, so keep
+	// drawing until we land on one that does
+	var n int
+	for {
+		n = pick()
+		if v, _ := countryField(Countries[n], format); v != "" {
+			break
 		}
-		return country, nil
 	}
-	// Generate a new one
-	n := rand.Intn(len(CountryCodes))
-	country := CountryCodes[n]
 	// store it in the cache
 	ca := oc["country"]
+	cache := ca.([]int)
+	oc["country"] = append(cache, n)
+	oc.setNamed(opts["as"], Countries[n])
+
+	return formatCountry(Countries[n], format, cCase)
+}
+
+// weightedIndex picks a random index into weights, where the chance of each index being
+// picked is proportional to it's weight. Indexes with a weight of 0 are never picked.
+func weightedIndex(rnd *rand.Rand, weights []float64) int {
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	r := rnd.Float64() * total
+	last := 0
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if r < w {
+			return i
+		}
+		r -= w
+		last = i
+	}
+	// Floating point error can leave a sliver of r behind after the final weight
+	return last
+}
+
+func formatCountry(c *Country, format string, cCase string) (string, error) {
+	v, err := countryField(c, format)
+	if err != nil {
+		return "", err
+	} else if v == "" {
+		return "", InvalidArgumentError(fmt.Sprintf("The country %s has no %s representation. Please check your input string", c.Alpha2, format))
+	}
+	return applyCase(v, cCase), nil
+}
+
+func continent(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["continent"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "continent", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	var result string
+	if ref := opts["ref"]; ref != "" {
+		v, err := oc.getNamed(ref)
+		if err != nil {
+			return "", err
+		}
+		c, ok := v.(*Country)
+		if !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("ref: %s does not refer to a country. Please check your input string", ref))
+		}
+		// A few entries, like the United Nations, aren't on any continent, and are left blank
+		result = c.Continent
+	} else {
+		result = Continents[rnd.Intn(len(Continents))]
+	}
+
+	// store it in the cache
+	ca := oc["continent"]
 	cache := ca.([]string)
-	oc["country"] = append(cache, country)
+	oc["continent"] = append(cache, result)
+
+	return applyCase(result, cCase), nil
+}
 
-	if cCase == "down" {
-		return strings.ToLower(country), nil
+// countryField returns the representation of the country matching the format option
+// of the country token
+func countryField(c *Country, format string) (string, error) {
+	switch format {
+	case "iso2":
+		return c.Alpha2, nil
+	case "iso3":
+		return c.Alpha3, nil
+	case "numeric":
+		return c.Numeric, nil
+	case "name":
+		return c.Name, nil
+	case "continent":
+		return c.Continent, nil
 	}
-	return country, nil
+	return "", InvalidArgumentError(fmt.Sprintf("format: %s is not a known country format. Use one of iso2, iso3, numeric, name, or continent", format))
 }
 
-func unicode(oc objectCache, opts cmdOptions) (string, error) {
+func unicode(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
 	cCase := opts["case"]
-	num, err := opts.getInt("length")
+	min, max, err := lengthRange(opts)
 	if err != nil {
 		return "", err
-	} else if num <= 0 {
-		return "", InvalidArgumentError("You have specified a number of characters to generate which is not a number greater than zero. Please check your input string")
 	}
 	ord, err := opts.getInt("ordinal")
 	if err != nil {
@@ -403,7 +1969,7 @@ func unicode(oc objectCache, opts cmdOptions) (string, error) {
 		c := oc["unicode"]
 		cache := c.([]string)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for unicode strings. Please check your input string", ord))
+			return "", ordinalError(ord, "unicode", len(cache))
 		}
 		str := cache[ord]
 		// Countries go into the cache upper case, only check for lowering it
@@ -413,7 +1979,24 @@ func unicode(oc objectCache, opts cmdOptions) (string, error) {
 		return str, nil
 	}
 
-	result := generateRandomString(num)
+	ranges := PrintableRanges
+	if name := opts["ranges"]; name != "" {
+		// The entry is missing for caches which weren't built by a Callstack, and a nil
+		// map finds nothing, the same as a Callstack which has no ranges added
+		added, _ := oc[rangesKey].(map[string][][]int)
+		set, ok := added[name]
+		if !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("ranges: %s has not been added to the Callstack with AddRanges", name))
+		}
+		ranges = set
+	}
+
+	num := min
+	// Intn can't be given a 0, so only pick a length when there is a range to pick from
+	if max > min {
+		num += rnd.Intn(max - min + 1)
+	}
+	result := generateRandomString(rnd, num, ranges)
 	// store it in the cache
 	ca := oc["unicode"]
 	cache := ca.([]string)
@@ -424,13 +2007,11 @@ func unicode(oc objectCache, opts cmdOptions) (string, error) {
 	return string(result), nil
 }
 
-func ascii(oc objectCache, opts cmdOptions) (string, error) {
+func ascii(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
 	cCase := opts["case"]
-	num, err := opts.getInt("length")
+	min, max, err := lengthRange(opts)
 	if err != nil {
 		return "", err
-	} else if num <= 0 {
-		return "", InvalidArgumentError("You have specified a number of characters to generate which is not a number greater than zero. Please check your input string")
 	}
 	ord, err := opts.getInt("ordinal")
 	if err != nil {
@@ -441,7 +2022,7 @@ func ascii(oc objectCache, opts cmdOptions) (string, error) {
 		c := oc["ascii"]
 		cache := c.([]string)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for ASCII strings. Please check your input string", ord))
+			return "", ordinalError(ord, "ascii", len(cache))
 		}
 		str := cache[ord]
 		// Countries go into the cache upper case, only check for lowering it
@@ -451,7 +2032,12 @@ func ascii(oc objectCache, opts cmdOptions) (string, error) {
 		return str, nil
 	}
 
-	result := generateRandomASCIIString(num)
+	num := min
+	// Intn can't be given a 0, so only pick a length when there is a range to pick from
+	if max > min {
+		num += rnd.Intn(max - min + 1)
+	}
+	result := generateRandomASCIIString(rnd, num)
 	// store it in the cache
 	ca := oc["ascii"]
 	cache := ca.([]string)
@@ -462,23 +2048,56 @@ func ascii(oc objectCache, opts cmdOptions) (string, error) {
 	return string(result), nil
 }
 
-func generateRandomASCIIString(length int) string {
+// lengthRange returns the smallest and largest number of characters a generated string
+// may have. If either of the minlen or maxlen options are set, they take the place of the
+// fixed length option. They're apart from the generic maxlength, which cuts the value
+// down once it's generated.
+func lengthRange(opts cmdOptions) (int, int, error) {
+	num, err := opts.getInt("length")
+	if err != nil {
+		return 0, 0, err
+	}
+	min, err := opts.getInt("minlen")
+	if err != nil {
+		return 0, 0, err
+	}
+	max, err := opts.getInt("maxlen")
+	if err != nil {
+		return 0, 0, err
+	}
+	if min == 0 && max == 0 {
+		min, max = num, num
+	} else if min == 0 {
+		// Only an upper bound was given
+		min = 1
+	} else if max == 0 {
+		return 0, 0, InvalidArgumentError("You have specified a minlen without a maxlen. Please check your input string")
+	}
+	if min <= 0 || max <= 0 {
+		return 0, 0, InvalidArgumentError("You have specified a number of characters to generate which is not a number greater than zero. Please check your input string")
+	} else if min > max {
+		return 0, 0, InvalidArgumentError("You cannot generate a random string whose minlen is greater than it's maxlen. Please check your input string")
+	}
+	return min, max, nil
+}
+
+func generateRandomASCIIString(rnd *rand.Rand, length int) string {
 	// This also includes numbers which is questionable, however since when folks want to
 	// work with ascii strings, they anticipate 0-9 as well. Open to changing this if need be.
 	var letters = []rune("0123456789abcdefghijklmnopqrstuvwxy")
 
 	b := make([]rune, length)
 	for i := range b {
-		b[i] = letters[rand.Intn(len(letters))]
+		b[i] = letters[rnd.Intn(len(letters))]
 	}
 	return string(b)
 }
 
-func generateRandomString(length int) string {
+func generateRandomString(rnd *rand.Rand, length int, ranges [][]int) string {
 	rarr := make([]rune, length)
 	for i := 0; i < length; i++ {
 		// First, pick which range this character comes from
-		r := PrintableRanges[rand.Intn(len(PrintableRanges))]
+		r := ranges[rnd.Intn(len(ranges))]
 
 		minCharCode := r[0]
 		maxCharCode := r[1]
@@ -486,18 +2105,22 @@ func generateRandomString(length int) string {
 		// Get the delata between max and min
 		diff := maxCharCode - minCharCode
 		// Get a random value within the range specified
-		num := rand.Intn(diff) + minCharCode
+		num := rnd.Intn(diff) + minCharCode
 		// Turn it into a rune, set it on the result object
 		rarr[i] = rune(num)
 	}
 	return string(rarr)
 }
 
-func now(oc objectCache, opts cmdOptions) (string, error) {
+func now(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
 	loc, err := time.LoadLocation(opts["zone"])
 	if err != nil {
 		return "", err
 	}
+	unit, err := getTruncate(opts)
+	if err != nil {
+		return "", err
+	}
 
 	ord, err := opts.getInt("ordinal")
 	if err != nil {
@@ -505,23 +2128,28 @@ func now(oc objectCache, opts cmdOptions) (string, error) {
 	}
 	if ord >= 0 {
 		c := oc["now"]
-		cache := c.([]string)
+		cache := c.([]time.Time)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for time-now. Please check your input string", ord))
+			return "", ordinalError(ord, "now", len(cache))
 		}
-		return cache[ord], nil
+		// The cache holds the time itself, so a reference can use it's own format
+		t := truncateTime(cache[ord], unit)
+		return formatTime(&t, opts["format"]), nil
 	}
 	now := time.Now().In(loc)
-	ts := formatTime(&now, opts["format"])
+	// The cache holds the time before it's truncated, for references to truncate
+	// their own way
+	t := truncateTime(now, unit)
+	ts := formatTime(&t, opts["format"])
 
 	// store it in the cache
 	c := oc["now"]
-	cache := c.([]string)
-	oc["now"] = append(cache, ts)
+	cache := c.([]time.Time)
+	oc["now"] = append(cache, now)
 	return ts, nil
 }
 
-func datetime(oc objectCache, opts cmdOptions) (string, error) {
+func datetime(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
 	min, err := opts.getInt("min")
 	if err != nil {
 		return "", err
@@ -541,17 +2169,23 @@ func datetime(oc objectCache, opts cmdOptions) (string, error) {
 	}
 
 	f := opts["format"]
+	unit, err := getTruncate(opts)
+	if err != nil {
+		return "", err
+	}
 	ord, err := opts.getInt("ordinal")
 	if err != nil {
 		return "", err
 	}
 	if ord >= 0 {
 		c := oc["time"]
-		cache := c.([]string)
+		cache := c.([]time.Time)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for time-now. Please check your input string", ord))
+			return "", ordinalError(ord, "time", len(cache))
 		}
-		return cache[ord], nil
+		// The cache holds the time itself, so a reference can use it's own format
+		t := truncateTime(cache[ord], unit)
+		return formatTime(&t, f), nil
 	}
 	// get the difference between them
 	diff := max - min
@@ -559,29 +2193,134 @@ func datetime(oc objectCache, opts cmdOptions) (string, error) {
 	// Get a random value from 0 to the delta, and add the minimum
 	// Due to an issue with Int63n, you cannot pass it a 0
 	if diff > 0 {
-		ut = rand.Int63n(int64(diff)) + int64(min)
+		ut = rnd.Int63n(int64(diff)) + int64(min)
 	} else {
 		ut = int64(min)
 	}
 	// Get the time at that value
 	t := time.Unix(ut, 0).In(loc)
-	ts := formatTime(&t, f)
+	// The cache holds the time before it's truncated, for references to truncate
+	// their own way
+	tt := truncateTime(t, unit)
+	ts := formatTime(&tt, f)
 	// store it in the cache
 	c := oc["time"]
-	cache := c.([]string)
-	oc["time"] = append(cache, ts)
+	cache := c.([]time.Time)
+	oc["time"] = append(cache, t)
 
 	return ts, nil
 }
 
+// getTruncate returns the truncate option of {now} and {time}, which is the finest field
+// of the time to keep
+func getTruncate(opts cmdOptions) (string, error) {
+	switch v := opts["truncate"]; v {
+	case "", "day", "hour", "minute", "second":
+		return v, nil
+	}
+	return "", InvalidArgumentError(fmt.Sprintf("truncate: %s is not one of day, hour, minute, or second. Please check your input string", opts["truncate"]))
+}
+
+// truncateTime zeroes every field of the time finer than unit, such as the hour, minute,
+// and second for day. Unlike Time.Truncate, this is done in the time's own zone, so a day
+// starts at midnight there rather than in UTC.
+func truncateTime(t time.Time, unit string) time.Time {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+	switch unit {
+	case "day":
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	case "hour":
+		return time.Date(year, month, day, hour, 0, 0, 0, t.Location())
+	case "minute":
+		return time.Date(year, month, day, hour, min, 0, 0, t.Location())
+	case "second":
+		return time.Date(year, month, day, hour, min, sec, 0, t.Location())
+	}
+	return t
+}
+
 func formatTime(t *time.Time, format string) string {
+	// Go's reference time has no way to express weeks, quarters, or the epoch, so they're
+	// computed
+	switch format {
+	case "isoweek":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case "quarter":
+		return fmt.Sprintf("%04d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	case "epoch", "unix":
+		return strconv.FormatInt(t.Unix(), 10)
+	case "epochmillis":
+		return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+	}
 	if f, ok := TimeFormats[format]; ok {
 		return t.Format(f)
 	}
 	return t.Format(format)
 }
 
-func guid(oc objectCache, opts cmdOptions) (string, error) {
+func duration(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	min, err := time.ParseDuration(opts["min"])
+	if err != nil {
+		return "", err
+	}
+	max, err := time.ParseDuration(opts["max"])
+	if err != nil {
+		return "", err
+	}
+	res, err := time.ParseDuration(opts["resolution"])
+	if err != nil {
+		return "", err
+	} else if res <= 0 {
+		return "", InvalidArgumentError("You have specified a resolution which is not a duration greater than zero. Please check your input string")
+	}
+	f := opts["format"]
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+	if ord >= 0 {
+		c := oc["duration"]
+		cache := c.([]time.Duration)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "duration", len(cache))
+		}
+		return formatDuration(cache[ord], f)
+	}
+	if min > max {
+		return "", InvalidArgumentError("You cannot generate a random duration whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+
+	// Step up from the minimum in multiples of the resolution, so the result stays
+	// in range while not being precise down to the nanosecond
+	steps := int64((max - min) / res)
+	d := min + time.Duration(rnd.Int63n(steps+1))*res
+	// store it in the cache
+	c := oc["duration"]
+	cache := c.([]time.Duration)
+	oc["duration"] = append(cache, d)
+
+	return formatDuration(d, f)
+}
+
+func formatDuration(d time.Duration, format string) (string, error) {
+	switch format {
+	case "string":
+		return d.String(), nil
+	case "s":
+		return strconv.FormatInt(int64(d/time.Second), 10), nil
+	case "ms":
+		return strconv.FormatInt(int64(d/time.Millisecond), 10), nil
+	}
+	return "", InvalidArgumentError(fmt.Sprintf("format: %s is not a known duration format. Use one of string, s, or ms", format))
+}
+
+func guid(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	format := opts["format"]
+	if _, err := formatGUID("", format); err != nil {
+		return "", err
+	}
 	ord, err := opts.getInt("ordinal")
 	if err != nil {
 		return "", err
@@ -590,9 +2329,10 @@ func guid(oc objectCache, opts cmdOptions) (string, error) {
 		c := oc["guid"]
 		cache := c.([]string)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for guids. Please check your input string", ord))
+			return "", ordinalError(ord, "guid", len(cache))
 		}
-		return cache[ord], nil
+		// The cache holds the dashed form, so a reference can use any format
+		return formatGUID(cache[ord], format)
 	}
 
 	guid := uuidv4()
@@ -601,18 +2341,126 @@ func guid(oc objectCache, opts cmdOptions) (string, error) {
 	cache := c.([]string)
 	oc["guid"] = append(cache, guid)
 
-	return guid, nil
+	return formatGUID(guid, format)
+}
+
+// formatGUID writes the dashed guid g in the given format
+func formatGUID(g string, format string) (string, error) {
+	switch format {
+	case "dashed":
+		return g, nil
+	case "compact":
+		return strings.Replace(g, "-", "", -1), nil
+	case "braced":
+		return "{" + g + "}", nil
+	case "urn":
+		return "urn:uuid:" + g, nil
+	}
+	return "", InvalidArgumentError(fmt.Sprintf("format: %s is not a known guid format. Use one of dashed, compact, braced, or urn", format))
+}
+
+func rownum(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	start, err := opts.getInt("start")
+	if err != nil {
+		return "", err
+	}
+	pad, err := opts.getInt("pad")
+	if err != nil {
+		return "", err
+	} else if pad < 0 {
+		return "", InvalidArgumentError("You have specified a padding which is not a number greater than or equal to zero. Please check your input string")
+	}
+	step, err := opts.getInt("step")
+	if err != nil {
+		return "", err
+	}
+	jitter, err := opts.getInt("jitter")
+	if err != nil {
+		return "", err
+	} else if jitter < 0 {
+		return "", InvalidArgumentError("You have specified a jitter which is not a number greater than or equal to zero. Please check your input string")
+	}
+	// The row is counted by the Callstack, so it's the same for every token in the line
+	n := start + (oc[rowKey].(int)-1)*step
+	if jitter > 0 {
+		// Each row is moved from where it would be on it's own, so the noise doesn't
+		// build up over a long run
+		n += rnd.Intn(2*jitter+1) - jitter
+	}
+	return fmt.Sprintf("%0*d", pad, n), nil
+}
+
+// The process unique value and counter which go into every ObjectID, following the
+// same scheme as the MongoDB drivers
+var (
+	objectIDProcess = randomBytes(5)
+	objectIDCounter = func() uint32 {
+		b := randomBytes(4)
+		return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	}()
+)
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(crand.Reader, b); err != nil {
+		// probably "shouldn't happen"
+		log.Fatal(err)
+	}
+	return b
+}
+
+func objectid(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+	if ord >= 0 {
+		c := oc["objectid"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "objectid", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	ts := time.Now().Unix()
+	if t := opts["time"]; t != "" {
+		if ts, err = strconv.ParseInt(t, 10, 64); err != nil {
+			return "", err
+		} else if ts < 0 || ts > math.MaxUint32 {
+			return "", InvalidArgumentError("You have specified a time which does not fit in an ObjectID. It must be a unix epoch value from 0 to 4294967295. Please check your input string")
+		}
+	}
+	// 4 bytes of timestamp, 5 bytes unique to the process, and a 3 byte counter
+	b := make([]byte, 12)
+	b[0] = byte(ts >> 24)
+	b[1] = byte(ts >> 16)
+	b[2] = byte(ts >> 8)
+	b[3] = byte(ts)
+	copy(b[4:9], objectIDProcess)
+	i := atomic.AddUint32(&objectIDCounter, 1)
+	b[9] = byte(i >> 16)
+	b[10] = byte(i >> 8)
+	b[11] = byte(i)
+	id := fmt.Sprintf("%x", b)
+
+	// store it in the cache
+	c := oc["objectid"]
+	cache := c.([]string)
+	oc["objectid"] = append(cache, id)
+
+	return id, nil
 }
 
-func firstname(oc objectCache, opts cmdOptions) (string, error) {
-	return name("firstname", FirstNames, oc, opts)
+func firstname(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return name(rnd, "firstname", FirstNames, oc, opts)
 }
 
-func lastname(oc objectCache, opts cmdOptions) (string, error) {
-	return name("lastname", LastNames, oc, opts)
+func lastname(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	return name(rnd, "lastname", LastNames, oc, opts)
 }
 
-func name(nameType string, names []*Name, oc objectCache, opts cmdOptions) (string, error) {
+func name(rnd *rand.Rand, nameType string, names []*Name, oc objectCache, opts cmdOptions) (string, error) {
 	cCase := opts["case"]
 	lang := opts["language"]
 	if !KnownLanguage(lang) {
@@ -627,7 +2475,7 @@ func name(nameType string, names []*Name, oc objectCache, opts cmdOptions) (stri
 		c := oc[nameType]
 		cache := c.([]string)
 		if len(cache)-1 < ord {
-			return "", InvalidArgumentError(fmt.Sprintf("Ordinal %d has not yet been encountered for %s values. Please check your input string", ord, nameType))
+			return "", ordinalError(ord, nameType, len(cache))
 		}
 		str := cache[ord]
 		// Names go into the cache as camel case, check if we need to swap it
@@ -640,7 +2488,7 @@ func name(nameType string, names []*Name, oc objectCache, opts cmdOptions) (stri
 	}
 
 	// Generate a new one
-	n := rand.Intn(len(names))
+	n := rnd.Intn(len(names))
 	name := names[n]
 	result := name.GetSpelling(lang)
 
@@ -2,14 +2,27 @@ package moldova
 
 import (
 	"bytes"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/big"
 	"math/rand"
+	"net"
 	"os"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
+
+	"github.com/StabbyCutyou/moldova/data"
 )
 
 type TestComparator func(string) error
@@ -45,10 +58,41 @@ var GUIDCases = []TestCase{
 			return errors.New("Guid at position 1 not equal to guid at position 0: " + p[0] + " " + p[1])
 		},
 	},
+	{
+		Template:   "{guid:format:compact}",
+		Comparator: matches(`^[0-9a-f]{12}4[0-9a-f]{3}[89ab][0-9a-f]{15}$`),
+	},
+	{
+		Template:   "{guid:format:braced}",
+		Comparator: matches(`^\{[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}\}$`),
+	},
+	{
+		Template:   "{guid:format:urn}",
+		Comparator: matches(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+	},
+	{
+		Template: "{guid:format:urn} {guid:ordinal:0|format:compact} {guid:ordinal:0|format:braced} {guid:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			dashed := p[3]
+			if p[0] == "urn:uuid:"+dashed && p[1] == strings.Replace(dashed, "-", "", -1) && p[2] == "{"+dashed+"}" {
+				return nil
+			}
+			return errors.New("Guid references did not agree across formats: " + s)
+		},
+	},
 	{
 		Template:     "{guid}@{guid:ordinal:1}",
 		WriteFailure: true,
 	},
+	{
+		Template:     "{guid:format:base64}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{guid} {guid:ordinal:0|format:base64}",
+		WriteFailure: true,
+	},
 }
 
 var NowCases = []TestCase{
@@ -79,6 +123,14 @@ var NowCases = []TestCase{
 		Template:     "{now}@{now:ordinal:1}",
 		WriteFailure: true,
 	},
+	{
+		Template:   "{now:truncate:day|format:150405.000} {now:truncate:second|format:.000}",
+		Comparator: matches(`^000000\.000 \.000$`),
+	},
+	{
+		Template:     "{now:truncate:week}",
+		WriteFailure: true,
+	},
 }
 
 var TimeCases = []TestCase{
@@ -119,6 +171,83 @@ var TimeCases = []TestCase{
 			return errors.New("Time at position 1 not equal to time at position 0: " + p[0] + " " + p[1])
 		},
 	},
+	{
+		Template:   "{time:min:1455512165|max:1455512165|format:isoweek|zone:UTC} {time:min:1455512165|max:1455512165|format:quarter|zone:UTC}",
+		Comparator: matches(`^2016-W07 2016-Q1$`),
+	},
+	{
+		// Midnight is in the zone of the time, which is still the day before in New York
+		Template:   "{time:min:1455512165|max:1455512165|zone:America/New_York|truncate:day} {time:ordinal:0|truncate:hour} {time:ordinal:0|truncate:minute}",
+		Comparator: matches(`^2016-02-14 00:00:00 2016-02-14 23:00:00 2016-02-14 23:56:00$`),
+	},
+	{
+		// Like the format, a reference doesn't take the truncate of the original
+		Template:   "{time:min:1455512165|max:1455512165|truncate:hour|format:epoch} {time:ordinal:0|format:epoch} {time:ordinal:0|truncate:day|format:epoch}",
+		Comparator: matches(`^1455508800 1455512165 1455494400$`),
+	},
+	{
+		Template:     "{time:truncate:fortnight}",
+		WriteFailure: true,
+	},
+	{
+		// The ISO week belongs to the year which holds most of it, so New Year's Day can
+		// still be in the last week of the year before
+		Template:   "{time:min:1609459200|max:1609459200|format:isoweek|zone:UTC} {time:min:1609459200|max:1609459200|format:quarter|zone:UTC}",
+		Comparator: matches(`^2020-W53 2021-Q1$`),
+	},
+	{
+		Template:   "{time:min:1483142400|max:1483142400|format:isoweek|zone:UTC} {time:min:1483142400|max:1483142400|format:quarter|zone:UTC}",
+		Comparator: matches(`^2016-W52 2016-Q4$`),
+	},
+	{
+		Template:   "{time:min:1467331200|max:1467331200|format:quarter|zone:UTC}",
+		Comparator: matches(`^2016-Q3$`),
+	},
+	{
+		Template:   "{time:min:1455512165|max:1455512165|format:epoch} {time:ordinal:0|format:unix} {time:ordinal:0|format:epochmillis}",
+		Comparator: matches(`^1455512165 1455512165 1455512165000$`),
+	},
+	{
+		// The epoch is the same no matter which zone the time is in
+		Template:   "{time:min:0|max:0|format:epoch|zone:Asia/Tokyo} {time:ordinal:0|format:epochmillis}",
+		Comparator: matches(`^0 0$`),
+	},
+	{
+		Template: "{now:format:epochmillis} {now:ordinal:0|format:epoch}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			ms, err := strconv.ParseInt(p[0], 10, 64)
+			if err != nil {
+				return err
+			}
+			if strconv.FormatInt(ms/1000, 10) == p[1] {
+				return nil
+			}
+			return errors.New("Now in milliseconds was not the same time as now in seconds: " + s)
+		},
+	},
+	{
+		Template:   "{now:format:isoweek} {now:format:quarter}",
+		Comparator: matches(`^[0-9]{4}-W[0-5][0-9] [0-9]{4}-Q[1-4]$`),
+	},
+	{
+		Template:   "{time:min:1455512165|max:1455512165|zone:EST} {time:ordinal:0|format:2006} {time:ordinal:0|format:quarter}",
+		Comparator: matches(`^2016-02-14 23:56:05 2016 2016-Q1$`),
+	},
+	{
+		Template: "{now:format:simpletz}@{now:ordinal:0|format:2006-01-02T15:04:05.000000000Z07:00}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			t, err := time.Parse(time.RFC3339Nano, p[1])
+			if err != nil {
+				return err
+			}
+			if t.Format("2006-01-02 15:04:05 -0700") == p[0] {
+				return nil
+			}
+			return errors.New("Now at position 1 was not the same time as now at position 0: " + p[0] + " " + p[1])
+		},
+	},
 	{
 		Template:     "{time}@{time:ordinal:1}",
 		WriteFailure: true,
@@ -174,6 +303,56 @@ var CountryCases = []TestCase{
 		Template:     "{country}@{country:ordinal:1}",
 		WriteFailure: true,
 	},
+	{
+		Template:   "{country:format:iso2}",
+		Comparator: matches(`^[A-Z]{2}$`),
+	},
+	{
+		Template:   "{country:format:iso3}",
+		Comparator: matches(`^[A-Z]{3}$`),
+	},
+	{
+		Template:   "{country:format:numeric}",
+		Comparator: matches(`^\d{3}$`),
+	},
+	{
+		Template: "{country:format:name}",
+		Comparator: func(s string) error {
+			for _, c := range data.Countries {
+				if c.Name == s {
+					return nil
+				}
+			}
+			return errors.New("Country name not found in the list of countries: " + s)
+		},
+	},
+	{
+		Template: "{country:format:iso3}@{country:ordinal:0}@{country:ordinal:0|format:numeric}@{country:ordinal:0|format:name|case:down}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			for _, c := range data.Countries {
+				if c.Alpha3 == p[0] {
+					if c.Alpha2 == p[1] && c.Numeric == p[2] && strings.ToLower(c.Name) == p[3] {
+						return nil
+					}
+					return errors.New("Country references did not all render the same country: " + s)
+				}
+			}
+			return errors.New("Country alpha-3 code not found in the list of countries: " + p[0])
+		},
+	},
+	{
+		Template:     "{country:format:iso4}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{country:weight:population|format:iso3}",
+		Comparator: matches(`^[A-Z]{3}$`),
+	},
+	{
+		Template:     "{country:weight:gdp}",
+		WriteFailure: true,
+	},
 }
 
 // Placeholders
@@ -231,6 +410,239 @@ var FloatCases = []TestCase{
 		Template:     "{float}@{float:ordinal:1}",
 		WriteFailure: true,
 	},
+	{
+		Template:   "{float:min:2.5|max:2.5}",
+		Comparator: matches(`^2\.500000$`),
+	},
+	{
+		Template:     "{float:min:2.5|max:2.5|inclusive:false}",
+		WriteFailure: true,
+	},
+	{
+		Template: "{float:min:-5|max:-4|inclusive:false|precision:10}",
+		Comparator: func(s string) error {
+			if n := mustParseFloat(s); n >= -5 && n < -4 {
+				return nil
+			}
+			return errors.New("Float out of range for an exclusive max: " + s)
+		},
+	},
+	{
+		Template:   "{float:min:4999.0|max:5000.0|format:f|precision:2}",
+		Comparator: floatWithin(4999.0, 5000.0, 0.005),
+	},
+	{
+		Template:   "{float:min:4999.0|max:5000.0|format:e|precision:3}",
+		Comparator: floatWithin(4999.0, 5000.0, 5.0),
+	},
+	{
+		Template: "{float:min:0|max:1000000|format:sci|precision:2}",
+		Comparator: func(s string) error {
+			if !strings.Contains(s, "e+") {
+				return errors.New("Float was not rendered in scientific notation: " + s)
+			}
+			return floatWithin(0.0, 1000000.0, 5000.0)(s)
+		},
+	},
+	{
+		Template:   "{float:min:4999.0|max:5000.0|format:g|precision:6}",
+		Comparator: floatWithin(4999.0, 5000.0, 0.05),
+	},
+	{
+		Template: "{float:format:e}@{float:ordinal:0|format:e}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Float at position 1 not equal to Float at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{float:format:hex}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{float:precision:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{float:min:1.5|max:1.5|precision:2|as:x} {float:ref:x|precision:5} {float:ref:x|format:e|precision:1}",
+		Comparator: matches(`^1\.50 1\.50000 1\.5e\+00$`),
+	},
+	{
+		Template: "{float:precision:3|as:x},{float:ref:x|precision:1},{float:ordinal:0|precision:1}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, ",")
+			f, err := strconv.ParseFloat(p[0], 64)
+			if err != nil {
+				return err
+			}
+			// The reference rounds the original value, not the rounded value which was written
+			if p[1] == p[2] && math.Abs(f-mustParseFloat(p[1])) <= 0.0505 {
+				return nil
+			}
+			return errors.New("Float references did not agree with the original value: " + s)
+		},
+	},
+	{
+		Template:   "{int:min:7|max:7|as:n} {float:ref:n|precision:1}",
+		Comparator: matches(`^7 7\.0$`),
+	},
+	{
+		Template:     "{float:ref:x}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{country:as:x} {float:ref:x}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{float:min:0.01|max:0.09|quantize:0.05|precision:2}",
+		Comparator: matches(`^0\.05$`),
+	},
+	{
+		Template:     "{float:min:0.01|max:0.04|quantize:0.05}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{float:quantize:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{float:quantize:nickel}",
+		ParseFailure: true,
+	},
+	{
+		Template:   "{float:min:0|max:1|precision:1|count:3|sep:/}",
+		Comparator: matches(`^[01]\.[0-9](/[01]\.[0-9]){2}$`),
+	},
+	{
+		Template:     "{float:count:-1}",
+		WriteFailure: true,
+	},
+}
+
+// matches returns a comparator asserting the output matches the provided pattern
+func matches(pattern string) TestComparator {
+	re := regexp.MustCompile(pattern)
+	return func(s string) error {
+		if re.MatchString(s) {
+			return nil
+		}
+		return fmt.Errorf("%s does not match the pattern %s", s, pattern)
+	}
+}
+
+// hasSuffix returns a comparator asserting the output ends with one of the suffixes
+func hasSuffix(suffixes ...string) TestComparator {
+	return func(s string) error {
+		for _, suffix := range suffixes {
+			if strings.HasSuffix(s, suffix) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s does not end with any of %v", s, suffixes)
+	}
+}
+
+// emojiCount returns a comparator asserting the output is exactly count runes, each of
+// which is an emoji from one of the given categories
+func emojiCount(count int, categories ...string) TestComparator {
+	return func(s string) error {
+		runes := []rune(s)
+		if len(runes) != count {
+			return fmt.Errorf("%s is %d runes long, expected %d", s, len(runes), count)
+		}
+		for _, r := range runes {
+			found := false
+			for _, c := range categories {
+				for _, rng := range data.EmojiRanges[c] {
+					if int(r) >= rng[0] && int(r) <= rng[1] {
+						found = true
+					}
+				}
+			}
+			if !found {
+				return fmt.Errorf("%U in %s is not an emoji from %v", r, s, categories)
+			}
+		}
+		return nil
+	}
+}
+
+// passwordPolicy returns a comparator asserting the output is exactly length characters
+// long, with at least the given number of characters from each class
+func passwordPolicy(length, upper, lower, digits, symbols int) TestComparator {
+	return func(s string) error {
+		if len(s) != length {
+			return fmt.Errorf("Password %s is not %d characters long", s, length)
+		}
+		var u, l, d, sym int
+		for _, c := range s {
+			switch {
+			case c >= 'A' && c <= 'Z':
+				u++
+			case c >= 'a' && c <= 'z':
+				l++
+			case c >= '0' && c <= '9':
+				d++
+			default:
+				sym++
+			}
+		}
+		if u < upper || l < lower || d < digits || sym < symbols {
+			return fmt.Errorf("Password %s does not meet the minimum counts of %d upper, %d lower, %d digits, and %d symbols", s, upper, lower, digits, symbols)
+		}
+		return nil
+	}
+}
+
+// floatWithin returns a comparator asserting the output parses back to a float
+// inside of [min, max], give or take the provided tolerance lost to formatting
+func floatWithin(min, max, tolerance float64) TestComparator {
+	return func(s string) error {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		if f >= min-tolerance && f <= max+tolerance {
+			return nil
+		}
+		return fmt.Errorf("Float %s out of range [%f, %f]", s, min, max)
+	}
+}
+
+var DistributionCases = []TestCase{
+	{
+		Template:   "{float:min:10|max:10|distribution:normal}",
+		Comparator: matches(`^10\.000000$`),
+	},
+	{
+		// Far outside of the range, so every value is clamped
+		Template:   "{float:min:0|max:1|distribution:normal|mean:50|stddev:0.1|precision:1}",
+		Comparator: matches(`^1\.0$`),
+	},
+	{
+		Template:   "{int:min:0|max:100|distribution:normal|mean:-50|stddev:1}",
+		Comparator: matches(`^0$`),
+	},
+	{
+		Template:   "{int:min:0|max:100|step:10|distribution:normal|mean:43|stddev:0}",
+		Comparator: matches(`^40$`),
+	},
+	{
+		Template:     "{float:distribution:lognormal}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{float:distribution:normal|stddev:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:distribution:normal|mean:middle}",
+		ParseFailure: true,
+	},
 }
 
 var IntegerCases = []TestCase{
@@ -287,6 +699,73 @@ var IntegerCases = []TestCase{
 		Template:     "{int}@{int:ordinal:1}",
 		WriteFailure: true,
 	},
+	{
+		Template:     "{int:min:0|max:100|step:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:min:1|max:100|step:5}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{int:min:-100|max:-50|step:25}",
+		Comparator: matches(`^-(100|75|50)$`),
+	},
+	{
+		Template:   "{int:min:7|max:7}",
+		Comparator: matches(`^7$`),
+	},
+	{
+		Template:   "{int:min:7|max:8|inclusive:false}",
+		Comparator: matches(`^7$`),
+	},
+	{
+		Template:   "{int:min:-8|max:-7|inclusive:false}",
+		Comparator: matches(`^-8$`),
+	},
+	{
+		Template:   "{int:min:0|max:50|step:25|inclusive:false}",
+		Comparator: matches(`^(0|25)$`),
+	},
+	{
+		Template:     "{int:min:7|max:7|inclusive:false}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:inclusive:maybe}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{int:min:1|max:9|count:5}",
+		Comparator: matches(`^[1-9](,[1-9]){4}$`),
+	},
+	{
+		Template: "{int:count:3|sep:;} {int:ordinal:2}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if strings.HasSuffix(p[0], ";"+p[1]) {
+				return nil
+			}
+			return errors.New("Int at position 1 not equal to the last value at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{int:count:0}",
+		WriteFailure: true,
+	},
+	{
+		// The number of values in the range doesn't fit in an int
+		Template:   "{int:min:0|max:9223372036854775807} {int:min:-9223372036854775807|max:9223372036854775807|step:9223372036854775807}",
+		Comparator: matches(`^[0-9]{1,19} (-9223372036854775807|0|9223372036854775807)$`),
+	},
+	{
+		Template:   "{int:min:9223372036854775807|max:9223372036854775807} {int:min:-9223372036854775808|max:-9223372036854775808}",
+		Comparator: matches(`^9223372036854775807 -9223372036854775808$`),
+	},
+	{
+		Template:     "{int:min:-9223372036854775808|max:9223372036854775807}",
+		WriteFailure: true,
+	},
 }
 
 var UnicodeCases = []TestCase{
@@ -331,9 +810,30 @@ var UnicodeCases = []TestCase{
 		Template:     "{unicode}@{unicode:ordinal:1}",
 		WriteFailure: true,
 	},
-}
-
-var ASCIICases = []TestCase{
+	{
+		Template:     "{unicode:minlen:10|maxlen:3}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{unicode:minlen:3}",
+		WriteFailure: true,
+	},
+	{
+		Template: "{unicode:minlen:4|maxlen:4}",
+		Comparator: func(s string) error {
+			if len([]rune(s)) == 4 {
+				return nil
+			}
+			return errors.New("Unicode string not the correct length")
+		},
+	},
+	{
+		Template:     "{unicode:ranges:runic}",
+		WriteFailure: true,
+	},
+}
+
+var ASCIICases = []TestCase{
 	{
 		Template: "{ascii}",
 		Comparator: func(s string) error {
@@ -375,6 +875,14 @@ var ASCIICases = []TestCase{
 		Template:     "{ascii}@{ascii:ordinal:1}",
 		WriteFailure: true,
 	},
+	{
+		Template:     "{ascii:minlen:-1|maxlen:3}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{ascii:length:8|maxlength:3} {ascii:minlen:5|maxlen:5|maxlength:2}",
+		Comparator: matches(`^[0-9a-y]{3} [0-9a-y]{2}$`),
+	},
 }
 
 var FirstNameCases = []TestCase{
@@ -441,111 +949,4941 @@ var FullNameCases = []TestCase{
 	},
 }
 
-var InvalidTokenCases = []TestCase{
+var CurrencyCases = []TestCase{
 	{
-		Template:     "{firstname} {plastname}",
+		Template:   "{currency}",
+		Comparator: matches(`^\$\d+\.\d{2}$`),
+	},
+	{
+		Template: "{currency:code:USD|min:10|max:50}",
+		Comparator: func(s string) error {
+			if err := matches(`^\$\d{2}\.\d{2}$`)(s); err != nil {
+				return err
+			}
+			return floatWithin(10.0, 50.0, 0.005)(strings.TrimPrefix(s, "$"))
+		},
+	},
+	{
+		Template:   "{currency:code:EUR}",
+		Comparator: matches(`^€\d+\.\d{2}$`),
+	},
+	{
+		Template:   "{currency:code:JPY|min:100|max:100000}",
+		Comparator: matches(`^¥\d+$`),
+	},
+	{
+		Template:   "{currency:code:kwd}",
+		Comparator: matches(`^KD \d+\.\d{3}$`),
+	},
+	{
+		Template:   "{currency:min:-50|max:-10}",
+		Comparator: matches(`^-\$\d{2}\.\d{2}$`),
+	},
+	{
+		Template: "{currency}@{currency:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Currency at position 1 not equal to currency at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{currency}@{currency:ordinal:1}",
 		WriteFailure: true,
 	},
 	{
-		Template:     "{firstname:language:onglish}",
+		Template:     "{currency:code:XYZ}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{currency:min:10|max:5}",
 		WriteFailure: true,
 	},
 }
 
-var AllCases = [][]TestCase{
-	GUIDCases,
-	NowCases,
-	TimeCases,
-	CountryCases,
-	FloatCases,
-	IntegerCases,
-	UnicodeCases,
-	ASCIICases,
-	FirstNameCases,
-	LastNameCases,
-	FullNameCases,
-	InvalidTokenCases,
-}
-
-// TODO Test each random function individually, under a number of inputs to make supported
-// all the options behave as expected.
-
-func TestMain(m *testing.M) {
-	rand.Seed(time.Now().Unix())
-	os.Exit(m.Run())
+var WeekdayCases = []TestCase{
+	{
+		Template:   "{weekday}",
+		Comparator: matches(`^(Sun|Mon|Tues|Wednes|Thurs|Fri|Satur)day$`),
+	},
+	{
+		Template:   "{weekday:abbrev:true}",
+		Comparator: matches(`^(Sun|Mon|Tue|Wed|Thu|Fri|Sat)$`),
+	},
+	{
+		Template:   "{weekday:number:true}",
+		Comparator: matches(`^[0-6]$`),
+	},
+	{
+		Template:   "{weekday:case:up|abbrev:true}",
+		Comparator: matches(`^(SUN|MON|TUE|WED|THU|FRI|SAT)$`),
+	},
+	{
+		Template:   "{weekday:language:german}",
+		Comparator: matches(`^(Sonntag|Montag|Dienstag|Mittwoch|Donnerstag|Freitag|Samstag)$`),
+	},
+	{
+		Template: "{weekday:number:true}@{weekday:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			n, err := strconv.Atoi(p[0])
+			if err != nil {
+				return err
+			}
+			if time.Weekday(n).String() == p[1] {
+				return nil
+			}
+			return errors.New("Weekday at position 1 is not the same day as weekday at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{weekday}@{weekday:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{weekday:abbrev:maybe}",
+		WriteFailure: true,
+	},
 }
 
-func TestAllCases(t *testing.T) {
-	// TODO The library should be threadsafe, I should go wide here to run all specs
-	// in parallel, like the natural tests would be. Channel + waitgroup to collect
-	// and report on errors once they all finish
-	for _, cs := range AllCases {
-		for _, c := range cs {
-			cs, err := BuildCallstack(c.Template)
-			// If we get an error and weren't expecting it
-			// Or, if we didn't get one but were expecting it
-			if err != nil && !c.ParseFailure {
-				t.Error(err)
-			} else if err == nil && c.ParseFailure {
-				t.Error("Expected to encounter Parse Failure, but did not for Test Case ", c.Template)
+var MonthCases = []TestCase{
+	{
+		Template:   "{month}",
+		Comparator: matches(`^(January|February|March|April|May|June|July|August|September|October|November|December)$`),
+	},
+	{
+		Template:   "{month:abbrev:true}",
+		Comparator: matches(`^(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)$`),
+	},
+	{
+		Template: "{month:number:true}",
+		Comparator: func(s string) error {
+			i, err := strconv.Atoi(s)
+			if err != nil {
+				return err
 			}
-
-			result := &bytes.Buffer{}
-			err = cs.Write(result)
-
-			// If we get an error and weren't expecting it
-			// Or, if we didn't get one but were expecting it
-			if err != nil && !c.WriteFailure {
-				t.Error(err)
-			} else if err == nil && c.WriteFailure {
-				t.Error("Expected to encounter Write Failure, but did not for Test Case ", c.Template)
+			if i >= 1 && i <= 12 {
+				return nil
 			}
-
-			if c.Comparator != nil {
-				if err := c.Comparator(result.String()); err != nil {
-					t.Error(err)
-				}
+			return errors.New("Month number out of range: " + s)
+		},
+	},
+	{
+		Template: "{month:number:true}@{month:ordinal:0|abbrev:true|case:down}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			n, err := strconv.Atoi(p[0])
+			if err != nil {
+				return err
 			}
-		}
-	}
+			if strings.ToLower(time.Month(n).String()[:3]) == p[1] {
+				return nil
+			}
+			return errors.New("Month at position 1 is not the same month as month at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{month}@{month:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{month:language:onglish}",
+		WriteFailure: true,
+	},
 }
 
-func TestGeneratedStringLength(t *testing.T) {
-	template := "Hey I'm {int:min:1|max:9} years old"
-	sampleresult := "Hey I'm 1 years old"
-	cs, err := BuildCallstack(template)
-	if err != nil {
-		t.Error(err)
-	}
-	result := &bytes.Buffer{}
-	err = cs.Write(result)
-	if err != nil {
-		t.Error(err)
-	}
-	if len([]rune(sampleresult)) != len([]rune(result.String())) {
-		fmt.Println(result.String())
-		t.Error("Missing parts of the rendered templtate")
-	}
+var AddressCases = []TestCase{
+	{
+		Template:   "{zipcode}",
+		Comparator: matches(`^\d{5}$`),
+	},
+	{
+		Template:   "{zipcode:country:us}",
+		Comparator: matches(`^\d{5}$`),
+	},
+	{
+		Template:   "{zipcode:country:CA}",
+		Comparator: matches(`^[A-Z]\d[A-Z] \d[A-Z]\d$`),
+	},
+	{
+		Template:   "{zipcode:country:GB}",
+		Comparator: matches(`^[A-Z]{1,2}\d [0-9][A-Z]{2}$`),
+	},
+	{
+		Template:   "{state}",
+		Comparator: matches(`^[A-Z]{2}$`),
+	},
+	{
+		Template:   "{streetaddress}",
+		Comparator: matches(`^\d{1,4} [A-Za-z ]+$`),
+	},
+	{
+		Template:   "{streetaddress:country:DE}",
+		Comparator: matches(`^[^\d]+ \d{1,4}$`),
+	},
+	{
+		Template: "{city}",
+		Comparator: func(s string) error {
+			if len(s) > 0 {
+				return nil
+			}
+			return errors.New("City string not the correct length")
+		},
+	},
+	{
+		Template: "{streetaddress:as:home}|{city:ref:home}|{state:ref:home}|{zipcode:ref:home}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "|")
+			for _, c := range data.AddressFormats["US"].Cities {
+				if c.Name == p[1] {
+					if c.Region != p[2] {
+						return errors.New("State does not match the referenced city: " + s)
+					}
+					if p[3][:3] != c.PostalMask[:3] {
+						return errors.New("Zipcode does not match the referenced city: " + s)
+					}
+					return nil
+				}
+			}
+			return errors.New("City not found in the list of US cities: " + s)
+		},
+	},
+	{
+		Template: "{city:as:a|country:FR}|{zipcode:ref:a}|{city:as:b}|{city:ref:a}|{city:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "|")
+			if p[0] == p[3] && p[0] == p[4] {
+				return nil
+			}
+			return errors.New("City at position 3 and 4 not equal to city at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{city}@{city:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{city:ref:nowhere}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{city:country:AQ}",
+		WriteFailure: true,
+	},
 }
 
-func BenchmarkGUID(b *testing.B) {
-	c := GUIDCases[0]
-	var cs *Callstack
-	var err error
-	if cs, err = BuildCallstack(c.Template); err != nil {
-		b.Error(err)
-	}
-	b.ResetTimer()
-	for n := 0; n < b.N; n++ {
-		result := &bytes.Buffer{}
-		err = cs.Write(result)
-		if err != nil {
-			b.Error(err)
-		}
-	}
+var CompanyCases = []TestCase{
+	{
+		Template:   "{company}",
+		Comparator: hasSuffix(data.CompanySuffixes...),
+	},
+	{
+		Template:   "{company:legal:LLC}",
+		Comparator: hasSuffix(" LLC"),
+	},
+	{
+		Template: "{company:legal:false}",
+		Comparator: func(s string) error {
+			if len(s) == 0 {
+				return errors.New("Company string not the correct length")
+			}
+			words := strings.Split(s, " ")
+			for _, suffix := range data.CompanySuffixes {
+				if words[len(words)-1] == suffix {
+					return errors.New("Company ended with a suffix, but none was requested: " + s)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Template:   "{company:case:up|legal:Inc}",
+		Comparator: matches(`^[A-Z ]+ INC$`),
+	},
+	{
+		Template: "{company}@{company:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Company at position 1 not equal to company at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{company}@{company:ordinal:1}",
+		WriteFailure: true,
+	},
 }
 
-func BenchmarkNow(b *testing.B) {
+var JobTitleCases = []TestCase{
+	{
+		Template: "{jobtitle}",
+		Comparator: func(s string) error {
+			if len(s) > 0 {
+				return nil
+			}
+			return errors.New("Job title string not the correct length")
+		},
+	},
+	{
+		Template:   "{jobtitle:level:junior}",
+		Comparator: matches(`^Junior [A-Za-z ]+$`),
+	},
+	{
+		Template:   "{jobtitle:level:senior|case:down}",
+		Comparator: matches(`^senior [a-z ]+$`),
+	},
+	{
+		Template:   "{jobtitle:level:lead}",
+		Comparator: matches(`^Lead [A-Za-z ]+$`),
+	},
+	{
+		Template:   "{jobtitle:level:none}",
+		Comparator: matches(`^[A-Z][a-z]+ `),
+	},
+	{
+		Template: "{jobtitle}@{jobtitle:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Job title at position 1 not equal to job title at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{jobtitle}@{jobtitle:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{jobtitle:level:intern}",
+		WriteFailure: true,
+	},
+}
+
+var UsernameCases = []TestCase{
+	{
+		Template:   "{username}",
+		Comparator: matches(`^[a-z0-9]+$`),
+	},
+	{
+		Template:   "{username:style:first_last|digits:3}",
+		Comparator: matches(`^[a-z0-9]+_[a-z0-9]+\d{3}$`),
+	},
+	{
+		Template:   "{username:style:first.last}",
+		Comparator: matches(`^[a-z0-9]+\.[a-z0-9]+$`),
+	},
+	{
+		Template: "{username:style:flast|digits:4|maxlength:6}",
+		Comparator: func(s string) error {
+			if err := matches(`^[a-z0-9]+$`)(s); err != nil {
+				return err
+			}
+			if len(s) <= 6 {
+				return nil
+			}
+			return errors.New("Username was longer than the maxlength: " + s)
+		},
+	},
+	{
+		Template: "{firstname} {lastname}|{username:style:first.last|firstname:0|lastname:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "|")
+			name := strings.Replace(strings.ToLower(p[0]), " ", ".", 1)
+			name = regexp.MustCompile(`[^a-z0-9.]`).ReplaceAllString(name, "")
+			if name == p[1] {
+				return nil
+			}
+			return errors.New("Username did not match the referenced names: " + s)
+		},
+	},
+	{
+		Template: "{username}@{username:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "@")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Username at position 1 not equal to username at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{username}@{username:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{username:firstname:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{username:style:lastfirst}",
+		WriteFailure: true,
+	},
+}
+
+var PasswordCases = []TestCase{
+	{
+		Template:   "{password}",
+		Comparator: passwordPolicy(12, 1, 1, 1, 1),
+	},
+	{
+		Template:   "{password:length:20|upper:5|lower:5|digits:5|symbols:5}",
+		Comparator: passwordPolicy(20, 5, 5, 5, 5),
+	},
+	{
+		Template:   "{password:length:8|upper:0|lower:0|digits:8|symbols:0}",
+		Comparator: passwordPolicy(8, 0, 0, 8, 0),
+	},
+	{
+		Template:   "{password:length:32|upper:3|lower:0|digits:2|symbols:0}",
+		Comparator: passwordPolicy(32, 3, 0, 2, 0),
+	},
+	{
+		Template: "{password} {password:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Password at position 1 not equal to password at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{password}@{password:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{password:length:4|upper:2|lower:2|digits:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{password:upper:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{password:length:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{password:length:-1|upper:0|lower:0|digits:0|symbols:0}",
+		WriteFailure: true,
+	},
+}
+
+var AgeCases = []TestCase{
+	{
+		Template:   "{age}",
+		Comparator: matches(`^(1[89]|[2-8][0-9]|90)$`),
+	},
+	{
+		Template:   "{age:min:30|max:30}",
+		Comparator: matches(`^30$`),
+	},
+	{
+		Template:   "{age:format:birthyear}",
+		Comparator: matches(`^(19|20)[0-9]{2}$`),
+	},
+	{
+		Template:   "{age:format:birthdate}",
+		Comparator: matches(`^[0-9]{4}-[0-9]{2}-[0-9]{2}$`),
+	},
+	{
+		Template:     "{age:format:zodiac}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{age:min:60|max:50}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{age:min:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{age} {age:ordinal:1}",
+		WriteFailure: true,
+	},
+}
+
+var ObjectIDCases = []TestCase{
+	{
+		Template:   "{objectid}",
+		Comparator: matches(`^[0-9a-f]{24}$`),
+	},
+	{
+		Template:   "{objectid:time:1455512165}",
+		Comparator: matches(`^56c15a65[0-9a-f]{16}$`),
+	},
+	{
+		Template:   "{objectid:time:0}",
+		Comparator: matches(`^00000000[0-9a-f]{16}$`),
+	},
+	{
+		Template: "{objectid} {objectid} {objectid:ordinal:1}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return errors.New("ObjectIDs at position 0 and 1 should not be equal: " + p[0])
+			}
+			if p[1] != p[2] {
+				return errors.New("ObjectID at position 2 not equal to ObjectID at position 1: " + p[1] + " " + p[2])
+			}
+			return nil
+		},
+	},
+	{
+		Template:     "{objectid} {objectid:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{objectid:time:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{objectid:time:4294967296}",
+		WriteFailure: true,
+	},
+}
+
+// From semver.org, for checking the generated versions
+const semverPattern = `^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)(?:-((?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*)(?:\.(?:0|[1-9]\d*|\d*[a-zA-Z-][0-9a-zA-Z-]*))*))?(?:\+([0-9a-zA-Z-]+(?:\.[0-9a-zA-Z-]+)*))?$`
+
+var SemverCases = []TestCase{
+	{
+		Template:   "{semver}",
+		Comparator: matches(semverPattern),
+	},
+	{
+		Template:   "{semver:prerelease:true|build:true}",
+		Comparator: matches(semverPattern),
+	},
+	{
+		Template:   "{semver:prerelease:true|build:true}",
+		Comparator: matches(`^[0-9]+\.[0-9]+\.[0-9]+-(alpha|beta|rc)\.[1-9]\+build[0-9]+$`),
+	},
+	{
+		Template:   "{semver:maxmajor:0|maxminor:0|maxpatch:3}",
+		Comparator: matches(`^0\.0\.[0-3]$`),
+	},
+	{
+		Template: "{semver} {semver:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Semver at position 1 not equal to semver at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{semver} {semver:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{semver:maxminor:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{semver:build:sometimes}",
+		WriteFailure: true,
+	},
+}
+
+var HTTPCases = []TestCase{
+	{
+		Template:   "{httpstatus}",
+		Comparator: matches(`^[1-5][0-9]{2}$`),
+	},
+	{
+		Template:   "{httpstatus:class:2xx}",
+		Comparator: matches(`^2[0-9]{2}$`),
+	},
+	{
+		Template:   "{httpstatus:class:5XX}",
+		Comparator: matches(`^5[0-9]{2}$`),
+	},
+	{
+		Template: "{httpstatus} {httpstatus:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Status at position 1 not equal to status at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{httpstatus:class:6xx}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{httpstatus} {httpstatus:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{httpmethod}",
+		Comparator: matches(`^(GET|POST|PUT|DELETE|PATCH)$`),
+	},
+	{
+		Template:   "{httpmethod:weights:GET=3,post=1}",
+		Comparator: matches(`^(GET|POST)$`),
+	},
+	{
+		Template:   "{httpmethod:weights:DELETE=1}",
+		Comparator: matches(`^DELETE$`),
+	},
+	{
+		Template: "{httpmethod} {httpmethod:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Method at position 1 not equal to method at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{httpmethod:weights:FETCH=1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{httpmethod:weights:GET}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{httpmethod:weights:GET=0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{httpmethod} {httpmethod:ordinal:1}",
+		WriteFailure: true,
+	},
+}
+
+var LatLngCases = []TestCase{
+	{
+		Template:   "{latlng}",
+		Comparator: matches(`^-?[0-9]{1,2}\.[0-9]{6},-?[0-9]{1,3}\.[0-9]{6}$`),
+	},
+	{
+		Template:   "{latlng:precision:2}",
+		Comparator: matches(`^-?[0-9]{1,2}\.[0-9]{2},-?[0-9]{1,3}\.[0-9]{2}$`),
+	},
+	{
+		Template:   "{latlng:minlat:10|maxlat:10|minlng:-20|maxlng:-20|precision:1}",
+		Comparator: matches(`^10\.0,-20\.0$`),
+	},
+	{
+		Template:   "{latlng:bbox:-74.3,40.5,-73.7,40.9}",
+		Comparator: matches(`^40\.[5-9][0-9]*,-7[34]\.[0-9]+$`),
+	},
+	{
+		Template: "{latlng} {latlng:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Coordinate at position 1 not equal to coordinate at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{latlng} {latlng:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latlng:maxlat:91}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latlng:minlng:-180.5}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latlng:minlat:50|maxlat:40}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latlng:bbox:1,2,3}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latlng:precision:-1}",
+		WriteFailure: true,
+	},
+}
+
+var DurationCases = []TestCase{
+	{
+		Template:   "{duration}",
+		Comparator: matches(`^([0-9]+h)?([0-9]+m)?[0-9]+s$`),
+	},
+	{
+		Template:   "{duration:min:90m|max:90m}",
+		Comparator: matches(`^1h30m0s$`),
+	},
+	{
+		Template:   "{duration:min:1s|max:2s|format:ms}",
+		Comparator: matches(`^(1000|2000)$`),
+	},
+	{
+		Template:   "{duration:min:1s|max:2s|resolution:100ms|format:ms}",
+		Comparator: matches(`^(1[0-9]00|2000)$`),
+	},
+	{
+		Template:   "{duration:min:1m|max:2m|format:s}",
+		Comparator: matches(`^([6-9][0-9]|1[01][0-9]|120)$`),
+	},
+	{
+		Template: "{duration} {duration:ordinal:0|format:s}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			d, err := time.ParseDuration(p[1] + "s")
+			if err != nil {
+				return err
+			}
+			if d.String() == p[0] {
+				return nil
+			}
+			return errors.New("Duration at position 1 not equal to duration at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{duration} {duration:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{duration:min:1h|max:1m}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{duration:max:forever}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{duration:resolution:0s}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{duration:format:days}",
+		WriteFailure: true,
+	},
+}
+
+var SlugCases = []TestCase{
+	{
+		Template:   "{slug}",
+		Comparator: matches(`^[a-z]+-[a-z]+-[a-z]+$`),
+	},
+	{
+		Template:   "{slug:words:1}",
+		Comparator: matches(`^[a-z]+$`),
+	},
+	{
+		Template:   "{slug:words:10|maxlength:20}",
+		Comparator: matches(`^[a-z][a-z-]{0,18}[a-z]$`),
+	},
+	{
+		Template: "{slug} {slug:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Slug at position 1 not equal to slug at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{slug} {slug:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{slug:words:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{slug:maxlength:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{firstname:as:f} {slug:from:f}",
+		Comparator: matches(`^.+ [a-z0-9-]+$`),
+	},
+	{
+		Template:   "{int:min:7|max:7|as:n} {slug:from:n}",
+		Comparator: matches(`^7 7$`),
+	},
+	{
+		Template:     "{slug:from:f}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{guid:as:g} {slug:from:g}",
+		Comparator: matches(`^([0-9a-f-]{36}) [0-9a-f]{8}(-[0-9a-f]{4}){3}-[0-9a-f]{12}$`),
+	},
+	{
+		Template:     "{latlng:as:p} {slug:from:p}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{lorem:language:german|count:20|as:l} {slug:from:l}",
+		Comparator: matches(`^.+ [a-z0-9-]+$`),
+	},
+	{
+		// There are no Latin letters to keep
+		Template:     "{lorem:language:russian|as:l} {slug:from:l}",
+		WriteFailure: true,
+	},
+}
+
+var FilenameCases = []TestCase{
+	{
+		Template:   "{filename}",
+		Comparator: matches(`^[a-z]+_[a-z]+\.(txt|pdf|jpg|png|csv|json)$`),
+	},
+	{
+		Template:   "{filename:ext:jpg,png,gif}",
+		Comparator: hasSuffix(".jpg", ".png", ".gif"),
+	},
+	{
+		Template:   "{filename:ext:.tar.gz|words:1|case:up}",
+		Comparator: matches(`^[A-Z]+\.tar\.gz$`),
+	},
+	{
+		Template:   "{filename:path:2|words:3|ext:log}",
+		Comparator: matches(`^[a-z]+/[a-z]+/[a-z]+_[a-z]+_[a-z]+\.log$`),
+	},
+	{
+		Template: "{filename} {filename:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Filename at position 1 not equal to filename at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{filename} {filename:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{filename:ext:,}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{filename:words:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{filename:path:-1}",
+		WriteFailure: true,
+	},
+}
+
+var EmojiCases = []TestCase{
+	{
+		Template:   "{emoji}",
+		Comparator: emojiCount(1, "faces", "animals", "food"),
+	},
+	{
+		Template:   "{emoji:count:5}",
+		Comparator: emojiCount(5, "faces", "animals", "food"),
+	},
+	{
+		Template:   "{emoji:count:3|category:animals}",
+		Comparator: emojiCount(3, "animals"),
+	},
+	{
+		Template:   "{emoji:category:food}",
+		Comparator: emojiCount(1, "food"),
+	},
+	{
+		Template:   "{emoji:count:2|category:faces}",
+		Comparator: emojiCount(2, "faces"),
+	},
+	{
+		Template: "{emoji:count:4} {emoji:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Emoji at position 1 not equal to emoji at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{emoji} {emoji:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{emoji:count:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{emoji:category:flags}",
+		WriteFailure: true,
+	},
+}
+
+var IBANCases = []TestCase{
+	{
+		Template:   "{iban}",
+		Comparator: matches(`^DE[0-9]{20}$`),
+	},
+	{
+		Template:   "{iban:country:gb}",
+		Comparator: matches(`^GB[0-9]{2}[A-Z]{4}[0-9]{14}$`),
+	},
+	{
+		Template: "{iban} {iban:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("IBAN at position 1 not equal to IBAN at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{iban} {iban:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{iban:country:US}",
+		WriteFailure: true,
+	},
+}
+
+var ISBNCases = []TestCase{
+	{
+		Template:   "{isbn}",
+		Comparator: matches(`^978[01][0-9]{9}$`),
+	},
+	{
+		Template:   "{isbn:version:10}",
+		Comparator: matches(`^[01][0-9]{8}[0-9X]$`),
+	},
+	{
+		Template:   "{isbn:hyphenate:true}",
+		Comparator: matches(`^978-[01]-[0-9]{2,7}-[0-9]{1,6}-[0-9]$`),
+	},
+	{
+		Template:   "{isbn:version:10|hyphenate:true}",
+		Comparator: matches(`^[01]-[0-9]{2,7}-[0-9]{1,6}-[0-9X]$`),
+	},
+	{
+		Template: "{isbn} {isbn:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("ISBN at position 1 not equal to ISBN at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{isbn} {isbn:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{isbn:version:12}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{isbn:hyphenate:maybe}",
+		WriteFailure: true,
+	},
+}
+
+var CommentCases = []TestCase{
+	{
+		Template:   "{#: this is ignored}",
+		Comparator: matches(`^$`),
+	},
+	{
+		Template:   "a{#: ranges match the schema, see ticket:42|max}b",
+		Comparator: matches(`^ab$`),
+	},
+	{
+		Template:   "{#}{int:min:5|max:5} {# the same int again}{int:ordinal:0}",
+		Comparator: matches(`^5 5$`),
+	},
+	{
+		Template: "{guid}{#:not a guid} {guid:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Comments should not affect ordinals: " + p[0] + " " + p[1])
+		},
+	},
+}
+
+var RepeatCases = []TestCase{
+	{
+		Template:   "{repeat:count:3|sep:,|tpl:{int:min:1|max:9}}",
+		Comparator: matches(`^[1-9],[1-9],[1-9]$`),
+	},
+	{
+		Template:   "[{repeat:count:2|sep:, |tpl:\"{country}-{int:min:5|max:5}\"}]",
+		Comparator: matches(`^\["[A-Z]{2}-5", "[A-Z]{2}-5"\]$`),
+	},
+	{
+		Template:   "{repeat:count:0|tpl:{guid}}",
+		Comparator: matches(`^$`),
+	},
+	{
+		Template:   "{repeat:count:4|tpl:x}",
+		Comparator: matches(`^xxxx$`),
+	},
+	{
+		// Ordinals only refer to values in the same repetition
+		Template: "{repeat:count:3|sep:;|tpl:{int:min:0|max:1000}={int:ordinal:0}}",
+		Comparator: func(s string) error {
+			for _, r := range strings.Split(s, ";") {
+				p := strings.Split(r, "=")
+				if p[0] != p[1] {
+					return errors.New("Int at position 1 not equal to int at position 0 within a repetition: " + r)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Template:   "{repeat:count:2|sep:\\||tpl:{repeat:count:2|sep:,|tpl:{int:min:7|max:7}}}",
+		Comparator: matches(`^7,7\|7,7$`),
+	},
+	{
+		Template:   "{repeat:count:2|tpl:\\{{int:min:3|max:3}\\}}",
+		Comparator: matches(`^\{3\}\{3\}$`),
+	},
+	{
+		Template: "{repeat:count:3|tpl:{guid}} {repeat:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Repeat at position 1 not equal to repeat at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{repeat:count:-1|tpl:x}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{repeat:count:2|tpl:{int:mni:4}}",
+		ParseFailure: true,
+	},
+	{
+		Template:     "{repeat:count:2|tpl:{int:min:4|max:1}}",
+		WriteFailure: true,
+	},
+	{
+		// Names stored outside of the template can be used inside of it, and the other
+		// way around
+		Template:   "{int:min:7|max:7|as:x}|{repeat:count:2|sep:,|tpl:{expr:value:x*2}}",
+		Comparator: matches(`^7\|14,14$`),
+	},
+	{
+		Template:   "{repeat:count:1|tpl:{int:min:3|max:3|as:y}} {expr:value:y+1}",
+		Comparator: matches(`^3 4$`),
+	},
+	{
+		Template:   "{repeat:count:3|sep:,|tpl:{rownum}}|{repeat:ordinal:0}",
+		Comparator: matches(`^1,2,3\|1,2,3$`),
+	},
+}
+
+var EscapeCases = []TestCase{
+	{
+		Template:   "\\{int\\} {int:min:5|max:5}",
+		Comparator: matches(`^\{int\} 5$`),
+	},
+	{
+		Template:   "C:\\temp\\\\{int:min:5|max:5}",
+		Comparator: matches(`^C:\\temp\\5$`),
+	},
+	{
+		Template:   "C:\\\\dir\\file.txt {int:min:5|max:5}",
+		Comparator: matches(`^C:\\\\dir\\file\.txt 5$`),
+	},
+	{
+		Template:   "C:\\\\dir\\",
+		Comparator: matches(`^C:\\\\dir\\$`),
+	},
+	{
+		Template:   "{repeat:count:1|tpl:a\\\\b\\{c\\}}",
+		Comparator: matches(`^a\\b\{c\}$`),
+	},
+	{
+		Template:   "{now:format:2006\\|01\\|02}",
+		Comparator: matches(`^[0-9]{4}\|[0-9]{2}\|[0-9]{2}$`),
+	},
+	{
+		Template:   "{#: a comment with {braces} in it}ok",
+		Comparator: matches(`^ok$`),
+	},
+}
+
+var NullCases = []TestCase{
+	{
+		Template:   "{int:min:5|max:5|nullprob:1}",
+		Comparator: matches(`^NULL$`),
+	},
+	{
+		Template:   "{int:min:5|max:5|nullprob:0}",
+		Comparator: matches(`^5$`),
+	},
+	{
+		Template:   "'{firstname:nullprob:1|nullvalue:null}'",
+		Comparator: matches(`^'null'$`),
+	},
+	{
+		Template:   "'{country:nullprob:1|nullvalue:}'",
+		Comparator: matches(`^''$`),
+	},
+	{
+		// The value is still generated, so the ordinal has something to refer to
+		Template:   "{int:min:5|max:5|nullprob:1},{int:ordinal:0}",
+		Comparator: matches(`^NULL,5$`),
+	},
+	{
+		Template:     "{int:nullprob:1.5}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:nullprob:often}",
+		ParseFailure: true,
+	},
+}
+
+var ExprCases = []TestCase{
+	{
+		Template:   "{int:min:5|max:5|as:x} {expr:value:x*2}",
+		Comparator: matches(`^5 10$`),
+	},
+	{
+		Template:   "{int:min:5|max:5|as:x} {expr:value:x + 3}",
+		Comparator: matches(`^5 8$`),
+	},
+	{
+		Template:   "{int:min:5|max:5|as:x} {expr:value:x-8}",
+		Comparator: matches(`^5 -3$`),
+	},
+	{
+		Template:   "{int:min:17|max:17|as:x} {expr:value:x/5}",
+		Comparator: matches(`^17 3$`),
+	},
+	{
+		Template:   "{int:min:17|max:17|as:x} {expr:value:x % 5}",
+		Comparator: matches(`^17 2$`),
+	},
+	{
+		Template:   "{int:min:2|max:2} {int:min:4|max:4} {expr:value:$0 + $1 * 10}",
+		Comparator: matches(`^2 4 42$`),
+	},
+	{
+		Template:   "{int:min:2|max:2|as:a} {int:min:4|max:4|as:b} {expr:value:(a + b) * -2}",
+		Comparator: matches(`^2 4 -12$`),
+	},
+	{
+		Template:   "{int:min:2|max:2|as:a} {expr:value:a*a|as:sq} {expr:value:sq+1} {expr:ordinal:1}",
+		Comparator: matches(`^2 4 5 5$`),
+	},
+	{
+		Template:     "{expr:value:y*2}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:x} {expr:value:x/0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:x} {expr:value:x*}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:x} {expr:value:(x*2}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:x} {expr:value:x^2}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int} {expr:value:$1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{streetaddress:as:home} {expr:value:home+1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{expr:value:1} {expr:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{expr:value:9223372036854775807 - 1} {expr:value:-9223372036854775807 - 1}",
+		Comparator: matches(`^9223372036854775806 -9223372036854775808$`),
+	},
+	{
+		Template:     "{expr:value:9223372036854775807 + 1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{expr:value:-9223372036854775807 - 2}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{expr:value:4294967296 * 4294967296}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{expr:value:-(-9223372036854775807 - 1)}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{expr:value:(-9223372036854775807 - 1) / -1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{expr:value:99999999999999999999}",
+		WriteFailure: true,
+	},
+}
+
+func TestExprUndefinedName(t *testing.T) {
+	cs, err := BuildCallstack("{int:as:x} {expr:value:x + y*2}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cs.Write(&bytes.Buffer{})
+	if err == nil {
+		t.Fatal("Expected an error for a name which has not been defined")
+	}
+	want := `value: y has not yet been defined with the as option at character 5 of the expression "x + y*2"`
+	if !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected the error to contain %q, got %s", want, err)
+	}
+	if strings.Contains(err.Error(), "ref:") {
+		t.Errorf("Expected the error not to name the ref option, got %s", err)
+	}
+}
+
+var CreditCardCases = []TestCase{
+	{
+		Template:   "{creditcard}",
+		Comparator: matches(`^[2-6][0-9]{14,15}$`),
+	},
+	{
+		Template:   "{creditcard:network:visa}",
+		Comparator: matches(`^4[0-9]{15}$`),
+	},
+	{
+		Template:   "{creditcard:network:AMEX}",
+		Comparator: matches(`^3[47][0-9]{13}$`),
+	},
+	{
+		Template:   "{cardtype}",
+		Comparator: matches(`^(visa|mastercard|amex|discover)$`),
+	},
+	{
+		Template:   "{creditcard:network:discover|as:cc},{cardtype:ref:cc}",
+		Comparator: matches(`^6[0-9]{15},discover$`),
+	},
+	{
+		Template: "{creditcard} {creditcard:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Card at position 1 not equal to card at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{creditcard} {creditcard:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{creditcard:network:diners}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{cardtype:ref:cc}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{streetaddress:as:cc} {cardtype:ref:cc}",
+		WriteFailure: true,
+	},
+}
+
+var InvalidTokenCases = []TestCase{
+	{
+		Template:     "{firstname} {plastname}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{firstname:language:onglish}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:mni:5}",
+		ParseFailure: true,
+	},
+	{
+		Template:     "{float:min:1.0|precission:2}",
+		ParseFailure: true,
+	},
+	{
+		Template:     "{guid:ordinal:0|case:up}",
+		ParseFailure: true,
+	},
+	{
+		Template:     "{country:format:iso3|weights:population}",
+		ParseFailure: true,
+	},
+	{
+		Template:     "{time:min}",
+		ParseFailure: true,
+	},
+}
+
+var GenderCases = []TestCase{
+	{
+		Template:   "{gender}",
+		Comparator: matches(`^(male|female|nonbinary)$`),
+	},
+	{
+		Template:   "{gender:format:short}",
+		Comparator: matches(`^[MFX]$`),
+	},
+	{
+		Template:   "{gender:format:numeric}",
+		Comparator: matches(`^[123]$`),
+	},
+	{
+		Template:   "{gender:values:woman,man|format:short}",
+		Comparator: matches(`^[WM]$`),
+	},
+	{
+		Template:   "{gender:weights:0,1,0}",
+		Comparator: matches(`^female$`),
+	},
+	{
+		Template:   "{gender:values:f,m,u|weights:0,0,2|format:numeric}",
+		Comparator: matches(`^3$`),
+	},
+	{
+		Template:   "{gender:weights:1,0,0} {gender:ordinal:0|format:short} {gender:ordinal:0|format:numeric}",
+		Comparator: matches(`^male M 1$`),
+	},
+	{
+		Template:     "{gender} {gender:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{gender:format:initial}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{gender:weights:1,1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{gender:weights:-1,1,1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{gender:weights:0,0,0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{gender:values:male,,female}",
+		WriteFailure: true,
+	},
+}
+
+var PortCases = []TestCase{
+	{
+		Template:   "{port}",
+		Comparator: matches(`^[1-9][0-9]{0,4}$`),
+	},
+	{
+		Template:   "{port:min:8080|max:8080}",
+		Comparator: matches(`^8080$`),
+	},
+	{
+		Template:   "{port:preset:ephemeral|max:49152}",
+		Comparator: matches(`^49152$`),
+	},
+	{
+		Template:   "{port:preset:privileged|min:1023}",
+		Comparator: matches(`^1023$`),
+	},
+	{
+		Template: "{port} {port:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Port at position 1 not equal to port at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{port} {port:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{port:min:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{port:max:65536}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{port:min:9000|max:8000}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{port:preset:privileged|min:2000}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{port:preset:dynamic}",
+		WriteFailure: true,
+	},
+}
+
+var WordCases = []TestCase{
+	{
+		Template:   "{word}",
+		Comparator: matches(`^[a-z]+$`),
+	},
+	{
+		Template:   "{word:case:up}",
+		Comparator: matches(`^[A-Z]+$`),
+	},
+	{
+		Template:   "{word:minlen:4|maxlen:6}",
+		Comparator: matches(`^[a-z]{4,6}$`),
+	},
+	{
+		Template:   "{word:minlen:1|maxlen:1}",
+		Comparator: matches(`^a$`),
+	},
+	{
+		Template: "{word} {word:ordinal:0|case:up}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if strings.ToUpper(p[0]) == p[1] {
+				return nil
+			}
+			return errors.New("Word at position 1 not equal to word at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{word} {word:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{word:minlen:50}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{word:minlen:6|maxlen:5}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{word:maxlen:-1}",
+		WriteFailure: true,
+	},
+}
+
+var NanoIDCases = []TestCase{
+	{
+		Template:   "{nanoid}",
+		Comparator: matches(`^[A-Za-z0-9_-]{21}$`),
+	},
+	{
+		Template:   "{nanoid:size:8|alphabet:0123456789abcdef}",
+		Comparator: matches(`^[0-9a-f]{8}$`),
+	},
+	{
+		Template:   "{nanoid:size:5|alphabet:αβ}",
+		Comparator: matches(`^[αβ]{5}$`),
+	},
+	{
+		Template: "{nanoid} {nanoid:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("NanoID at position 1 not equal to NanoID at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{nanoid} {nanoid:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{nanoid:size:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{nanoid:alphabet:x}",
+		WriteFailure: true,
+	},
+}
+
+var ContinentCases = []TestCase{
+	{
+		Template:   "{continent}",
+		Comparator: matches(`^(Africa|Antarctica|Asia|Europe|North America|Oceania|South America)$`),
+	},
+	{
+		Template:   "{continent:case:up}",
+		Comparator: matches(`^[A-Z ]+$`),
+	},
+	{
+		Template:   "{country:format:continent}",
+		Comparator: matches(`^(Africa|Antarctica|Asia|Europe|North America|Oceania|South America)$`),
+	},
+	{
+		Template: "{country:format:continent|as:c}|{continent:ref:c}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "|")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Continent of the referenced country did not agree: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:   "{continent} {continent:ordinal:0|case:down}",
+		Comparator: matches(`^([A-Za-z]+( [A-Za-z]+)?) [a-z ]+$`),
+	},
+	{
+		Template:     "{continent} {continent:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{continent:ref:c}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:c} {continent:ref:c}",
+		WriteFailure: true,
+	},
+}
+
+var RowNumberCases = []TestCase{
+	{
+		Template:   "{rownum}",
+		Comparator: matches(`^1$`),
+	},
+	{
+		Template:   "{rownum:start:0} {rownum:start:100}",
+		Comparator: matches(`^0 100$`),
+	},
+	{
+		Template:   "{rownum:start:42|pad:5}",
+		Comparator: matches(`^00042$`),
+	},
+	{
+		Template:   "{repeat:count:3|sep:,|tpl:{rownum}}",
+		Comparator: matches(`^1,2,3$`),
+	},
+	{
+		Template:   "{repeat:count:3|sep:,|tpl:{rownum:start:10|step:-5}}",
+		Comparator: matches(`^10,5,0$`),
+	},
+	{
+		Template:   "{rownum:start:100|jitter:3}",
+		Comparator: matches(`^(9[7-9]|10[0-3])$`),
+	},
+	{
+		Template:     "{rownum:pad:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{rownum:jitter:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{rownum:step:fast}",
+		ParseFailure: true,
+	},
+	{
+		Template:     "{rownum:ordinal:0}",
+		ParseFailure: true,
+	},
+}
+
+var BoolCases = []TestCase{
+	{
+		Template:   "{bool}",
+		Comparator: matches(`^(true|false)$`),
+	},
+	{
+		Template:   "{bool:format:numeric|probability:1}",
+		Comparator: matches(`^1$`),
+	},
+	{
+		Template:   "{bool:format:yesno|probability:0}",
+		Comparator: matches(`^no$`),
+	},
+	{
+		Template:   "{bool:probability:1} {bool:ordinal:0|format:yesno}",
+		Comparator: matches(`^true yes$`),
+	},
+	{
+		Template:   "{bool:probability:1|as:active} {maybe:ref:active|then:YES|else:NO}",
+		Comparator: matches(`^true YES$`),
+	},
+	{
+		Template:   "{bool:probability:0|as:active} {maybe:ref:active|then:YES|else:NO}",
+		Comparator: matches(`^false NO$`),
+	},
+	{
+		Template:   "[{bool:probability:0|as:a}{maybe:ref:a|then:x}]",
+		Comparator: matches(`^\[false\]$`),
+	},
+	{
+		Template:     "{bool} {bool:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{bool:probability:1.5}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{bool:format:onoff}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{maybe:ref:active|then:YES|else:NO}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:active} {maybe:ref:active|then:YES|else:NO}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{maybe:then:YES}",
+		WriteFailure: true,
+	},
+}
+
+var SSNCases = []TestCase{
+	{
+		Template:   "{ssn}",
+		Comparator: matches(`^[0-8][0-9]{2}-[0-9]{2}-[0-9]{4}$`),
+	},
+	{
+		Template:   "{ssn:format:plain}",
+		Comparator: matches(`^[0-8][0-9]{8}$`),
+	},
+	{
+		Template: "{ssn:format:plain} {ssn:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == strings.Replace(p[1], "-", "", -1) {
+				return nil
+			}
+			return errors.New("SSN at position 1 not equal to SSN at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{ssn} {ssn:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{ssn:format:spaced}",
+		WriteFailure: true,
+	},
+}
+
+var HashtagCases = []TestCase{
+	{
+		Template:   "{hashtag}",
+		Comparator: matches(`^#[a-z]+$`),
+	},
+	{
+		Template:   "{hashtag:count:3}",
+		Comparator: matches(`^#[a-z]+ #[a-z]+ #[a-z]+$`),
+	},
+	{
+		Template:   "{hashtag:words:3}",
+		Comparator: matches(`^#[a-z]+[A-Z][a-z]*[A-Z][a-z]*$`),
+	},
+	{
+		Template:   "{hashtag:count:2|words:2}",
+		Comparator: matches(`^#[a-z]+[A-Z][a-z]* #[a-z]+[A-Z][a-z]*$`),
+	},
+	{
+		Template: "{hashtag} {hashtag:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Hashtag at position 1 not equal to hashtag at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{hashtag} {hashtag:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{hashtag:count:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{hashtag:words:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{word:as:w} {hashtag:from:w}",
+		Comparator: matches(`^([a-z]+) #([a-z]+)$`),
+	},
+	{
+		Template:     "{hashtag:from:w}",
+		WriteFailure: true,
+	},
+}
+
+var LoremCases = []TestCase{
+	{
+		Template:   "{lorem}",
+		Comparator: matches(`^[a-z]+( [a-z]+){7}$`),
+	},
+	{
+		Template:   "{lorem:words:3|case:up}",
+		Comparator: matches(`^[A-Z]+ [A-Z]+ [A-Z]+$`),
+	},
+	{
+		Template:   "{lorem:words:4|language:russian}",
+		Comparator: matches(`^\p{Cyrillic}+( \p{Cyrillic}+){3}$`),
+	},
+	{
+		Template:   "{lorem:words:4|language:greek}",
+		Comparator: matches(`^\p{Greek}+( \p{Greek}+){3}$`),
+	},
+	{
+		Template:   "{lorem:words:2|language:French}",
+		Comparator: matches(`^\p{L}+ \p{L}+$`),
+	},
+	{
+		Template: "{lorem:words:2|language:russian} {lorem:ordinal:0|case:up}",
+		Comparator: func(s string) error {
+			p := strings.SplitN(s, " ", 3)
+			if strings.ToUpper(p[0]+" "+p[1]) == p[2] {
+				return nil
+			}
+			return errors.New("Lorem at position 1 not equal to lorem at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{lorem} {lorem:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{lorem:words:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{lorem:language:klingon}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{lorem:unit:sentence|count:2|words:3}",
+		Comparator: matches(`^[A-Z][a-z]* [a-z]+ [a-z]+\. [A-Z][a-z]* [a-z]+ [a-z]+\.$`),
+	},
+	{
+		Template:   "{lorem:unit:sentence|words:2|language:russian}",
+		Comparator: matches(`^\p{Lu}\p{Ll}* \p{Ll}+\.$`),
+	},
+	{
+		Template:   "{lorem:unit:paragraph|count:3|sentences:2|words:2|wrap:html}",
+		Comparator: matches(`^(<p>[A-Z][a-z]* [a-z]+\. [A-Z][a-z]* [a-z]+\.</p>){3}$`),
+	},
+	{
+		Template:   "{lorem:unit:paragraph|count:3|sentences:1|words:2|wrap:markdown}",
+		Comparator: matches(`^[A-Z][a-z]* [a-z]+\.\n\n[A-Z][a-z]* [a-z]+\.\n\n[A-Z][a-z]* [a-z]+\.$`),
+	},
+	{
+		Template:   "{lorem:unit:paragraph|count:2|sentences:1|words:1}",
+		Comparator: matches(`^[A-Z][a-z]*\. [A-Z][a-z]*\.$`),
+	},
+	{
+		Template:   "{lorem:words:3|wrap:html|case:up}",
+		Comparator: matches(`^<p>[A-Z]+ [A-Z]+ [A-Z]+</p>$`),
+	},
+	{
+		// References apply their own wrapping to the same paragraphs
+		Template: "{lorem:unit:paragraph|count:2|sentences:2|words:3|wrap:markdown} {lorem:ordinal:0|wrap:html}",
+		Comparator: func(s string) error {
+			p := strings.SplitN(s, " <p>", 2)
+			if strings.Replace(p[0], "\n\n", "</p><p>", -1)+"</p>" == p[1] {
+				return nil
+			}
+			return errors.New("Lorem at position 1 not the same paragraphs as lorem at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{lorem:unit:chapter}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{lorem:wrap:latex}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{lorem:unit:paragraph|count:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{lorem:unit:paragraph|sentences:0}",
+		WriteFailure: true,
+	},
+}
+
+var LiteralCases = []TestCase{
+	{
+		Template:   "hello world",
+		Comparator: matches(`^hello world$`),
+	},
+	{
+		Template:   "a stray } is written as it is",
+		Comparator: matches(`^a stray \} is written as it is$`),
+	},
+	{
+		Template:   "",
+		Comparator: matches(`^$`),
+	},
+	{
+		Template:   "tabs\tand\nlines",
+		Comparator: matches(`^tabs\tand\nlines$`),
+	},
+}
+
+var VINCases = []TestCase{
+	{
+		Template:   "{vin}",
+		Comparator: matches(`^[0-9A-HJ-NPR-Z]{8}[0-9X][A-HJ-NPR-TV-Y1-9][0-9A-HJ-NPR-Z]{7}$`),
+	},
+	{
+		Template: "{vin} {vin:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("VIN at position 1 not equal to VIN at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{vin} {vin:ordinal:1}",
+		WriteFailure: true,
+	},
+}
+
+var LicensePlateCases = []TestCase{
+	{
+		Template:   "{licenseplate}",
+		Comparator: matches(`^[0-9][A-Z]{3}[0-9]{3}$`),
+	},
+	{
+		Template:   "{licenseplate:region:UK}",
+		Comparator: matches(`^[A-Z]{2}[0-9]{2} [A-Z]{3}$`),
+	},
+	{
+		Template:   "{licenseplate:region:fr}",
+		Comparator: matches(`^[A-Z]{2}-[0-9]{3}-[A-Z]{2}$`),
+	},
+	{
+		Template:   "{licenseplate:region:US-NY}",
+		Comparator: matches(`^[A-Z]{3}-[0-9]{4}$`),
+	},
+	{
+		Template: "{licenseplate:region:DE}|{licenseplate:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, "|")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("License plate at position 1 not equal to license plate at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{licenseplate} {licenseplate:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{licenseplate:region:US-ZZ}",
+		WriteFailure: true,
+	},
+}
+
+var TimezoneCases = []TestCase{
+	{
+		Template:   "{timezone}",
+		Comparator: matches(`^[A-Z][a-z]+/[A-Za-z_/]+$`),
+	},
+	{
+		Template:   "{latlng:bbox:-74.006,40.7128,-74.006,40.7128|as:p} {timezone:ref:p}",
+		Comparator: matches(` America/New_York$`),
+	},
+	{
+		Template: "{timezone} {timezone:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Timezone at position 1 not equal to timezone at position 0: " + p[0] + " " + p[1])
+		},
+	},
+	{
+		Template:     "{timezone} {timezone:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{timezone:ref:p}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:p} {timezone:ref:p}",
+		WriteFailure: true,
+	},
+}
+
+var StatusCases = []TestCase{
+	{
+		Template:   "{status}",
+		Comparator: matches(`^(pending|shipped|cancelled)$`),
+	},
+	{
+		Template:   "{status:set:priority|case:up}",
+		Comparator: matches(`^(LOW|MEDIUM|HIGH|CRITICAL)$`),
+	},
+	{
+		Template:   "{status:set:httpclass}",
+		Comparator: matches(`^[2-5]xx$`),
+	},
+	{
+		Template:   "{status:set:Ticket}",
+		Comparator: matches(`^(open|in progress|resolved|closed)$`),
+	},
+	{
+		Template: "{status:set:payment} {status:ordinal:0|case:up}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if strings.ToUpper(p[0]) == p[1] {
+				return nil
+			}
+			return errors.New("Status at position 1 not equal to status at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{status} {status:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{status:set:mood}",
+		WriteFailure: true,
+	},
+}
+
+var MaxLengthCases = []TestCase{
+	{
+		Template:   "{lorem:words:20|maxlength:10}",
+		Comparator: matches(`^.{10}$`),
+	},
+	{
+		Template: "{lorem:words:20|language:russian|maxlength:4}",
+		Comparator: func(s string) error {
+			if runeLength(s) == 4 {
+				return nil
+			}
+			return errors.New("Lorem was not cut down to 4 characters: " + s)
+		},
+	},
+	{
+		// The value is generated at it's full length, then cut down
+		Template: "{unicode:length:50|maxlength:10} {unicode:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if runeLength(p[0]) == 10 && runeLength(p[1]) == 50 && strings.HasPrefix(p[1], p[0]) {
+				return nil
+			}
+			return errors.New("Unicode string was not cut down to it's maxlength: " + s)
+		},
+	},
+	{
+		Template:   "{int:min:12345|max:12345|maxlength:3}",
+		Comparator: matches(`^123$`),
+	},
+	{
+		Template:   "{country:format:name|maxlength:1|case:up}",
+		Comparator: matches(`^[A-Z]$`),
+	},
+	{
+		Template:   "{int:min:12345|max:12345|maxlength:0}",
+		Comparator: matches(`^12345$`),
+	},
+	{
+		// The nullvalue is written as it is
+		Template:   "{int:nullprob:1|nullvalue:nothing|maxlength:2}",
+		Comparator: matches(`^nothing$`),
+	},
+	{
+		// Ordinals refer to the whole value
+		Template: "{guid:maxlength:8} {guid:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if len(p[0]) == 8 && len(p[1]) == 36 && strings.HasPrefix(p[1], p[0]) {
+				return nil
+			}
+			return errors.New("Guid at position 1 not the whole of guid at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{int:maxlength:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:maxlength:short}",
+		ParseFailure: true,
+	},
+}
+
+var AffixCases = []TestCase{
+	{
+		Template:   "{int:min:1|max:9|prefix:ID-|suffix:!}",
+		Comparator: matches(`^ID-[1-9]!$`),
+	},
+	{
+		Template:   "{country:prefix:(|suffix:)}",
+		Comparator: matches(`^\([A-Z]{2}\)$`),
+	},
+	{
+		Template:   "{guid:format:compact|prefix:urn:id:}",
+		Comparator: matches(`^urn:id:[0-9a-f]{32}$`),
+	},
+	{
+		Template:   "{float:min:2.5|max:2.5|precision:1|suffix:%}",
+		Comparator: matches(`^2\.5%$`),
+	},
+	{
+		// References take their own prefix and suffix, if any
+		Template:   "{int:min:7|max:7|prefix:#} {int:ordinal:0} {int:ordinal:0|prefix:<|suffix:>}",
+		Comparator: matches(`^#7 7 <7>$`),
+	},
+	{
+		// The maxlength is of the value alone
+		Template:   "{int:min:12345|max:12345|maxlength:2|prefix:ID-}",
+		Comparator: matches(`^ID-12$`),
+	},
+	{
+		Template:   "{int:nullprob:1|prefix:ID-}",
+		Comparator: matches(`^NULL$`),
+	},
+	{
+		// The legal designation of {company} comes before the suffix
+		Template:   "{company:legal:Ltd|prefix:The |suffix:!}",
+		Comparator: matches(`^The [A-Za-z ]+ Ltd!$`),
+	},
+	{
+		Template:   "{company:legal:false|suffix:!}",
+		Comparator: matches(`^[A-Za-z]+( [A-Za-z]+)?!$`),
+	},
+}
+
+var BloodTypeCases = []TestCase{
+	{
+		Template:   "{bloodtype}",
+		Comparator: matches(`^(A|B|AB|O)[+-]$`),
+	},
+	{
+		Template:   "{bloodtype:format:abo}",
+		Comparator: matches(`^(A|B|AB|O)$`),
+	},
+	{
+		Template: "{bloodtype} {bloodtype:ordinal:0|format:abo}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0][:len(p[0])-1] == p[1] {
+				return nil
+			}
+			return errors.New("Blood type at position 1 not the group of blood type at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{bloodtype} {bloodtype:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{bloodtype:format:rh}",
+		WriteFailure: true,
+	},
+}
+
+var GitSHACases = []TestCase{
+	{
+		Template:   "{gitsha}",
+		Comparator: matches(`^[0-9a-f]{40}$`),
+	},
+	{
+		Template:   "{gitsha:short:true}",
+		Comparator: matches(`^[0-9a-f]{7}$`),
+	},
+	{
+		Template: "{gitsha} {gitsha:ordinal:0|short:true}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if strings.HasPrefix(p[0], p[1]) && len(p[1]) == 7 {
+				return nil
+			}
+			return errors.New("Short gitsha at position 1 not the start of gitsha at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{gitsha} {gitsha:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{gitsha:short:sometimes}",
+		WriteFailure: true,
+	},
+}
+
+var ShortCodeCases = []TestCase{
+	{
+		Template:   "{base36}",
+		Comparator: matches(`^[0-9a-z]{8}$`),
+	},
+	{
+		Template:   "{base36:length:12|case:up}",
+		Comparator: matches(`^[0-9A-Z]{12}$`),
+	},
+	{
+		Template:   "{base62:length:20}",
+		Comparator: matches(`^[0-9A-Za-z]{20}$`),
+	},
+	{
+		Template: "{base36} {base36:ordinal:0|case:up}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if strings.ToUpper(p[0]) == p[1] {
+				return nil
+			}
+			return errors.New("Base36 at position 1 not equal to base36 at position 0: " + s)
+		},
+	},
+	{
+		Template: "{base62} {base62:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Base62 at position 1 not equal to base62 at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{base62} {base62:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{base36:length:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{base62:case:up}",
+		ParseFailure: true,
+	},
+}
+
+var IMEICases = []TestCase{
+	{
+		Template:   "{imei}",
+		Comparator: matches(`^[0-9]{15}$`),
+	},
+	{
+		Template:   "{imei:tac:realistic}",
+		Comparator: matches(`^(35|86|01|44|49)[0-9]{13}$`),
+	},
+	{
+		Template:   "{imei:tac:35332509}",
+		Comparator: matches(`^35332509[0-9]{7}$`),
+	},
+	{
+		Template: "{imei} {imei:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("IMEI at position 1 not equal to IMEI at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{imei} {imei:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{imei:tac:3533250}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{imei:tac:3533250x}",
+		WriteFailure: true,
+	},
+}
+
+var HostnameCases = []TestCase{
+	{
+		Template:   "{hostname}",
+		Comparator: matches(`^[a-z]{3,}-[a-z]{3,}-[0-9]{1,2}$`),
+	},
+	{
+		Template:   "{hostname:style:role|domain:Example.com}",
+		Comparator: matches(`^[a-z]+[0-9]{2}\.example\.com$`),
+	},
+	{
+		Template:   "{hostname:domain:internal}",
+		Comparator: matches(`^[a-z]+-[a-z]+-[0-9]+\.internal$`),
+	},
+	{
+		Template: "{hostname} {hostname:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("Hostname at position 1 not equal to hostname at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{hostname} {hostname:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{hostname:style:pets}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{hostname:domain:-bad.com}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{hostname:domain:under_score.com}",
+		WriteFailure: true,
+	},
+}
+
+var JWTCases = []TestCase{
+	{
+		Template:   "{jwt}",
+		Comparator: matches(`^eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]{86}\.[A-Za-z0-9_-]{43}$`),
+	},
+	{
+		Template:   "{jwt:alg:rs256|payload:12}",
+		Comparator: matches(`^eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]{16}\.[A-Za-z0-9_-]{342}$`),
+	},
+	{
+		Template: "{jwt} {jwt:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[1] {
+				return nil
+			}
+			return errors.New("JWT at position 1 not equal to JWT at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{jwt} {jwt:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{jwt:alg:none}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{jwt:payload:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{jwt:payload:lots}",
+		ParseFailure: true,
+	},
+}
+
+var UniqueCases = []TestCase{
+	{
+		Template:   "{int:min:1|max:3|unique:true} {int:ordinal:0}",
+		Comparator: matches(`^([1-3]) ([1-3])$`),
+	},
+	{
+		Template:     "{int:unique:maybe}",
+		WriteFailure: true,
+	},
+}
+
+var FractionCases = []TestCase{
+	{
+		Template:   "{fraction}",
+		Comparator: matches(`^[0-4]/4$`),
+	},
+	{
+		Template:   "{fraction:denominator:10|min:0.3|max:0.5}",
+		Comparator: matches(`^[3-5]/10$`),
+	},
+	{
+		Template:     "{fraction:denominator:3|min:0.5|max:0.5}",
+		WriteFailure: true,
+	},
+	{
+		Template:   "{fraction:format:percent|denominator:100|min:0.75|max:0.75}",
+		Comparator: matches(`^75%$`),
+	},
+	{
+		Template:   "{fraction:format:percent|denominator:3|min:0.3|max:0.4|precision:2}",
+		Comparator: matches(`^33\.33%$`),
+	},
+	{
+		Template:   "{fraction:denominator:4|min:0.75|max:0.75} {fraction:ordinal:0|format:percent}",
+		Comparator: matches(`^3/4 75%$`),
+	},
+	{
+		Template:     "{fraction} {fraction:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{fraction:denominator:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{fraction:min:-0.5}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{fraction:min:0.8|max:0.2}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{fraction:format:ratio}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{fraction:denominator:half}",
+		ParseFailure: true,
+	},
+}
+
+var PercentageCases = []TestCase{
+	{
+		Template:   "{percentage}",
+		Comparator: matches(`^(100\.00|[0-9]{1,2}\.[0-9]{2})$`),
+	},
+	{
+		Template:   "{percentage:symbol:true|precision:0}",
+		Comparator: matches(`^(100|[0-9]{1,2})%$`),
+	},
+	{
+		Template:   "{percentage:min:25|max:25|precision:1}",
+		Comparator: matches(`^25\.0$`),
+	},
+	{
+		Template:   "{percentage:min:12.5|max:12.5} {percentage:ordinal:0|symbol:true|precision:3}",
+		Comparator: matches(`^12\.50 12\.500%$`),
+	},
+	{
+		Template:     "{percentage} {percentage:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{percentage:max:101}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{percentage:min:60|max:40}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{percentage:precision:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{percentage:symbol:yes please}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{percentage:min:low}",
+		ParseFailure: true,
+	},
+}
+
+var BarcodeCases = []TestCase{
+	{
+		Template:   "{ean13}",
+		Comparator: matches(`^[0-9]{13}$`),
+	},
+	{
+		Template:   "{upc}",
+		Comparator: matches(`^[0-9]{12}$`),
+	},
+	{
+		Template:   "{ean13:company:400638133393} {upc:company:03600029145}",
+		Comparator: matches(`^4006381333931 036000291452$`),
+	},
+	{
+		Template:   "{ean13:company:50}",
+		Comparator: matches(`^50[0-9]{11}$`),
+	},
+	{
+		Template: "{ean13} {upc} {ean13:ordinal:0} {upc:ordinal:0}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == p[2] && p[1] == p[3] {
+				return nil
+			}
+			return errors.New("Barcodes at positions 2 and 3 not equal to those at positions 0 and 1: " + s)
+		},
+	},
+	{
+		Template:     "{ean13} {ean13:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{upc:company:036000291452}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{ean13:company:40-06}",
+		WriteFailure: true,
+	},
+}
+
+var LatencyCases = []TestCase{
+	{
+		Template:   "{latency}",
+		Comparator: matches(`^[0-9]+$`),
+	},
+	{
+		Template:   "{latency:precision:2}",
+		Comparator: matches(`^[0-9]+\.[0-9]{2}$`),
+	},
+	{
+		Template:   "{latency:p50:250|p99:250}",
+		Comparator: matches(`^250$`),
+	},
+	{
+		Template:   "{latency:p50:12.5|p99:12.5|precision:1} {latency:ordinal:0|precision:3}",
+		Comparator: matches(`^12\.5 12\.500$`),
+	},
+	{
+		Template:     "{latency} {latency:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latency:p50:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latency:p50:500|p99:100}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latency:precision:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{latency:p99:slow}",
+		ParseFailure: true,
+	},
+}
+
+var MeasureCases = []TestCase{
+	{
+		Template:   "{measure}",
+		Comparator: matches(`^[0-9]{1,3}\.[0-9]$`),
+	},
+	{
+		Template:   "{measure:unit:celsius|min:23.4|max:23.4|symbol:true}",
+		Comparator: matches(`^23\.4°C$`),
+	},
+	{
+		Template:   "{measure:unit:fahrenheit|in:celsius|min:100|max:100|precision:0|symbol:true}",
+		Comparator: matches(`^212°F$`),
+	},
+	{
+		Template:   "{measure:unit:kelvin|in:Celsius|min:0|max:0|precision:2|symbol:true}",
+		Comparator: matches(`^273\.15 K$`),
+	},
+	{
+		Template:   "{measure:unit:kilometer|in:mile|min:1|max:1|precision:6|symbol:true}",
+		Comparator: matches(`^1\.609344 km$`),
+	},
+	{
+		Template:   "{measure:min:37|max:37} {measure:ordinal:0|unit:fahrenheit|symbol:true}",
+		Comparator: matches(`^37\.0 98\.6°F$`),
+	},
+	{
+		Template:   "{measure:unit:pound|min:1|max:1|precision:0} {measure:ordinal:0|unit:gram|precision:2}",
+		Comparator: matches(`^1 453\.59$`),
+	},
+	{
+		Template:     "{measure} {measure:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{measure:unit:kilogram} {measure:ordinal:0|unit:meter}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{measure:unit:furlong}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{measure:unit:celsius|in:meter}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{measure:min:10|max:5}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{measure:precision:-1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{measure:max:hot}",
+		ParseFailure: true,
+	},
+}
+
+var MacAddrCases = []TestCase{
+	{
+		Template:   "{macaddr}",
+		Comparator: matches(`^[0-9a-f][02468ace](:[0-9a-f]{2}){5}$`),
+	},
+	{
+		Template:   "{macaddr:vendor:VMware|case:up}",
+		Comparator: matches(`^00:(05:69|0C:29|50:56)(:[0-9A-F]{2}){3}$`),
+	},
+	{
+		Template: "{macaddr:vendor:xen} {macaddr:ordinal:0|case:up}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if strings.ToUpper(p[0]) == p[1] {
+				return nil
+			}
+			return errors.New("MAC address at position 1 not equal to MAC address at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{macaddr} {macaddr:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{macaddr:vendor:acme}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{macaddr:case:title}",
+		WriteFailure: true,
+	},
+}
+
+var SentenceCases = []TestCase{
+	{
+		Template:   "{sentence}",
+		Comparator: matches(`^[A-Z][a-z]*( [a-z]+){3,11}\.$`),
+	},
+	{
+		Template:   "{sentence:min:2|max:2|end:?!}",
+		Comparator: matches(`^[A-Z][a-z]* [a-z]+[?!]$`),
+	},
+	{
+		Template:   "{sentence:min:1|max:20|distribution:normal|mean:3|stddev:0}",
+		Comparator: matches(`^[A-Z][a-z]*( [a-z]+){2}\.$`),
+	},
+	{
+		Template:   "{sentence:language:german|case:up}",
+		Comparator: matches(`^[^a-z]+\.$`),
+	},
+	{
+		Template: "{sentence} {sentence:ordinal:0}",
+		Comparator: func(s string) error {
+			// Each sentence ends in a period, and nothing else does
+			p := strings.SplitAfter(s, ". ")
+			if len(p) == 2 && p[0] == p[1]+" " {
+				return nil
+			}
+			return errors.New("Sentence at position 1 not equal to sentence at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{sentence} {sentence:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{sentence:min:0}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{sentence:min:5|max:4}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{sentence:language:klingon}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{sentence:distribution:poisson}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{sentence:max:many}",
+		ParseFailure: true,
+	},
+}
+
+var MoneyCases = []TestCase{
+	{
+		Template:   "{money}",
+		Comparator: matches(`^\$(0|[1-9][0-9]{0,2}(,[0-9]{3})?)\.[0-9]{2}$`),
+	},
+	{
+		Template:   "{money:code:USD|locale:en-US|min:1000|max:1000000}",
+		Comparator: matches(`^\$[1-9][0-9]{0,2}(,[0-9]{3}){1,2}\.[0-9]{2}$`),
+	},
+	{
+		Template:   "{money:code:EUR|locale:de_de|min:1000000|max:9999999}",
+		Comparator: matches(`^[1-9](\.[0-9]{3}){2},[0-9]{2} €$`),
+	},
+	{
+		Template:   "{money:code:JPY|locale:de-DE|min:-5000|max:-1000}",
+		Comparator: matches(`^-[1-5]\.[0-9]{3} ¥$`),
+	},
+	{
+		Template:   "{money:code:CHF|locale:de-CH|min:1234.5|max:1234.5}",
+		Comparator: matches(`^CHF 1’234\.50$`),
+	},
+	{
+		Template:   "{money:code:EUR|locale:fr-FR|min:12345.67|max:12345.67}",
+		Comparator: matches("^12\u202f345,67 €$"),
+	},
+	{
+		Template:   "{money:code:BRL|locale:pt-BR|min:0.5|max:0.5}",
+		Comparator: matches(`^R\$ 0,50$`),
+	},
+	{
+		Template:   "{money:min:1234567.89|max:1234567.89} {money:ordinal:0|locale:de-DE}",
+		Comparator: matches(`^\$1,234,567\.89 1\.234\.567,89 \$$`),
+	},
+	{
+		Template:     "{money} {money:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{money:code:XYZ}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{money:locale:en}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{money:locale:xx-XX}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{money:min:10|max:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{money:max:lots}",
+		ParseFailure: true,
+	},
+}
+
+var CallingCodeCases = []TestCase{
+	{
+		Template:   "{callingcode}",
+		Comparator: matches(`^\+[1-9][0-9]{0,3}$`),
+	},
+	{
+		Template:   "{callingcode:format:idd} {callingcode:format:digits}",
+		Comparator: matches(`^00[1-9][0-9]{0,3} [1-9][0-9]{0,3}$`),
+	},
+	{
+		Template: "{callingcode} {callingcode:ordinal:0|format:digits}",
+		Comparator: func(s string) error {
+			p := strings.Split(s, " ")
+			if p[0] == "+"+p[1] {
+				return nil
+			}
+			return errors.New("Calling code at position 1 not equal to calling code at position 0: " + s)
+		},
+	},
+	{
+		Template:     "{callingcode} {callingcode:ordinal:1}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{callingcode:format:e164}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{callingcode:ref:c}",
+		WriteFailure: true,
+	},
+	{
+		Template:     "{int:as:c} {callingcode:ref:c}",
+		WriteFailure: true,
+	},
+}
+
+var AllCases = [][]TestCase{
+	GUIDCases,
+	NowCases,
+	TimeCases,
+	CountryCases,
+	FloatCases,
+	IntegerCases,
+	UnicodeCases,
+	ASCIICases,
+	FirstNameCases,
+	LastNameCases,
+	FullNameCases,
+	CurrencyCases,
+	WeekdayCases,
+	MonthCases,
+	AddressCases,
+	CompanyCases,
+	JobTitleCases,
+	UsernameCases,
+	PasswordCases,
+	DistributionCases,
+	AgeCases,
+	ObjectIDCases,
+	SemverCases,
+	HTTPCases,
+	LatLngCases,
+	DurationCases,
+	SlugCases,
+	FilenameCases,
+	EmojiCases,
+	IBANCases,
+	ISBNCases,
+	CommentCases,
+	RepeatCases,
+	EscapeCases,
+	NullCases,
+	ExprCases,
+	CreditCardCases,
+	GenderCases,
+	PortCases,
+	WordCases,
+	NanoIDCases,
+	ContinentCases,
+	RowNumberCases,
+	BoolCases,
+	SSNCases,
+	HashtagCases,
+	LoremCases,
+	LiteralCases,
+	VINCases,
+	LicensePlateCases,
+	TimezoneCases,
+	StatusCases,
+	MaxLengthCases,
+	AffixCases,
+	BloodTypeCases,
+	GitSHACases,
+	ShortCodeCases,
+	IMEICases,
+	HostnameCases,
+	JWTCases,
+	UniqueCases,
+	FractionCases,
+	PercentageCases,
+	BarcodeCases,
+	LatencyCases,
+	MeasureCases,
+	MacAddrCases,
+	SentenceCases,
+	MoneyCases,
+	CallingCodeCases,
+	InvalidTokenCases,
+}
+
+// TODO Test each random function individually, under a number of inputs to make supported
+// all the options behave as expected.
+
+// mustParseFloat parses a float written by a test case, which is known to be valid
+func mustParseFloat(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestMain(m *testing.M) {
+	rand.Seed(time.Now().Unix())
+	os.Exit(m.Run())
+}
+
+func TestAllCases(t *testing.T) {
+	// TODO The library should be threadsafe, I should go wide here to run all specs
+	// in parallel, like the natural tests would be. Channel + waitgroup to collect
+	// and report on errors once they all finish
+	for _, cs := range AllCases {
+		for _, c := range cs {
+			cs, err := BuildCallstack(c.Template)
+			// If we get an error and weren't expecting it
+			// Or, if we didn't get one but were expecting it
+			if err != nil && !c.ParseFailure {
+				t.Error(err)
+			} else if err == nil && c.ParseFailure {
+				t.Error("Expected to encounter Parse Failure, but did not for Test Case ", c.Template)
+			}
+			// There is nothing to write if the template could not be parsed
+			if err != nil {
+				continue
+			}
+
+			result := &bytes.Buffer{}
+			err = cs.Write(result)
+
+			// If we get an error and weren't expecting it
+			// Or, if we didn't get one but were expecting it
+			if err != nil && !c.WriteFailure {
+				t.Error(err)
+			} else if err == nil && c.WriteFailure {
+				t.Error("Expected to encounter Write Failure, but did not for Test Case ", c.Template)
+			}
+
+			if c.Comparator != nil {
+				if err := c.Comparator(result.String()); err != nil {
+					t.Error(err)
+				}
+			}
+		}
+	}
+}
+
+func TestPopulationWeightedCountry(t *testing.T) {
+	cs, err := BuildCallstack("{country:weight:population}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	result := &bytes.Buffer{}
+	iterations := 10000
+	for i := 0; i < iterations; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		counts[result.String()]++
+		result.Reset()
+	}
+	// China and India hold over a third of the population in the data set, so they
+	// should come up far more often than the roughly 1 in 130 a uniform pick would give
+	if share := float64(counts["CN"]+counts["IN"]) / float64(iterations); share < 0.25 {
+		t.Errorf("Expected populous countries to make up at least 25%% of results, got %f", share)
+	}
+	if counts["CN"] <= counts["NZ"] || counts["IN"] <= counts["IS"] {
+		t.Error("Expected populous countries to appear more frequently than sparse ones")
+	}
+	// Entries with no population should never be chosen
+	for _, c := range []string{"AQ", "EU", "UN"} {
+		if counts[c] > 0 {
+			t.Errorf("Expected %s to never be chosen, but it was chosen %d times", c, counts[c])
+		}
+	}
+}
+
+func TestWeightedIndex(t *testing.T) {
+	weights := []float64{0, 1, 0, 3, 0}
+	counts := make([]int, len(weights))
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := 0; i < 10000; i++ {
+		counts[weightedIndex(rnd, weights)]++
+	}
+	if counts[0] > 0 || counts[2] > 0 || counts[4] > 0 {
+		t.Error("Expected indexes with no weight to never be chosen", counts)
+	}
+	if counts[3] < 2*counts[1] {
+		t.Error("Expected the index with 3 times the weight to be chosen far more often", counts)
+	}
+}
+
+func TestSeed(t *testing.T) {
+	template := "{int}|{float}|{country:weight:population}|{unicode}|{ascii}|{firstname}|{weekday}|{time}|{currency}"
+	results := make([]string, 2)
+	for i := range results {
+		cs, err := BuildCallstack(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs.Seed(1234)
+		result := &bytes.Buffer{}
+		for j := 0; j < 10; j++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		results[i] = result.String()
+	}
+	if results[0] != results[1] {
+		t.Error("Expected two Callstacks with the same seed to produce the same results: " + results[0] + " " + results[1])
+	}
+}
+
+func TestLengthRange(t *testing.T) {
+	for _, template := range []string{
+		"{unicode:minlen:3|maxlen:10}",
+		"{ascii:minlen:3|maxlen:10}",
+	} {
+		cs, err := BuildCallstack(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lengths := make(map[int]bool)
+		result := &bytes.Buffer{}
+		for i := 0; i < 1000; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			l := len([]rune(result.String()))
+			if l < 3 || l > 10 {
+				t.Fatalf("%s generated a string of length %d, outside of the range 3 to 10", template, l)
+			}
+			lengths[l] = true
+			result.Reset()
+		}
+		// Both ends of the range should be reachable
+		if !lengths[3] || !lengths[10] {
+			t.Errorf("%s did not generate strings at both ends of the range: %v", template, lengths)
+		}
+	}
+}
+
+func TestIntegerStep(t *testing.T) {
+	cs, err := BuildCallstack("{int:min:0|max:100|step:5}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	result := &bytes.Buffer{}
+	for i := 0; i < 2000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		n, err := strconv.Atoi(result.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n < 0 || n > 100 || n%5 != 0 {
+			t.Fatalf("Generated %d, which is not a multiple of 5 from 0 to 100", n)
+		}
+		seen[n] = true
+		result.Reset()
+	}
+	// Every step, including both bounds, should be reachable
+	if len(seen) != 21 {
+		t.Errorf("Expected all 21 steps from 0 to 100 to be generated, got %d of them", len(seen))
+	}
+}
+
+func TestIntegerIncludesMax(t *testing.T) {
+	for _, c := range []struct {
+		template string
+		seen     map[string]bool
+	}{
+		{"{int:min:4999|max:5000}", map[string]bool{"4999": true, "5000": true}},
+		{"{int:min:-5001|max:-5000}", map[string]bool{"-5001": true, "-5000": true}},
+		{"{int:min:-1|max:1}", map[string]bool{"-1": true, "0": true, "1": true}},
+		{"{int:min:4999|max:5000|inclusive:false}", map[string]bool{"4999": true}},
+		{"{int:min:-5001|max:-5000|inclusive:false}", map[string]bool{"-5001": true}},
+	} {
+		cs, err := BuildCallstack(c.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]bool)
+		result := &bytes.Buffer{}
+		for i := 0; i < 200; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			seen[result.String()] = true
+			result.Reset()
+		}
+		if len(seen) != len(c.seen) {
+			t.Errorf("Expected %s to generate %v, got %v", c.template, c.seen, seen)
+			continue
+		}
+		for v := range seen {
+			if !c.seen[v] {
+				t.Errorf("Expected %s to generate %v, got %v", c.template, c.seen, seen)
+				break
+			}
+		}
+	}
+}
+
+// fixedSource always returns the same value, to reach the ends of a range which are too
+// unlikely to be generated in a test
+type fixedSource int64
+
+func (f fixedSource) Int63() int64 { return int64(f) }
+func (f fixedSource) Seed(int64)   {}
+
+func TestFloatIncludesMax(t *testing.T) {
+	// The largest value the source can give for an inclusive float
+	cs, err := BuildCallstack("{float:min:-5|max:5} {float:min:-5|max:5|inclusive:false}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SetSource(fixedSource(1 << 53))
+	result := &bytes.Buffer{}
+	if err := cs.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	p := strings.Split(result.String(), " ")
+	if p[0] != "5.000000" {
+		t.Errorf("Expected an inclusive float to reach it's max of 5, got %s", p[0])
+	}
+	if n := mustParseFloat(p[1]); n >= 5 {
+		t.Errorf("Expected an exclusive float to stay below it's max of 5, got %s", p[1])
+	}
+}
+
+func TestFloatQuantize(t *testing.T) {
+	steps := []float64{0.05, 0.25, 0.1, 3}
+	cs, err := BuildCallstack("{float:min:-10|max:10|quantize:0.05|precision:2} {float:min:0|max:1|quantize:0.25|inclusive:false} {float:min:0.3|max:0.7|quantize:0.1|distribution:normal} {float:min:1|max:100|quantize:3|precision:0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for j, v := range strings.Split(result.String(), " ") {
+			n := mustParseFloat(v)
+			if k := n / steps[j]; math.Abs(k-math.Round(k)) > 1e-6 {
+				t.Errorf("%s is not a multiple of %g", v, steps[j])
+			}
+			if j == 1 && n >= 1 {
+				t.Errorf("Expected an exclusive float to stay below it's max of 1, got %s", v)
+			}
+			if j == 2 && (n < 0.3-1e-9 || n > 0.7+1e-9) {
+				t.Errorf("Expected %s to be from 0.3 to 0.7", v)
+			}
+		}
+		result.Reset()
+	}
+}
+
+func TestMultipleValues(t *testing.T) {
+	cs, err := BuildCallstack("{int:min:1|max:9|count:5}|{float:min:-1|max:1|precision:2|count:4|sep: }|{int:min:0|max:10|step:5|count:12|sep:;|distribution:normal}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 200; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for j, v := range strings.Split(result.String(), "|") {
+			check := []struct {
+				sep   string
+				count int
+				min   float64
+				max   float64
+			}{{",", 5, 1, 9}, {" ", 4, -1, 1}, {";", 12, 0, 10}}[j]
+			values := strings.Split(v, check.sep)
+			if len(values) != check.count {
+				t.Errorf("Expected %d values in %s, got %d", check.count, v, len(values))
+			}
+			for _, n := range values {
+				if f := mustParseFloat(n); f < check.min || f > check.max {
+					t.Errorf("%s is outside of %g to %g", n, check.min, check.max)
+				} else if j == 2 && int(f)%5 != 0 {
+					t.Errorf("%s is not a step of 5", n)
+				}
+			}
+		}
+		result.Reset()
+	}
+}
+
+func TestTimeTruncate(t *testing.T) {
+	cs, err := BuildCallstack("{time:zone:Asia/Kolkata|truncate:day|format:simpletz} {time:zone:America/St_Johns|truncate:hour|format:simpletz} {time:zone:UTC|truncate:minute|format:simpletz}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 500; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), " ")
+		for j := 0; j < 3; j++ {
+			ts, err := time.Parse("2006-01-02 15:04:05 -0700", strings.Join(p[j*3:j*3+3], " "))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// Each truncates one more field than the last
+			fields := []int{ts.Second(), ts.Minute(), ts.Hour()}[:3-j]
+			for _, f := range fields {
+				if f != 0 {
+					t.Errorf("Expected the truncated fields of %s to be zero", ts)
+				}
+			}
+		}
+		result.Reset()
+	}
+}
+
+func TestTimeReferencesReformat(t *testing.T) {
+	cs, err := BuildCallstack("{time:zone:America/New_York|format:simpletz}|{time:ordinal:0|format:epoch}|{time:ordinal:0|format:2006-01-02T15:04:05Z07:00}|{now:format:epochmillis}|{now:ordinal:0|format:2006-01-02T15:04:05.000Z07:00}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 200; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "|")
+		// The offset is needed to tell apart the two times an hour repeats when DST ends
+		simple, err := time.Parse("2006-01-02 15:04:05 -0700", p[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		rfc, err := time.Parse(time.RFC3339, p[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if epoch := strconv.FormatInt(simple.Unix(), 10); epoch != p[1] || !rfc.Equal(simple) {
+			t.Errorf("Expected every format to be the same time, got %s", result.String())
+		}
+		now, err := time.Parse("2006-01-02T15:04:05.000Z07:00", p[4])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if millis := strconv.FormatInt(now.UnixNano()/int64(time.Millisecond), 10); millis != p[3] {
+			t.Errorf("Expected both formats of now to be the same time, got %s", result.String())
+		}
+		result.Reset()
+	}
+}
+
+func TestAgeMatchesBirthdate(t *testing.T) {
+	cs, err := BuildCallstack("{age:min:0|max:100} {age:ordinal:0|format:birthyear} {age:ordinal:0|format:birthdate}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), " ")
+		a, err := strconv.Atoi(p[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		year, err := strconv.Atoi(p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		birthdate, err := time.Parse("2006-01-02", p[2])
+		if err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now().UTC()
+		if a < 0 || a > 100 {
+			t.Errorf("Generated an age of %d, which is outside of 0 to 100", a)
+		}
+		// Depending on whether their birthday has passed yet this year
+		if year != now.Year()-a && year != now.Year()-a-1 {
+			t.Errorf("Birth year %d does not agree with an age of %d", year, a)
+		}
+		if year != birthdate.Year() || ageOn(birthdate, now) != a {
+			t.Errorf("Birthdate %s does not agree with an age of %d", p[2], a)
+		}
+		result.Reset()
+	}
+}
+
+func TestObjectIDTimestamp(t *testing.T) {
+	for _, ts := range []int64{0, 1, 1455512165, time.Now().Unix(), math.MaxUint32} {
+		cs, err := BuildCallstack(fmt.Sprintf("{objectid:time:%d}", ts))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := strconv.ParseInt(result.String()[:8], 16, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if decoded != ts {
+			t.Errorf("Expected ObjectID %s to hold the timestamp %d, got %d", result.String(), ts, decoded)
+		}
+	}
+}
+
+func TestHTTPStatusClass(t *testing.T) {
+	cs, err := BuildCallstack("{httpstatus:class:4xx}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		code, err := strconv.Atoi(result.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code < 400 || code > 499 {
+			t.Fatalf("Generated %d, which is not a 4xx status code", code)
+		}
+		result.Reset()
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	cs, err := BuildCallstack("{int} {int:min:0|max:5} {ascii}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetDefault("int", "min", "1000"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetDefault("int", "max", "2000"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetDefault("ascii", "length", "7"); err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 100; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), " ")
+		n, err := strconv.Atoi(p[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n < 1000 || n > 2000 {
+			t.Errorf("Expected the changed defaults to be used, got %d", n)
+		}
+		// The options set in the template win over the defaults
+		n, err = strconv.Atoi(p[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n < 0 || n > 5 {
+			t.Errorf("Expected the options from the template to be used, got %d", n)
+		}
+		if len(p[2]) != 7 {
+			t.Errorf("Expected the changed length to be used, got %s", p[2])
+		}
+		result.Reset()
+	}
+
+	// Other Callstacks keep the original defaults
+	other, err := BuildCallstack("{ascii}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result.String()) != 2 {
+		t.Errorf("Expected a new Callstack to use the original defaults, got %s", result.String())
+	}
+
+	if err := cs.SetDefault("integer", "min", "10"); err == nil {
+		t.Error("Expected an error setting a default for an unknown token")
+	}
+	if err := cs.SetDefault("int", "mni", "10"); err == nil {
+		t.Error("Expected an error setting a default for an unknown option")
+	}
+	if err := cs.SetDefault("int", "min", "abc"); err == nil {
+		t.Error("Expected an error setting a default which is not a number for a numeric option")
+	}
+	if err := cs.SetDefault("float", "max", "1.5.0"); err == nil {
+		t.Error("Expected an error setting a default which is not a number for a numeric option")
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	for _, defaults := range []string{
+		"# team conventions\n\nint.min=2000\nint.max = 2000\n  time.zone=America/New_York\nfloat.precision=1\n",
+		`{"int": {"min": 2000, "max": "2000"}, "time": {"zone": "America/New_York"}, "float": {"precision": 1}}`,
+	} {
+		cs, err := BuildCallstack("{int}|{int:max:2001}|{time:format:2006-01-02T15:04:05-0700}|{time:zone:UTC|format:2006-01-02T15:04:05-0700}|{float}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.LoadDefaults(strings.NewReader(defaults)); err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "|")
+		if p[0] != "2000" {
+			t.Errorf("Expected the loaded defaults to be used for the int, got %s", p[0])
+		}
+		// Options set in the template win over the defaults
+		if p[1] != "2000" && p[1] != "2001" {
+			t.Errorf("Expected the template's max to be used with the loaded min, got %s", p[1])
+		}
+		if !strings.HasSuffix(p[2], "-0500") && !strings.HasSuffix(p[2], "-0400") {
+			t.Errorf("Expected the loaded zone to be used for the time, got %s", p[2])
+		}
+		if !strings.HasSuffix(p[3], "+0000") {
+			t.Errorf("Expected the template's zone to be used for the time, got %s", p[3])
+		}
+		if !regexp.MustCompile(`^[0-9]+\.[0-9]$`).MatchString(p[4]) {
+			t.Errorf("Expected the loaded precision to be used for the float, got %s", p[4])
+		}
+	}
+}
+
+func TestLoadDefaultsErrors(t *testing.T) {
+	for _, defaults := range []string{
+		"int.min",
+		"min=5",
+		"int.min=5\nintt.max=10",
+		"int.mni=5",
+		`{"int": {"min": [1, 2]}}`,
+		`{"int": 5}`,
+		`{"int": {"min": 5}`,
+		"int.max=lots",
+		`{"int": {"min": "abc"}}`,
+		`{"float": {"precision": 1.5}}`,
+	} {
+		cs, err := BuildCallstack("{int}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cs.LoadDefaults(strings.NewReader(defaults)); err == nil {
+			t.Errorf("Expected an error loading the defaults %q", defaults)
+		}
+	}
+}
+
+func TestLatLngRange(t *testing.T) {
+	cs, err := BuildCallstack("{latlng}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), ",")
+		lat, err := strconv.ParseFloat(p[0], 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lng, err := strconv.ParseFloat(p[1], 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lat < -90 || lat > 90 {
+			t.Errorf("Generated a latitude of %f, which is outside of -90 to 90", lat)
+		}
+		if lng < -180 || lng > 180 {
+			t.Errorf("Generated a longitude of %f, which is outside of -180 to 180", lng)
+		}
+		result.Reset()
+	}
+}
+
+func TestDurationRange(t *testing.T) {
+	cs, err := BuildCallstack("{duration:min:1h|max:3h}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		d, err := time.ParseDuration(result.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d < time.Hour || d > 3*time.Hour {
+			t.Errorf("Generated a duration of %s, which is outside of 1h to 3h", d)
+		}
+		result.Reset()
+	}
+}
+
+func TestSlugWords(t *testing.T) {
+	valid := regexp.MustCompile(`^[a-z0-9-]+$`)
+	for words := 1; words <= 6; words++ {
+		cs, err := BuildCallstack(fmt.Sprintf("{slug:words:%d}", words))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 100; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			s := result.String()
+			if !valid.MatchString(s) {
+				t.Errorf("Slug %s contains characters which are not URL friendly", s)
+			}
+			if n := len(strings.Split(s, "-")); n != words {
+				t.Errorf("Expected slug %s to have %d words, got %d", s, words, n)
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestFilenameExtension(t *testing.T) {
+	cs, err := BuildCallstack("{filename:ext:jpg,png,gif|path:1}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	result := &bytes.Buffer{}
+	for i := 0; i < 500; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		s := result.String()
+		ext := s[strings.LastIndex(s, ".")+1:]
+		if ext != "jpg" && ext != "png" && ext != "gif" {
+			t.Errorf("Filename %s does not end with one of the requested extensions", s)
+		}
+		seen[ext] = true
+		result.Reset()
+	}
+	if len(seen) != 3 {
+		t.Errorf("Expected all 3 extensions to be used, got %v", seen)
+	}
+}
+
+func TestIBANChecksum(t *testing.T) {
+	// The lengths of a full IBAN in each country, from the IBAN registry
+	lengths := map[string]int{"DE": 22, "GB": 22, "FR": 27, "NL": 18, "NO": 15, "IT": 27, "PL": 28}
+	for country, length := range lengths {
+		cs, err := BuildCallstack("{iban:country:" + country + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 100; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			s := result.String()
+			if len(s) != length || s[:2] != country {
+				t.Errorf("Expected a %d character IBAN for %s, got %s", length, country, s)
+			}
+			// Moving the first 4 characters to the end, the whole thing must be 1 mod 97
+			mod := new(big.Int)
+			digits := ""
+			for _, c := range s[4:] + s[:4] {
+				if c >= 'A' && c <= 'Z' {
+					digits += strconv.Itoa(int(c-'A') + 10)
+				} else {
+					digits += string(c)
+				}
+			}
+			mod.SetString(digits, 10)
+			if mod.Mod(mod, big.NewInt(97)).Int64() != 1 {
+				t.Errorf("IBAN %s does not have valid check digits", s)
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestISBNChecksum(t *testing.T) {
+	for _, version := range []string{"10", "13"} {
+		cs, err := BuildCallstack("{isbn:hyphenate:true|version:" + version + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 500; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			s := strings.Replace(result.String(), "-", "", -1)
+			sum := 0
+			for j, c := range s {
+				d := int(c - '0')
+				if c == 'X' {
+					d = 10
+				}
+				if version == "10" {
+					// Weighted from 10 down to 1, the sum must be divisible by 11
+					sum += (10 - j) * d
+				} else if j%2 == 1 {
+					// Alternately weighted by 1 and 3, the sum must be divisible by 10
+					sum += 3 * d
+				} else {
+					sum += d
+				}
+			}
+			if (version == "10" && (len(s) != 10 || sum%11 != 0)) || (version == "13" && (len(s) != 13 || sum%10 != 0)) {
+				t.Errorf("ISBN-%s %s does not have a valid check digit", version, result.String())
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestRepeatIsIndependent(t *testing.T) {
+	cs, err := BuildCallstack("{repeat:count:20|sep:,|tpl:{int:min:0|max:1000000}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	if err := cs.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, v := range strings.Split(result.String(), ",") {
+		seen[v] = true
+	}
+	if len(seen) < 2 {
+		t.Errorf("Expected each repetition to generate it's own values, got %s", result.String())
+	}
+}
+
+func TestRepeatInheritsChanges(t *testing.T) {
+	for _, test := range []struct {
+		template string
+		change   func(*Callstack) error
+		want     string
+	}{
+		{
+			"{repeat:count:3|sep:,|tpl:{int}}",
+			func(cs *Callstack) error {
+				if err := cs.SetDefault("int", "min", "500"); err != nil {
+					return err
+				}
+				return cs.SetDefault("int", "max", "500")
+			},
+			"500,500,500",
+		},
+		{
+			"{repeat:count:2|sep:,|tpl:{repeat:count:2|sep:;|tpl:{int}}}",
+			func(cs *Callstack) error {
+				return cs.LoadDefaults(strings.NewReader("int.min=8\nint.max=8"))
+			},
+			"8;8,8;8",
+		},
+		{
+			// A default template is built with the defaults set before it
+			"{repeat:count:2|sep:,}",
+			func(cs *Callstack) error {
+				if err := cs.LoadDefaults(strings.NewReader("int.min=9\nint.max=9")); err != nil {
+					return err
+				}
+				return cs.SetDefault("repeat", "tpl", "{int}")
+			},
+			"9,9",
+		},
+		{
+			"{repeat:count:2|sep:,|nullprob:0|tpl:{int}}",
+			func(cs *Callstack) error {
+				return cs.SetNullRate(1, "N")
+			},
+			"N,N",
+		},
+		{
+			// The values inside of the template are escaped once, along with the rest
+			"'{repeat:count:2|sep: |tpl:{int:nullprob:1|nullvalue:O'Brien}}'",
+			func(cs *Callstack) error {
+				cs.SetEscaper(EscapeSQL)
+				return nil
+			},
+			"'O''Brien O''Brien'",
+		},
+	} {
+		cs, err := BuildCallstack(test.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := test.change(cs); err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		if result.String() != test.want {
+			t.Errorf("Expected %s to write %s, got %s", test.template, test.want, result.String())
+		}
+	}
+
+	// A default template can't nest itself forever
+	cs, err := BuildCallstack("{repeat}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetDefault("repeat", "tpl", "{repeat}"); err == nil {
+		t.Error("Expected an error for a default template which nests itself")
+	}
+}
+
+func TestRepeatCollectErrors(t *testing.T) {
+	cs, err := BuildCallstack("{int:min:5|max:5}|{repeat:count:2|sep:,|tpl:{int:min:5|max:1}-{int:min:6|max:6}}|{int:min:7|max:7}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SetCollectErrors(true, "?")
+	result := &bytes.Buffer{}
+	err = cs.Write(result)
+	errs, ok := err.(WriteErrors)
+	if !ok || len(errs) != 2 {
+		t.Fatalf("Expected an error for each repetition, got %v", err)
+	}
+	if result.String() != "5|?-6,?-6|7" {
+		t.Errorf("Expected the placeholders to be written inside of the repeat, got %s", result.String())
+	}
+}
+
+func TestNullFrequency(t *testing.T) {
+	cs, err := BuildCallstack("{int:nullprob:0.2}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nulls := 0
+	iterations := 10000
+	result := &bytes.Buffer{}
+	for i := 0; i < iterations; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		if result.String() == "NULL" {
+			nulls++
+		}
+		result.Reset()
+	}
+	// With 10000 tries, the share of nulls should land well within a few percent of 20%
+	if share := float64(nulls) / float64(iterations); share < 0.17 || share > 0.23 {
+		t.Errorf("Expected roughly 20%% of values to be NULL, got %f", share)
+	}
+}
+
+func TestEscapeCSV(t *testing.T) {
+	for v, expected := range map[string]string{
+		"":               "",
+		"plain":          "plain",
+		"a,b":            `"a,b"`,
+		`say "hi"`:       `"say ""hi"""`,
+		"two\nlines":     "\"two\nlines\"",
+		"Москва, Россия": `"Москва, Россия"`,
+	} {
+		if escaped := EscapeCSV(v); escaped != expected {
+			t.Errorf("Expected %q to be escaped as %q, got %q", v, expected, escaped)
+		}
+	}
+
+	// Only the values of tokens are escaped, not the template around them
+	cs, err := BuildCallstack("{company:legal:Widgets, Inc},{int:min:5|max:5}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SetEscaper(EscapeCSV)
+	result := &bytes.Buffer{}
+	if err := cs.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^"[A-Za-z ]+ Widgets, Inc",5$`).MatchString(result.String()) {
+		t.Errorf("Expected the company to be quoted, got %s", result.String())
+	}
+}
+
+func TestEscapeSQL(t *testing.T) {
+	for v, expected := range map[string]string{
+		"":         "",
+		"plain":    "plain",
+		"O'Brien":  "O''Brien",
+		"''":       "''''",
+		"a, \"b\"": "a, \"b\"",
+	} {
+		if escaped := EscapeSQL(v); escaped != expected {
+			t.Errorf("Expected %q to be escaped as %q, got %q", v, expected, escaped)
+		}
+	}
+
+	// Values are cut down to their maxlength before they're escaped, so the quote which
+	// is left is still doubled
+	cs, err := BuildCallstack("'{repeat:count:1|tpl:O'Brien|maxlength:2}'")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SetEscaper(EscapeSQL)
+	result := &bytes.Buffer{}
+	if err := cs.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	if result.String() != "'O'''" {
+		t.Errorf("Expected the value to be cut down then escaped, got %s", result.String())
+	}
+}
+
+func TestEscapeJSON(t *testing.T) {
+	for v, expected := range map[string]string{
+		"":               "",
+		"plain":          "plain",
+		`say "hi"`:       `say \"hi\"`,
+		`C:\temp`:        `C:\\temp`,
+		"tab\tnew\nline": `tab\tnew\nline`,
+		"\x01":           `\u0001`,
+		"<b>&</b>":       "<b>&</b>",
+		"Zoë 🙂":          "Zoë 🙂",
+	} {
+		if escaped := EscapeJSON(v); escaped != expected {
+			t.Errorf("Expected %q to be escaped as %q, got %q", v, expected, escaped)
+		}
+	}
+
+	// The jsonescape option escapes a single token, so it can be placed in a JSON string
+	cs, err := BuildCallstack(`\{"name": "{repeat:count:1|tpl:Bobby "Tables" \\ O'Brien|jsonescape:true}", "n": {int:jsonescape:true}, "raw": "{repeat:count:1|tpl:a"b}"\}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	if err := cs.Write(result); err != nil {
+		t.Fatal(err)
+	}
+	expected := regexp.MustCompile(`^\{"name": "Bobby \\"Tables\\" \\\\ O'Brien", "n": [0-9]+, "raw": "a"b"\}$`)
+	if !expected.MatchString(result.String()) {
+		t.Errorf("Expected only the tokens with jsonescape to be escaped, got %s", result.String())
+	}
+
+	// Decoding the escaped value gives back the original
+	if cs, err = BuildCallstack(`\{"v": "{unicode:length:50|jsonescape:true}", "w": "{ascii:length:50|jsonescape:true}"\}`); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		result.Reset()
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]string
+		if err := json.Unmarshal(result.Bytes(), &m); err != nil {
+			t.Fatalf("%s is not valid JSON: %s", result.String(), err)
+		}
+		if utf8.RuneCountInString(m["v"]) != 50 || len(m["w"]) != 50 {
+			t.Errorf("Expected the values to decode back to 50 characters, got %q", m)
+		}
+	}
+}
+
+func TestEscapeMarkdown(t *testing.T) {
+	for v, expected := range map[string]string{
+		"":                  "",
+		"plain":             "plain",
+		"a|b":               `a\|b`,
+		"one\ntwo\r\nthree": "one<br>two<br>three",
+	} {
+		if escaped := EscapeMarkdown(v); escaped != expected {
+			t.Errorf("Expected %q to be escaped as %q, got %q", v, expected, escaped)
+		}
+	}
+}
+
+func TestNumericOptionsFailAtParse(t *testing.T) {
+	for _, template := range []string{
+		"{int:min:abc}",
+		"{int:max:1.5}",
+		"{int:step:}",
+		"{float:min:ten}",
+		"{float:max:1e}",
+		"{float:precision:2.5}",
+		"{time:min:yesterday}",
+		"{time:max:1455512165.5}",
+		"{guid:ordinal:first}",
+		"{country:nullprob:half}",
+		"{latlng:minlat:north}",
+		"{repeat:count:2|tpl:{int:max:x}}",
+	} {
+		_, err := BuildCallstack(template)
+		if _, ok := err.(InvalidArgumentError); !ok {
+			t.Errorf("Expected an InvalidArgumentError parsing %s, got %v", template, err)
+		}
+	}
+
+	// Numbers are only checked for being numbers, not for being in range
+	for _, template := range []string{
+		"{int:min:-5|max:-1}",
+		"{float:min:-2.5|max:1e3}",
+		"{time:min:0|max:0}",
+		"{latlng:minlat:40.5|maxlat:41|minlng:-74.25|maxlng:-73.5}",
+		"{int:min:10|max:1}",
+		"{lorem:words:0}",
+		"{country:nullprob:2}",
+	} {
+		if _, err := BuildCallstack(template); err != nil {
+			t.Errorf("Expected %s to parse, got %s", template, err)
+		}
+	}
+}
+
+func TestIsValidTemplate(t *testing.T) {
+	for _, c := range []struct {
+		template string
+		valid    bool
+		message  string
+	}{
+		{"INSERT INTO t VALUES ({int}, '{firstname}')", true, ""},
+		{"INSERT INTO t VALUES ({int}, '{xyz}')", false, `the token "xyz" at offset 30 is not recognized`},
+		// Offsets are counted in runes, not bytes
+		{"Привет {int} {xyz}", false, `the token "xyz" at offset 13 is not`},
+		{"{int} {int:mni:5}", false, `mni is not a known option for the token "int" at offset 6`},
+		{"{int}, {int:min:10|max:1}", false, `token "int" at offset 7: You cannot generate`},
+		{"{ascii} {int:min:ten}", false, `the option min for the token "int" at offset 8 must be a whole number, not "ten"`},
+		{"{float:precision:2|nullprob:2}", false, `token "float" at offset 0: You have specified a nullprob`},
+		{"{int", false, `the { at offset 0 is never closed`},
+		{"{int} {repeat:tpl:{int}", false, `the { at offset 6 is never closed`},
+		{"{int} \\{int", true, ""},
+	} {
+		valid, err := IsValidTemplate(c.template)
+		if valid != c.valid {
+			t.Errorf("Expected %q to have validity %t, got %t", c.template, c.valid, valid)
+		}
+		if c.valid {
+			if err != nil {
+				t.Errorf("Expected no error for %q, got %s", c.template, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), c.message) {
+			t.Errorf("Expected the error for %q to contain %q, got %v", c.template, c.message, err)
+		}
+	}
+}
+
+func TestNormalDistribution(t *testing.T) {
+	for _, template := range []string{
+		"{float:min:0|max:100|distribution:normal|mean:40|stddev:5}",
+		"{int:min:0|max:100|distribution:normal|mean:40|stddev:5}",
+	} {
+		cs, err := BuildCallstack(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iterations := 10000
+		values := make([]float64, iterations)
+		sum := 0.0
+		result := &bytes.Buffer{}
+		for i := range values {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			v, err := strconv.ParseFloat(result.String(), 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v < 0 || v > 100 {
+				t.Errorf("Generated %f, which is outside of 0 to 100", v)
+			}
+			values[i] = v
+			sum += v
+			result.Reset()
+		}
+		mean := sum / float64(iterations)
+		variance := 0.0
+		for _, v := range values {
+			variance += (v - mean) * (v - mean)
+		}
+		stddev := math.Sqrt(variance / float64(iterations))
+		if math.Abs(mean-40) > 0.5 {
+			t.Errorf("Expected the sample mean of %s to be close to 40, got %f", template, mean)
+		}
+		if math.Abs(stddev-5) > 0.5 {
+			t.Errorf("Expected the sample stddev of %s to be close to 5, got %f", template, stddev)
+		}
+	}
+}
+
+func TestCardTypeMatchesCard(t *testing.T) {
+	prefixes := map[string]*regexp.Regexp{
+		"visa":       regexp.MustCompile(`^4[0-9]{15}$`),
+		"mastercard": regexp.MustCompile(`^(5[1-5]|2221|2720)[0-9]+$`),
+		"amex":       regexp.MustCompile(`^3[47][0-9]{13}$`),
+		"discover":   regexp.MustCompile(`^(6011|65)[0-9]+$`),
+	}
+	cs, err := BuildCallstack("{creditcard:as:cc} {cardtype:ref:cc}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), " ")
+		if !prefixes[p[1]].MatchString(p[0]) {
+			t.Errorf("Card %s does not belong to the %s network", p[0], p[1])
+		}
+		if !passesLuhn(p[0]) {
+			t.Errorf("Card %s does not pass the Luhn check", p[0])
+		}
+		result.Reset()
+	}
+}
+
+// passesLuhn returns whether the number passes the Luhn check. Doubling every second
+// digit from the right, the digits must add up to a multiple of 10.
+func passesLuhn(number string) bool {
+	sum := 0
+	for j := range number {
+		d := int(number[len(number)-1-j] - '0')
+		if j%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func TestIMEILuhn(t *testing.T) {
+	// A known IMEI, from the example used to document the check digit
+	if !passesLuhn("490154203237518") || luhnCheck("49015420323751") != "8" {
+		t.Error("Expected 490154203237518 to have a check digit of 8")
+	}
+
+	cs, err := BuildCallstack("{imei} {imei:tac:realistic} {imei:tac:35332509}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for _, imei := range strings.Split(result.String(), " ") {
+			if len(imei) != 15 || !passesLuhn(imei) {
+				t.Errorf("IMEI %s is not 15 digits which pass the Luhn check", imei)
+			}
+		}
+		result.Reset()
+	}
+}
+
+func TestGenderWeights(t *testing.T) {
+	cs, err := BuildCallstack("{gender:weights:3,1,0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	iterations := 10000
+	result := &bytes.Buffer{}
+	for i := 0; i < iterations; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		counts[result.String()]++
+		result.Reset()
+	}
+	if counts["nonbinary"] != 0 {
+		t.Errorf("Expected no values with a weight of 0, got %d", counts["nonbinary"])
+	}
+	// 3 to 1 should be roughly 75% male
+	if share := float64(counts["male"]) / float64(iterations); share < 0.72 || share > 0.78 {
+		t.Errorf("Expected roughly 75%% of values to be male, got %f", share)
+	}
+}
+
+func TestStatusWeights(t *testing.T) {
+	cs, err := BuildCallstack("{status:set:order}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	iterations := 10000
+	result := &bytes.Buffer{}
+	for i := 0; i < iterations; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		counts[result.String()]++
+		result.Reset()
+	}
+	for status, expected := range map[string]float64{"pending": 0.4, "shipped": 0.5, "cancelled": 0.1} {
+		if share := float64(counts[status]) / float64(iterations); share < expected-0.03 || share > expected+0.03 {
+			t.Errorf("Expected roughly %.0f%% of orders to be %s, got %f", expected*100, status, share)
+		}
+	}
+
+	// Every set should have weights which read as a percentage
+	for name, set := range data.StatusSets {
+		total := 0.0
+		for _, v := range set {
+			total += v.Weight
+		}
+		if total != 100 {
+			t.Errorf("Expected the weights of %s to add up to 100, got %f", name, total)
+		}
+	}
+}
+
+func TestBloodTypeWeights(t *testing.T) {
+	cs, err := BuildCallstack("{bloodtype}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	iterations := 10000
+	result := &bytes.Buffer{}
+	for i := 0; i < iterations; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		counts[result.String()]++
+		result.Reset()
+	}
+	if len(counts) != len(data.BloodTypes) {
+		t.Errorf("Expected all %d blood types to be generated, got %v", len(data.BloodTypes), counts)
+	}
+	// O+ and A+ are each over a third of people, and AB- well under 1 in 100
+	for bloodtype, bounds := range map[string][2]float64{"O+": {0.34, 0.41}, "A+": {0.32, 0.39}, "AB-": {0, 0.015}} {
+		if share := float64(counts[bloodtype]) / float64(iterations); share < bounds[0] || share > bounds[1] {
+			t.Errorf("Expected %s to be between %f and %f of blood types, got %f", bloodtype, bounds[0], bounds[1], share)
+		}
+	}
+}
+
+func TestShortCodeCharset(t *testing.T) {
+	cs, err := BuildCallstack("{base36:length:50} {base62:length:50}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make([]map[rune]bool, 2)
+	for i := range seen {
+		seen[i] = make(map[rune]bool)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 200; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for j, code := range strings.Split(result.String(), " ") {
+			for _, r := range code {
+				seen[j][r] = true
+			}
+		}
+		result.Reset()
+	}
+	// 10,000 digits of each should turn up every one of them
+	if len(seen[0]) != 36 || len(seen[1]) != 62 {
+		t.Errorf("Expected all 36 and 62 digits to be used, got %d and %d", len(seen[0]), len(seen[1]))
+	}
+}
+
+func TestHostnamesAreValid(t *testing.T) {
+	// RFC 1123: at most 253 characters, in labels of 1 to 63 letters, digits, and hyphens
+	// which don't start or end with a hyphen
+	label := `[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?`
+	pattern := regexp.MustCompile(`^` + label + `(\.` + label + `)*$`)
+	cs, err := BuildCallstack("{hostname} {hostname:style:role} {hostname:domain:a.very-long-subdomain.example.co.uk}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for _, host := range strings.Split(result.String(), " ") {
+			if len(host) > 253 || !pattern.MatchString(host) {
+				t.Errorf("%s is not a valid hostname", host)
+			}
+		}
+		result.Reset()
+	}
+
+	// There's no room for a host in front of a domain which is already at the limit
+	long := strings.Repeat(strings.Repeat("a", 63)+".", 3) + strings.Repeat("a", 61)
+	if cs, err = BuildCallstack("{hostname:domain:" + long + "}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Write(result); err == nil {
+		t.Errorf("Expected an error for a domain of %d characters", len(long))
+	}
+}
+
+func TestPortPresets(t *testing.T) {
+	for preset, bounds := range map[string][2]int{
+		"any":        {1, 65535},
+		"privileged": {1, 1023},
+		"registered": {1024, 49151},
+		"ephemeral":  {49152, 65535},
+	} {
+		cs, err := BuildCallstack("{port:preset:" + preset + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 1000; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			p, err := strconv.Atoi(result.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p < bounds[0] || p > bounds[1] {
+				t.Errorf("Port %d is outside of the %s range %d to %d", p, preset, bounds[0], bounds[1])
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestWordLength(t *testing.T) {
+	for _, bounds := range [][2]int{{0, 0}, {2, 3}, {5, 5}, {7, 0}, {0, 4}} {
+		cs, err := BuildCallstack(fmt.Sprintf("{word:minlen:%d|maxlen:%d}", bounds[0], bounds[1]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 500; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			l := len(result.String())
+			if l < bounds[0] || (bounds[1] > 0 && l > bounds[1]) {
+				t.Errorf("Word %s is not between %d and %d letters long", result.String(), bounds[0], bounds[1])
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestNanoIDAlphabet(t *testing.T) {
+	for _, c := range []struct {
+		size     int
+		alphabet string
+	}{
+		{21, "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"},
+		{1, "ab"},
+		{64, "0123456789"},
+		{12, "日本語"},
+	} {
+		cs, err := BuildCallstack(fmt.Sprintf("{nanoid:size:%d|alphabet:%s}", c.size, c.alphabet))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 200; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			id := []rune(result.String())
+			if len(id) != c.size {
+				t.Errorf("NanoID %s is not %d characters long", string(id), c.size)
+			}
+			for _, r := range id {
+				if !strings.ContainsRune(c.alphabet, r) {
+					t.Errorf("NanoID %s has %q, which is not in the alphabet %s", string(id), r, c.alphabet)
+				}
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestContinentMatchesCountry(t *testing.T) {
+	continents := make(map[string]string)
+	for _, c := range data.Countries {
+		continents[c.Alpha2] = c.Continent
+	}
+	cs, err := BuildCallstack("{country:as:c}|{continent:ref:c}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "|")
+		if continents[p[0]] != p[1] {
+			t.Errorf("Expected the continent of %s to be %s, got %s", p[0], continents[p[0]], p[1])
+		}
+		result.Reset()
+	}
+	// Every country which is a place should have a continent
+	for _, c := range data.Countries {
+		if c.Population > 0 && c.Continent == "" {
+			t.Errorf("Country %s has no continent", c.Alpha2)
+		}
+	}
+}
+
+func TestRowNumbers(t *testing.T) {
+	cs, err := BuildCallstack("{rownum:pad:2},{rownum:start:0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 1; i <= 12; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		if expected := fmt.Sprintf("%02d,%d", i, i-1); result.String() != expected {
+			t.Errorf("Expected row %d to be written as %s, got %s", i, expected, result.String())
+		}
+		result.Reset()
+	}
+}
+
+func TestRowNumberJitter(t *testing.T) {
+	cs, err := BuildCallstack("{rownum:start:1000|step:60|jitter:10}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.Seed(7)
+	result := &bytes.Buffer{}
+	last := 0
+	moved := false
+	for i := 0; i < 500; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		n, err := strconv.Atoi(result.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := 1000 + i*60
+		if n < expected-10 || n > expected+10 {
+			t.Errorf("Expected row %d to be within 10 of %d, got %d", i+1, expected, n)
+		}
+		if n != expected {
+			moved = true
+		}
+		// The jitter is less than half the step, so the rows still count up
+		if i > 0 && n <= last {
+			t.Errorf("Expected row %d to be after %d, got %d", i+1, last, n)
+		}
+		last = n
+		result.Reset()
+	}
+	if !moved {
+		t.Error("Expected the jitter to move some of the rows")
+	}
+}
+
+func TestReader(t *testing.T) {
+	cs, err := BuildCallstack("{rownum},{int:min:5|max:5},{guid}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := Reader(cs, 100)
+	// Read in chunks small enough to split lines, and large enough to span several
+	out := &bytes.Buffer{}
+	for _, size := range []int{1, 3, 7, 50, 200} {
+		chunk := make([]byte, size)
+		n, err := r.Read(chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out.Write(chunk[:n])
+	}
+	rest, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Write(rest)
+
+	lines := strings.Split(out.String(), "\n")
+	if len(lines) != 101 || lines[100] != "" {
+		t.Fatalf("Expected 100 lines ending in a newline, got %d", len(lines))
+	}
+	pattern := regexp.MustCompile(`^[0-9]+,5,[0-9a-f-]{36}$`)
+	for i, l := range lines[:100] {
+		if !pattern.MatchString(l) || !strings.HasPrefix(l, strconv.Itoa(i+1)+",") {
+			t.Errorf("Line %d was not written as expected: %q", i+1, l)
+		}
+	}
+	if n, err := r.Read(make([]byte, 10)); n != 0 || err != io.EOF {
+		t.Errorf("Expected io.EOF once every line was read, got %d and %v", n, err)
+	}
+}
+
+func TestReaderWithCSV(t *testing.T) {
+	cs, err := BuildCallstack("{int:min:5|max:5},{company:legal:Widgets, Inc}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SetEscaper(EscapeCSV)
+	records, err := csv.NewReader(Reader(cs, 20)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 20 {
+		t.Errorf("Expected 20 records, got %d", len(records))
+	}
+	for _, r := range records {
+		if len(r) != 2 || r[0] != "5" || !strings.HasSuffix(r[1], " Widgets, Inc") {
+			t.Errorf("Record was not read as expected: %q", r)
+		}
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	cs, err := BuildCallstack("{int}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := Reader(cs, 0).Read(make([]byte, 10)); n != 0 || err != io.EOF {
+		t.Errorf("Expected io.EOF from a reader with no lines, got %d and %v", n, err)
+	}
+	cs, err = BuildCallstack("{int} {int:ordinal:5}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ioutil.ReadAll(Reader(cs, 5)); err == nil {
+		t.Error("Expected the error from writing a line to be returned")
+	}
+}
+
+func TestWriteMap(t *testing.T) {
+	cs, err := BuildCallstack("INSERT INTO orders VALUES ('{guid:key:id}', {int:min:3|max:3|key:qty}, '{lastname:key:name}', {int:min:1|max:1}, {bool:nullprob:1|key:gift}, {rownum:key:row})")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Escaping is for the text of the result, and isn't applied to the map
+	cs.SetEscaper(EscapeSQL)
+	for i := 1; i <= 3; i++ {
+		m, err := cs.WriteMap()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m) != 5 {
+			t.Errorf("Expected only the 5 keyed tokens in the map, got %v", m)
+		}
+		if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(m["id"]) {
+			t.Errorf("Expected a guid for id, got %s", m["id"])
+		}
+		if m["qty"] != "3" || m["name"] == "" || m["gift"] != "NULL" || m["row"] != strconv.Itoa(i) {
+			t.Errorf("Unexpected values in the map %v", m)
+		}
+	}
+
+	// A template without keys gives an empty map
+	for _, template := range []string{"{int}", "no tokens here"} {
+		if cs, err = BuildCallstack(template); err != nil {
+			t.Fatal(err)
+		}
+		if m, err := cs.WriteMap(); err != nil || len(m) != 0 {
+			t.Errorf("Expected an empty map for %s, got %v, %v", template, m, err)
+		}
+	}
+
+	if _, err := BuildCallstack("{guid:key:id} {int:key:id}"); err == nil {
+		t.Error("Expected an error when two tokens have the same key")
+	}
+	if cs, err = BuildCallstack("{int:key:a} {int:ordinal:3|key:b}"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cs.WriteMap(); err == nil {
+		t.Error("Expected an error when the template can't be written")
+	}
+
+	// When errors are collected, the values which were written are kept
+	cs.SetCollectErrors(true, "?")
+	m, err := cs.WriteMap()
+	if errs, ok := err.(WriteErrors); !ok || len(errs) != 1 {
+		t.Errorf("Expected the error to be collected, got %v", err)
+	}
+	if _, err := strconv.Atoi(m["a"]); err != nil || m["b"] != "?" {
+		t.Errorf("Expected the value of a and the placeholder for b, got %v", m)
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	cs, err := BuildCallstack("{int:min:1|max:1},{guid:ordinal:0},{int:min:2|max:2},{float:min:5|max:1},{int:ordinal:1}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	// By default, the first error stops the write
+	if err := cs.Write(result); err == nil {
+		t.Fatal("Expected an error from the invalid tokens")
+	} else if _, ok := err.(WriteErrors); ok {
+		t.Errorf("Expected only the first error, got %s", err)
+	}
+
+	cs.SetCollectErrors(true, "ERR")
+	result.Reset()
+	err = cs.Write(result)
+	if result.String() != "1,ERR,2,ERR,2" {
+		t.Errorf("Expected the placeholder in place of the failed tokens, got %s", result.String())
+	}
+	errs, ok := err.(WriteErrors)
+	if !ok {
+		t.Fatalf("Expected WriteErrors, got %v", err)
+	}
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), `"guid"`) || !strings.Contains(errs[1].Error(), `"float"`) {
+		t.Errorf("Expected the errors from the guid and float, got %s", errs)
+	}
+	if !strings.HasPrefix(errs.Error(), "2 tokens failed to write: ") {
+		t.Errorf("Unexpected message %s", errs.Error())
+	}
+
+	// WriteAll writes every result, and returns every error at the end
+	out := &bytes.Buffer{}
+	err = cs.WriteAll(out, 3, "\n")
+	if out.String() != "1,ERR,2,ERR,2\n1,ERR,2,ERR,2\n1,ERR,2,ERR,2" {
+		t.Errorf("Expected every result to be written, got %q", out.String())
+	}
+	if errs, ok := err.(WriteErrors); !ok || len(errs) != 6 {
+		t.Errorf("Expected 6 errors from WriteAll, got %v", err)
+	}
+
+	// A valid template has no errors to collect
+	cs.SetCollectErrors(false, "")
+	if cs, err = BuildCallstack("{int},{guid}"); err != nil {
+		t.Fatal(err)
+	}
+	cs.SetCollectErrors(true, "")
+	if err := cs.Write(result); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestWriteAll(t *testing.T) {
+	for _, c := range []struct {
+		n        int
+		sep      string
+		expected string
+	}{
+		{5, "", "1\n2\n3\n4\n5"},
+		{3, ", ", "1, 2, 3"},
+		{2, "\r\n", "1\r\n2"},
+		{1, "|", "1"},
+		{0, "", ""},
+	} {
+		cs, err := BuildCallstack("{rownum}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		if err := cs.WriteAll(result, c.n, c.sep); err != nil {
+			t.Fatal(err)
+		}
+		if result.String() != c.expected {
+			t.Errorf("Expected %d results separated by %q to be %q, got %q", c.n, c.sep, c.expected, result.String())
+		}
+	}
+
+	// A result which fails is not written
+	cs, err := BuildCallstack("{int} {int:ordinal:5}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	if err := cs.WriteAll(result, 5, ""); err == nil {
+		t.Error("Expected the error from writing a result to be returned")
+	} else if result.Len() != 0 {
+		t.Errorf("Expected nothing to be written, got %q", result.String())
+	}
+
+	// Neither is anything after the writer fails
+	cs, err = BuildCallstack("{rownum}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.WriteAll(failingWriter{}, 5, ""); err != errWriteFailed {
+		t.Errorf("Expected the error from the writer to be returned, got %v", err)
+	}
+}
+
+func TestWriteEach(t *testing.T) {
+	cs, err := BuildCallstack("{rownum}{int:min:1|max:1|ordinal:0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.SetCollectErrors(true, "?")
+	var results []string
+	var errs int
+	err = cs.WriteEach(3, func(i int, result *bytes.Buffer, err error) error {
+		if _, ok := err.(WriteErrors); ok {
+			errs++
+		}
+		results = append(results, strconv.Itoa(i)+":"+result.String())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(results, ",") != "0:1?,1:2?,2:3?" || errs != 3 {
+		t.Errorf("Expected each result with it's error, got %v with %d errors", results, errs)
+	}
+
+	// The first error from fn stops the writing
+	calls := 0
+	err = cs.WriteEach(5, func(i int, result *bytes.Buffer, err error) error {
+		calls++
+		return errWriteFailed
+	})
+	if err != errWriteFailed || calls != 1 {
+		t.Errorf("Expected the error from fn to stop the writing, got %v after %d calls", err, calls)
+	}
+}
+
+var errWriteFailed = errors.New("write failed")
+
+// failingWriter fails every write
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errWriteFailed
+}
+
+func TestMaybeFollowsBool(t *testing.T) {
+	cs, err := BuildCallstack("{bool:as:active|format:numeric},{maybe:ref:active|then:YES|else:NO}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		counts[result.String()]++
+		result.Reset()
+	}
+	// Both branches should come up, and only ever alongside the matching bool
+	if counts["1,YES"] == 0 || counts["0,NO"] == 0 || counts["1,YES"]+counts["0,NO"] != 1000 {
+		t.Errorf("Expected maybe to follow the bool down both branches, got %v", counts)
+	}
+}
+
+func TestSSNAvoidsReservedNumbers(t *testing.T) {
+	cs, err := BuildCallstack("{ssn}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 10000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "-")
+		area, _ := strconv.Atoi(p[0])
+		if area == 0 || area == 666 || area >= 900 {
+			t.Errorf("SSN %s has a reserved area number", result.String())
+		}
+		if p[1] == "00" || p[2] == "0000" {
+			t.Errorf("SSN %s has a group or serial of all zeros", result.String())
+		}
+		result.Reset()
+	}
+}
+
+func TestLoremLanguages(t *testing.T) {
+	for _, language := range []string{"french", "german", "spanish", "russian", "greek"} {
+		cs, err := BuildCallstack("{lorem:words:20|language:" + language + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		// Not every word has an accent, but 20 at a time should always turn one up
+		for i := 0; i < 50; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			ascii := true
+			for _, r := range result.String() {
+				if r > 127 {
+					ascii = false
+				}
+			}
+			if ascii {
+				t.Errorf("Expected lorem in %s to have characters outside of ASCII, got %s", language, result.String())
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestVINCheckDigit(t *testing.T) {
+	// Known good VINs, from the examples used to document the check digit
+	for _, v := range []string{"1M8GDM9AXKP042788", "11111111111111111", "1HGCM82633A004352"} {
+		if c := vinCheckDigit([]byte(v)); c != v[8] {
+			t.Errorf("Expected the check digit of %s to be %c, got %c", v, v[8], c)
+		}
+	}
+
+	values := make(map[rune]int)
+	for i, r := range "ABCDEFGH" {
+		values[r] = i + 1
+	}
+	for i, r := range "JKLMN" {
+		values[r] = i + 1
+	}
+	values['P'] = 7
+	values['R'] = 9
+	for i, r := range "STUVWXYZ" {
+		values[r] = i + 2
+	}
+	weights := []int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+	cs, err := BuildCallstack("{vin}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		v := result.String()
+		if strings.ContainsAny(v, "IOQ") {
+			t.Errorf("VIN %s has a character which is not allowed", v)
+		}
+		sum := 0
+		for j, r := range v {
+			if r >= '0' && r <= '9' {
+				sum += int(r-'0') * weights[j]
+			} else {
+				sum += values[r] * weights[j]
+			}
+		}
+		check := strconv.Itoa(sum % 11)
+		if check == "10" {
+			check = "X"
+		}
+		if v[8:9] != check {
+			t.Errorf("VIN %s should have a check digit of %s", v, check)
+		}
+		result.Reset()
+	}
+}
+
+func TestLicensePlateRegions(t *testing.T) {
+	for region, formats := range data.LicensePlateFormats {
+		// Build a pattern matching any of the formats for the region
+		patterns := make([]string, len(formats))
+		for i, f := range formats {
+			f = regexp.QuoteMeta(f)
+			f = strings.Replace(f, "#", "[0-9]", -1)
+			patterns[i] = strings.Replace(f, "@", "[A-Z]", -1)
+		}
+		pattern := regexp.MustCompile("^(" + strings.Join(patterns, "|") + ")$")
+
+		cs, err := BuildCallstack("{licenseplate:region:" + region + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 200; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			if !pattern.MatchString(result.String()) {
+				t.Errorf("License plate %s does not match any format for %s", result.String(), region)
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestTimezoneForCoordinates(t *testing.T) {
+	for _, c := range []struct {
+		lat  float64
+		lng  float64
+		zone string
+	}{
+		{40.7128, -74.0060, "America/New_York"},
+		{41.8781, -87.6298, "America/Chicago"},
+		{39.7392, -104.9903, "America/Denver"},
+		{34.0522, -118.2437, "America/Los_Angeles"},
+		{51.5074, -0.1278, "Europe/London"},
+		{48.8566, 2.3522, "Europe/Paris"},
+		{52.5200, 13.4050, "Europe/Berlin"},
+		{55.7558, 37.6173, "Europe/Moscow"},
+		{35.6762, 139.6503, "Asia/Tokyo"},
+		{31.2304, 121.4737, "Asia/Shanghai"},
+		{28.6139, 77.2090, "Asia/Kolkata"},
+		{-33.8688, 151.2093, "Australia/Sydney"},
+		{-23.5505, -46.6333, "America/Sao_Paulo"},
+		{50.4501, 30.5234, "Europe/Kiev"},
+		{-33.4489, -70.6693, "America/Santiago"},
+		{24.8607, 67.0011, "Asia/Karachi"},
+		{35.6892, 51.3890, "Asia/Tehran"},
+		{1.3521, 103.8198, "Asia/Singapore"},
+		{44.4268, 26.1025, "Europe/Bucharest"},
+		{-34.6037, -58.3816, "America/Argentina/Buenos_Aires"},
+		{-6.2088, 106.8456, "Asia/Jakarta"},
+		{25.2048, 55.2708, "Asia/Dubai"},
+		{41.0082, 28.9784, "Europe/Istanbul"},
+		// The middle of the oceans fall back to the nautical zones
+		{0, 0, "Etc/GMT"},
+		{0, -140, "Etc/GMT+9"},
+		{-40, 80, "Etc/GMT-5"},
+	} {
+		cs, err := BuildCallstack(fmt.Sprintf("{latlng:bbox:%[2]g,%[1]g,%[2]g,%[1]g|as:p}|{timezone:ref:p}", c.lat, c.lng))
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		if zone := strings.Split(result.String(), "|")[1]; zone != c.zone {
+			t.Errorf("Expected %g,%g to be in %s, got %s", c.lat, c.lng, c.zone, zone)
+		}
+	}
+
+	// Every zone which can be written should be one Go knows about
+	cs, err := BuildCallstack("{latlng:as:p} {timezone:ref:p} {timezone}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for _, zone := range strings.Split(result.String(), " ")[1:] {
+			if _, err := time.LoadLocation(zone); err != nil {
+				t.Errorf("Timezone %s is not a valid zone: %s", zone, err)
+			}
+		}
+		result.Reset()
+	}
+}
+
+func TestUnicodeRanges(t *testing.T) {
+	runic := [][]int{{0x16a0, 0x16eb}}
+	cs, err := BuildCallstack("{unicode:ranges:runic|length:10} {repeat:count:3|tpl:{unicode:ranges:runic}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.AddRanges("runic", runic); err != nil {
+		t.Fatal(err)
+	}
+	// Changing the ranges after they're added should have no effect
+	runic[0][1] = 0x10ffff
+
+	result := &bytes.Buffer{}
+	for i := 0; i < 500; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		for _, r := range strings.Replace(result.String(), " ", "", -1) {
+			if r < 0x16a0 || r >= 0x16eb {
+				t.Fatalf("%s has the character %U, which is not in the range", result.String(), r)
+			}
+		}
+		result.Reset()
+	}
+
+	// Only the Callstack the ranges were added to knows about them
+	other, err := BuildCallstack("{unicode:ranges:runic}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Write(result); err == nil {
+		t.Error("Expected an error for ranges which were not added")
+	}
+
+	for _, bad := range [][][]int{
+		nil,
+		{{0x16a0}},
+		{{0x16eb, 0x16a0}},
+		{{-1, 0x16a0}},
+		{{0x16a0, 0x110001}},
+	} {
+		if err := cs.AddRanges("bad", bad); err == nil {
+			t.Errorf("Expected an error adding the ranges %v", bad)
+		}
+	}
+	if err := cs.AddRanges("", runic); err == nil {
+		t.Error("Expected an error adding ranges with no name")
+	}
+}
+
+func TestSecureRandom(t *testing.T) {
+	template := "{int:min:10|max:19} {float:min:-5|max:5} {country} {ascii:length:8}"
+	secureTemplate := "{int:min:10|max:19|secure:true} {float:min:-5|max:5|secure:true} {country:secure:true} {ascii:length:8|secure:true}"
+	pattern := regexp.MustCompile(`^1[0-9] -?[0-4]\.[0-9]{6} [A-Z]{2} [ -~]{8}$`)
+
+	// Either every token in the Callstack, or only the ones which ask for it
+	everything, err := BuildCallstack(template)
+	if err != nil {
+		t.Fatal(err)
+	}
+	everything.SetSource(CryptoSource{})
+	perToken, err := BuildCallstack(secureTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cs := range []*Callstack{everything, perToken} {
+		result := &bytes.Buffer{}
+		for i := 0; i < 500; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			if !pattern.MatchString(result.String()) {
+				t.Errorf("%s is not in range", result.String())
+			}
+			result.Reset()
+		}
+	}
+
+	// Seeding has no effect on secure tokens
+	results := make([]string, 2)
+	for i := range results {
+		cs, err := BuildCallstack("{int:min:0|max:1000000|secure:true}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs.Seed(1234)
+		result := &bytes.Buffer{}
+		for j := 0; j < 5; j++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+		}
+		results[i] = result.String()
+	}
+	if results[0] == results[1] {
+		t.Error("Expected secure tokens to ignore the seed")
+	}
+
+	if _, err := IsValidTemplate("{int:secure:sometimes}"); err == nil {
+		t.Error("Expected an error for a secure option which is not a boolean")
+	}
+}
+
+func TestLengthsCountRunes(t *testing.T) {
+	for template, length := range map[string]int{
+		"{unicode:length:5}":                      5,
+		"{unicode:length:5|case:up}":              5,
+		"{nanoid:size:7|alphabet:日本語テキスト}":        7,
+		"{ascii:minlen:4|maxlen:4}":               4,
+		"{emoji:count:3|category:animals}":        3,
+		"{unicode:minlen:9|maxlen:9}":             9,
+		"{repeat:count:3|tpl:{unicode:length:2}}": 6,
+	} {
+		cs, err := BuildCallstack(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 100; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			if utf8.RuneCountInString(result.String()) != length {
+				t.Errorf("Expected %s to write %d runes, got %q", template, length, result.String())
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	for _, c := range []struct {
+		v        string
+		n        int
+		expected string
+	}{
+		{"hello", 3, "hel"},
+		{"hello", 10, "hello"},
+		{"日本語テキスト", 3, "日本語"},
+		{"Москва", 4, "Моск"},
+		{"🐼🐨🐯", 2, "🐼🐨"},
+		{"abc", 0, ""},
+		{"", 2, ""},
+	} {
+		if truncated := truncateRunes(c.v, c.n); truncated != c.expected {
+			t.Errorf("Expected %q cut to %d runes to be %q, got %q", c.v, c.n, c.expected, truncated)
+		}
+		if runeLength(c.expected) > c.n {
+			t.Errorf("Expected %q to be at most %d runes long", c.expected, c.n)
+		}
+	}
+}
+
+func TestGeneratedStringLength(t *testing.T) {
+	template := "Hey I'm {int:min:1|max:9} years old"
+	sampleresult := "Hey I'm 1 years old"
+	cs, err := BuildCallstack(template)
+	if err != nil {
+		t.Error(err)
+	}
+	result := &bytes.Buffer{}
+	err = cs.Write(result)
+	if err != nil {
+		t.Error(err)
+	}
+	if len([]rune(sampleresult)) != len([]rune(result.String())) {
+		fmt.Println(result.String())
+		t.Error("Missing parts of the rendered templtate")
+	}
+}
+
+func BenchmarkGUID(b *testing.B) {
+	c := GUIDCases[0]
+	var cs *Callstack
+	var err error
+	if cs, err = BuildCallstack(c.Template); err != nil {
+		b.Error(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		result := &bytes.Buffer{}
+		err = cs.Write(result)
+		if err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkNow(b *testing.B) {
 	c := NowCases[0]
 	var cs *Callstack
 	var err error
@@ -596,6 +5934,22 @@ func BenchmarkInteger(b *testing.B) {
 	}
 }
 
+func BenchmarkIntegerSecure(b *testing.B) {
+	var cs *Callstack
+	var err error
+	if cs, err = BuildCallstack("{int:min:0|max:1000|secure:true}"); err != nil {
+		b.Error(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		result := &bytes.Buffer{}
+		err = cs.Write(result)
+		if err != nil {
+			b.Error(err)
+		}
+	}
+}
+
 func BenchmarkFloat(b *testing.B) {
 	c := FloatCases[0]
 	var cs *Callstack
@@ -630,8 +5984,59 @@ func BenchmarkCountry(b *testing.B) {
 	}
 }
 
-func BenchmarkUnicode(b *testing.B) {
-	c := UnicodeCases[0]
+func BenchmarkUnicode(b *testing.B) {
+	c := UnicodeCases[0]
+	var cs *Callstack
+	var err error
+	if cs, err = BuildCallstack(c.Template); err != nil {
+		b.Error(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		result := &bytes.Buffer{}
+		err = cs.Write(result)
+		if err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkASCII(b *testing.B) {
+	c := ASCIICases[0]
+	var cs *Callstack
+	var err error
+	if cs, err = BuildCallstack(c.Template); err != nil {
+		b.Error(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		result := &bytes.Buffer{}
+		err = cs.Write(result)
+		if err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkFirstName(b *testing.B) {
+	c := FirstNameCases[0]
+	var cs *Callstack
+	var err error
+	if cs, err = BuildCallstack(c.Template); err != nil {
+		b.Error(err)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		result := &bytes.Buffer{}
+		err = cs.Write(result)
+		if err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func BenchmarkLastName(b *testing.B) {
+	c := FirstNameCases[0]
 	var cs *Callstack
 	var err error
 	if cs, err = BuildCallstack(c.Template); err != nil {
@@ -647,8 +6052,8 @@ func BenchmarkUnicode(b *testing.B) {
 	}
 }
 
-func BenchmarkASCII(b *testing.B) {
-	c := ASCIICases[0]
+func BenchmarkFullName(b *testing.B) {
+	c := FullNameCases[0]
 	var cs *Callstack
 	var err error
 	if cs, err = BuildCallstack(c.Template); err != nil {
@@ -664,8 +6069,8 @@ func BenchmarkASCII(b *testing.B) {
 	}
 }
 
-func BenchmarkFirstName(b *testing.B) {
-	c := FirstNameCases[0]
+func BenchmarkCurrency(b *testing.B) {
+	c := CurrencyCases[0]
 	var cs *Callstack
 	var err error
 	if cs, err = BuildCallstack(c.Template); err != nil {
@@ -681,11 +6086,10 @@ func BenchmarkFirstName(b *testing.B) {
 	}
 }
 
-func BenchmarkLastName(b *testing.B) {
-	c := FirstNameCases[0]
+func BenchmarkLiteralTemplate(b *testing.B) {
 	var cs *Callstack
 	var err error
-	if cs, err = BuildCallstack(c.Template); err != nil {
+	if cs, err = BuildCallstack("INSERT INTO users (id, name) VALUES (1, 'hello world');"); err != nil {
 		b.Error(err)
 	}
 	b.ResetTimer()
@@ -698,19 +6102,742 @@ func BenchmarkLastName(b *testing.B) {
 	}
 }
 
-func BenchmarkFullName(b *testing.B) {
-	c := FullNameCases[0]
-	var cs *Callstack
-	var err error
-	if cs, err = BuildCallstack(c.Template); err != nil {
-		b.Error(err)
+func BenchmarkBuildLiteralTemplate(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		if _, err := BuildCallstack("INSERT INTO users (id, name) VALUES (1, 'hello world');"); err != nil {
+			b.Error(err)
+		}
+	}
+}
+
+func TestMacAddrVendors(t *testing.T) {
+	for vendor, ouis := range data.VendorOUIs {
+		cs, err := BuildCallstack("{macaddr:vendor:" + vendor + "}")
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := &bytes.Buffer{}
+		for i := 0; i < 100; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			mac := result.String()
+			if _, err := net.ParseMAC(mac); err != nil {
+				t.Errorf("%s is not a valid MAC address: %s", mac, err)
+			}
+			found := false
+			for _, oui := range ouis {
+				if strings.HasPrefix(mac, oui+":") {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("%s does not start with an OUI of %s, one of %v", mac, vendor, ouis)
+			}
+			result.Reset()
+		}
+	}
+}
+
+func TestSentenceLengths(t *testing.T) {
+	cs, err := BuildCallstack("{sentence:min:3|max:6}|{sentence:min:1|max:30|distribution:normal|mean:25|stddev:10|end:!?}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	lengths := make(map[int]int)
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "|")
+		for j, bounds := range [][2]int{{3, 6}, {1, 30}} {
+			words := strings.Fields(p[j])
+			if len(words) < bounds[0] || len(words) > bounds[1] {
+				t.Errorf("%s has %d words, outside of %d to %d", p[j], len(words), bounds[0], bounds[1])
+			}
+			if p[j][0] < 'A' || p[j][0] > 'Z' {
+				t.Errorf("%s does not start with a capital letter", p[j])
+			}
+			if j == 0 {
+				lengths[len(words)]++
+			}
+		}
+		if !strings.HasSuffix(p[0], ".") || !strings.ContainsAny(p[1][len(p[1])-1:], "!?") {
+			t.Errorf("Expected each sentence to end with it's punctuation, got %s", result.String())
+		}
+		result.Reset()
+	}
+	// Every length between the bounds should come up
+	if len(lengths) != 4 {
+		t.Errorf("Expected sentences of 3 to 6 words, got %v", lengths)
+	}
+}
+
+func TestGroupThousands(t *testing.T) {
+	for digits, want := range map[string]string{
+		"0":          "0",
+		"999":        "999",
+		"1000":       "1.000",
+		"123456":     "123.456",
+		"1234567":    "1.234.567",
+		"9876543210": "9.876.543.210",
+	} {
+		if got := groupThousands(digits, "."); got != want {
+			t.Errorf("Expected %s to be grouped as %s, got %s", digits, want, got)
+		}
+	}
+}
+
+func TestCallingCodeMatchesCountry(t *testing.T) {
+	codes := make(map[string]string)
+	for _, c := range data.Countries {
+		codes[c.Alpha2] = c.CallingCode
+	}
+	cs, err := BuildCallstack("{country:as:c|format:iso2|weight:uniform} {callingcode:ref:c} {callingcode:ordinal:0|format:digits}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 500; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), " ")
+		want := codes[p[0]]
+		if want == "" {
+			// Entries without a calling code are left blank
+			if p[1] != "" || p[2] != "" {
+				t.Errorf("Expected %s to have no calling code, got %s and %s", p[0], p[1], p[2])
+			}
+		} else if p[1] != "+"+want || p[2] != want {
+			t.Errorf("Expected the calling code of %s to be +%s, got %s and %s", p[0], want, p[1], p[2])
+		}
+		result.Reset()
+	}
+}
+
+func TestJWTSegments(t *testing.T) {
+	cs, err := BuildCallstack("{jwt:alg:ES384|payload:100}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 100; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		segments := strings.Split(result.String(), ".")
+		if len(segments) != 3 {
+			t.Fatalf("Expected 3 segments in %s, found %d", result.String(), len(segments))
+		}
+		header, err := base64.RawURLEncoding.DecodeString(segments[0])
+		if err != nil {
+			t.Fatalf("Header %s is not base64url: %s", segments[0], err)
+		}
+		if string(header) != `{"alg":"ES384","typ":"JWT"}` {
+			t.Errorf("Unexpected header %s", header)
+		}
+		for j, size := range []int{100, 96} {
+			b, err := base64.RawURLEncoding.DecodeString(segments[j+1])
+			if err != nil {
+				t.Fatalf("Segment %s is not base64url: %s", segments[j+1], err)
+			}
+			if len(b) != size {
+				t.Errorf("Expected %d bytes in segment %d, found %d", size, j+1, len(b))
+			}
+		}
+		result.Reset()
+	}
+}
+
+func TestFoldLatin(t *testing.T) {
+	for text, want := range map[string]string{
+		"Zoë":                 "Zoe",
+		"Ærøskøbing Straße":   "AEroskobing Strasse",
+		"Ștefan Łukasz Çelik": "Stefan Lukasz Celik",
+		"Москва":              "Москва",
+		"plain ascii, 123":    "plain ascii, 123",
+	} {
+		if got := foldLatin(text); got != want {
+			t.Errorf("Expected %s to fold to %s, got %s", text, want, got)
+		}
+	}
+}
+
+func TestComposeFrom(t *testing.T) {
+	cs, err := BuildCallstack("{firstname:as:f}|{slug:from:f}|{word:as:w}|{hashtag:from:w}|{hashtag:ordinal:0}|{lorem:words:4|as:l}|{hashtag:from:l}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 100; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "|")
+		if slug := strings.ToLower(strings.Join(textWords(foldLatin(p[0])), "-")); p[1] != slug {
+			t.Errorf("Expected the slug of %s to be %s, found %s", p[0], slug, p[1])
+		}
+		if p[3] != "#"+p[2] {
+			t.Errorf("Expected the hashtag of %s to be #%s, found %s", p[2], p[2], p[3])
+		}
+		if p[4] != p[3] {
+			t.Errorf("Expected the hashtag at ordinal 0 to be %s, found %s", p[3], p[4])
+		}
+		words := strings.Fields(p[5])
+		tag := "#" + strings.ToLower(words[0])
+		for _, w := range words[1:] {
+			tag += strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+		}
+		if p[6] != tag {
+			t.Errorf("Expected the hashtag of %s to be %s, found %s", p[5], tag, p[6])
+		}
+		result.Reset()
+	}
+}
+
+// everyToken is a template segment for each token, with a variety of options, so that
+// they can all be exercised together. maybeEmpty marks the segments which can be blank.
+var everyToken = []struct {
+	tpl        string
+	maybeEmpty bool
+}{
+	{tpl: "{guid}"},
+	{tpl: "{guid:ordinal:0|format:braced}"},
+	{tpl: "{now:format:isoweek}"},
+	{tpl: "{time:min:0|max:1000000|format:epochmillis}"},
+	{tpl: "{int:min:-5|max:5|as:n}"},
+	{tpl: "{float:distribution:normal|mean:10|stddev:2|precision:3}"},
+	{tpl: "{ascii:length:12|case:up}"},
+	{tpl: "{unicode:length:4|ranges:greek}"},
+	{tpl: "{country:format:name|as:c}"},
+	{tpl: "{firstname:language:french|as:f}"},
+	{tpl: "{lastname:language:spanish}"},
+	{tpl: "{currency}"},
+	{tpl: "{weekday:language:german}"},
+	{tpl: "{month}"},
+	{tpl: "{streetaddress:as:home}"},
+	{tpl: "{city:ref:home}"},
+	{tpl: "{state:ref:home}"},
+	{tpl: "{zipcode:ref:home}"},
+	{tpl: "{company}"},
+	{tpl: "{jobtitle}"},
+	{tpl: "{username}"},
+	{tpl: "{password:length:16}"},
+	{tpl: "{age}"},
+	{tpl: "{objectid}"},
+	{tpl: "{semver}"},
+	{tpl: "{httpstatus}"},
+	{tpl: "{httpmethod}"},
+	{tpl: "{duration}"},
+	{tpl: "{latlng:as:p}"},
+	{tpl: "{timezone:ref:p}"},
+	{tpl: "{slug:from:f}"},
+	{tpl: "{filename}"},
+	{tpl: "{emoji}"},
+	{tpl: "{iban}"},
+	{tpl: "{isbn}"},
+	{tpl: "{repeat:count:3|sep:,|tpl:{int:min:1|max:9}}"},
+	{tpl: "{expr:value:n*2+1}"},
+	{tpl: "{creditcard:as:cc}"},
+	{tpl: "{cardtype:ref:cc}"},
+	{tpl: "{gender}"},
+	{tpl: "{port}"},
+	{tpl: "{word:as:w}"},
+	{tpl: "{hashtag:from:w}"},
+	{tpl: "{hashtag:count:2|words:2}"},
+	{tpl: "{nanoid}"},
+	{tpl: "{continent:ref:c}", maybeEmpty: true},
+	{tpl: "{rownum}"},
+	{tpl: "{bool:as:b}"},
+	{tpl: "{maybe:ref:b|then:yes}", maybeEmpty: true},
+	{tpl: "{ssn}"},
+	{tpl: "{lorem:unit:sentence|count:2}"},
+	{tpl: "{vin}"},
+	{tpl: "{licenseplate}"},
+	{tpl: "{timezone}"},
+	{tpl: "{status:set:payment}"},
+	{tpl: "{bloodtype}"},
+	{tpl: "{gitsha:short:true}"},
+	{tpl: "{base36}"},
+	{tpl: "{base62:length:12}"},
+	{tpl: "{imei:tac:realistic}"},
+	{tpl: "{hostname:style:role|domain:example.com}"},
+	{tpl: "{jwt:payload:16}"},
+	{tpl: "{fraction:denominator:8|min:0.25}"},
+	{tpl: "{percentage:symbol:true}"},
+	{tpl: "{ean13}"},
+	{tpl: "{upc:company:036000}"},
+	{tpl: "{latency:p50:40|p99:900|precision:1}"},
+	{tpl: "{measure:unit:fahrenheit|in:celsius|min:-10|max:40|symbol:true}"},
+	{tpl: "{macaddr:vendor:raspberrypi|case:up}"},
+	{tpl: "{sentence:min:3|max:9|distribution:normal|end:.?!}"},
+	{tpl: "{money:code:EUR|locale:fr-FR|min:-100000|max:100000}"},
+	{tpl: "{callingcode:format:idd}"},
+	{tpl: "{int:min:1|max:9|nullprob:0.5|nullvalue:|prefix:<|suffix:>}", maybeEmpty: true},
+	{tpl: "{lorem:words:20|maxlength:10}"},
+}
+
+// everyTokenSep separates the segments, and is not written by any token
+const everyTokenSep = "\x1f"
+
+func buildEveryToken() (*Callstack, error) {
+	tpls := make([]string, len(everyToken))
+	for i, s := range everyToken {
+		tpls[i] = s.tpl
+	}
+	cs, err := BuildCallstack(strings.Join(tpls, everyTokenSep))
+	if err != nil {
+		return nil, err
+	}
+	return cs, cs.AddRanges("greek", [][]int{{0x3b1, 0x3ca}})
+}
+
+func TestEveryTokenIsCovered(t *testing.T) {
+	cs, err := buildEveryToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	used := make(map[string]bool)
+	for _, tok := range cs.tokens {
+		used[tok.name] = true
+	}
+	for name := range defaultOptions {
+		if !used[name] {
+			t.Errorf("%s is not in the template of every token", name)
+		}
+	}
+}
+
+// TestEveryToken renders the template of every token many times over, in several
+// goroutines at once, so that run with -race it finds state shared between callstacks
+func TestEveryToken(t *testing.T) {
+	const workers = 4
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		go func(seed int64) {
+			errs <- renderEveryToken(seed, 250)
+		}(int64(w))
+	}
+	for w := 0; w < workers; w++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func renderEveryToken(seed int64, rows int) error {
+	cs, err := buildEveryToken()
+	if err != nil {
+		return err
+	}
+	cs.Seed(seed)
+	result := &bytes.Buffer{}
+	for i := 0; i < rows; i++ {
+		if err := cs.Write(result); err != nil {
+			return fmt.Errorf("seed %d, row %d: %s", seed, i, err)
+		}
+		segments := strings.Split(result.String(), everyTokenSep)
+		if len(segments) != len(everyToken) {
+			return fmt.Errorf("seed %d, row %d: expected %d segments, found %d in %q", seed, i, len(everyToken), len(segments), result.String())
+		}
+		for j, s := range segments {
+			if s == "" && !everyToken[j].maybeEmpty {
+				return fmt.Errorf("seed %d, row %d: %s wrote nothing", seed, i, everyToken[j].tpl)
+			}
+		}
+		result.Reset()
+	}
+	return nil
+}
+
+func BenchmarkEveryToken(b *testing.B) {
+	cs, err := buildEveryToken()
+	if err != nil {
+		b.Fatal(err)
 	}
+	cs.Seed(1)
+	result := &bytes.Buffer{}
 	b.ResetTimer()
 	for n := 0; n < b.N; n++ {
+		if err := cs.Write(result); err != nil {
+			b.Fatal(err)
+		}
+		result.Reset()
+	}
+}
+
+func TestNullRate(t *testing.T) {
+	tpl := "{int},{firstname},{float},{country},{bool},{rownum:nullprob:0}"
+	cs, err := BuildCallstack(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.SetNullRate(1.5, ""); err == nil {
+		t.Error("Expected an error for a null rate above 1")
+	}
+	if err := cs.SetNullRate(0.2, `\N`); err != nil {
+		t.Fatal(err)
+	}
+	cs.Seed(42)
+	result := &bytes.Buffer{}
+	var lines []string
+	nulls, values := 0, 0
+	for i := 0; i < 2000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, result.String())
+		p := strings.Split(result.String(), ",")
+		for _, v := range p[:5] {
+			if v == `\N` {
+				nulls++
+			}
+			values++
+		}
+		if p[5] != strconv.Itoa(i+1) {
+			t.Errorf("Expected the row number which opted out to be %d, got %s", i+1, p[5])
+		}
+		result.Reset()
+	}
+	if rate := float64(nulls) / float64(values); math.Abs(rate-0.2) > 0.03 {
+		t.Errorf("Expected around 20%% of the values to be null, got %.1f%%", rate*100)
+	}
+
+	// The same seed nulls out the same values
+	again, err := BuildCallstack(tpl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again.SetNullRate(0.2, `\N`)
+	again.Seed(42)
+	for i := 0; i < 100; i++ {
+		if err := again.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		if result.String() != lines[i] {
+			t.Fatalf("Expected row %d to repeat under the same seed, got %s and %s", i, lines[i], result.String())
+		}
+		result.Reset()
+	}
+}
+
+func TestSeededTokens(t *testing.T) {
+	texts := make(map[string]string)
+	var fixed string
+	// The same seed writes the same value, whatever else is in the template, and whatever
+	// the Callstack was seeded with
+	for n, tpl := range []string{
+		"{int:min:1|max:5|as:id}|{lorem:unit:sentence|seedref:id}|{float:seed:fixed}",
+		"{float}|{lorem}|{firstname}|{int:min:1|max:5|as:id}|{lorem:unit:sentence|seedref:id}|{float:seed:fixed}",
+	} {
+		cs, err := BuildCallstack(tpl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs.Seed(int64(n))
 		result := &bytes.Buffer{}
-		err = cs.Write(result)
+		for i := 0; i < 100; i++ {
+			if err := cs.Write(result); err != nil {
+				t.Fatal(err)
+			}
+			p := strings.Split(result.String(), "|")
+			p = p[len(p)-3:]
+			if text, ok := texts[p[0]]; ok && text != p[1] {
+				t.Errorf("Expected the text for %s to always be %s, got %s", p[0], text, p[1])
+			}
+			texts[p[0]] = p[1]
+			if fixed == "" {
+				fixed = p[2]
+			} else if p[2] != fixed {
+				t.Errorf("Expected the float seeded with fixed to always be %s, got %s", fixed, p[2])
+			}
+			result.Reset()
+		}
+	}
+	// Different seeds still write different values
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		seen[text] = true
+	}
+	if len(seen) != len(texts) {
+		t.Errorf("Expected each id to have it's own text, got %v", texts)
+	}
+
+	for _, tpl := range []string{
+		"{lorem:seedref:id}",
+		"{guid:as:id} {lorem:seed:a|seedref:id}",
+		"{country:as:c} {lorem:seedref:c}",
+	} {
+		cs, err := BuildCallstack(tpl)
 		if err != nil {
-			b.Error(err)
+			t.Fatal(err)
+		}
+		if err := cs.Write(&bytes.Buffer{}); err == nil {
+			t.Errorf("Expected an error writing %s", tpl)
+		}
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	week := strings.Repeat("{weekday:distinct:true} ", 6) + "{weekday:distinct:true}"
+	cs, err := BuildCallstack(week + "|{int:min:1|max:3|distinct:true}{int:min:1|max:3|distinct:true}{int:min:1|max:3|distinct:true}{int:ordinal:0|distinct:true}|{int:min:1|max:3}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 200; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), "|")
+		days := make(map[string]bool)
+		for _, day := range strings.Split(p[0], " ") {
+			days[day] = true
+		}
+		if len(days) != 7 {
+			t.Errorf("Expected every day of the week once, got %s", p[0])
+		}
+		// The reference repeats the first int, and the int without distinct can match any
+		if ints := p[1]; len(ints) != 4 || ints[0] == ints[1] || ints[0] == ints[2] || ints[1] == ints[2] || ints[3] != ints[0] {
+			t.Errorf("Expected 3 distinct ints and a reference to the first, got %s", ints)
+		}
+		result.Reset()
+	}
+
+	// There are only 7 days to choose from
+	if cs, err = BuildCallstack(week + " {weekday:distinct:true}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cs.Write(result); err == nil || !strings.Contains(err.Error(), "distinct:") {
+		t.Errorf("Expected an error for an eighth distinct day, got %v", err)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	cs, err := BuildCallstack("{username:unique:true},{int:min:1|max:100|unique:true},{int:ordinal:0}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usernames := make(map[string]bool)
+	ints := make(map[string]bool)
+	result := &bytes.Buffer{}
+	for i := 0; i < 50; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatalf("Row %d: %s", i, err)
+		}
+		p := strings.Split(result.String(), ",")
+		if usernames[p[0]] {
+			t.Errorf("Row %d repeated the username %s", i, p[0])
+		}
+		if ints[p[1]] {
+			t.Errorf("Row %d repeated the int %s", i, p[1])
+		}
+		// The values thrown away don't stay in the cache
+		if p[2] != p[1] {
+			t.Errorf("Row %d expected the int at ordinal 0 to be %s, found %s", i, p[1], p[2])
+		}
+		usernames[p[0]] = true
+		ints[p[1]] = true
+		result.Reset()
+	}
+
+	// Once every int from 1 to 5 has been used, there are none left
+	if cs, err = BuildCallstack("{int:min:1|max:5|unique:true}"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatalf("Row %d: %s", i, err)
+		}
+		result.Reset()
+	}
+	if err := cs.Write(result); err == nil {
+		t.Errorf("Expected an error once every value had been used, found %s", result.String())
+	}
+}
+
+func TestFractionRange(t *testing.T) {
+	cs, err := BuildCallstack("{fraction:denominator:7|min:0.2|max:0.6}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		found[result.String()] = true
+		result.Reset()
+	}
+	// 2/7 through 4/7 are the only sevenths from 0.2 to 0.6
+	if len(found) != 3 || !found["2/7"] || !found["3/7"] || !found["4/7"] {
+		t.Errorf("Expected only 2/7, 3/7, and 4/7, found %v", found)
+	}
+}
+
+func TestOrdinalErrors(t *testing.T) {
+	for template, expected := range map[string]string{
+		"{int}@{guid:ordinal:0}":                     "Ordinal 0 has not yet been encountered for {guid}: no {guid} has been generated yet in this line. Ordinals count the values of each type of token separately, so they can only refer back to another {guid}",
+		"{guid} {guid:ordinal:1}":                    "Ordinal 1 has not yet been encountered for {guid}: only 1 {guid} has been generated so far in this line, at ordinal 0",
+		"{int} {int} {float} {int:ordinal:2}":        "Ordinal 2 has not yet been encountered for {int}: only 2 {int} have been generated so far in this line, at ordinals 0 to 1",
+		"{firstname} {username:lastname:0}":          "Ordinal 0 has not yet been encountered for {lastname}: no {lastname} has been generated yet in this line",
+		"{int:as:x} {expr:value:$1}":                 "Ordinal 1 has not yet been encountered for {int}: only 1 {int} has been generated so far in this line, at ordinal 0",
+		"{city} {streetaddress:ordinal:0}":           "Ordinal 0 has not yet been encountered for {streetaddress}: no {streetaddress} has been generated yet in this line",
+		"{repeat:count:2|tpl:{int}} {int:ordinal:0}": "Ordinal 0 has not yet been encountered for {int}: no {int} has been generated yet in this line",
+	} {
+		cs, err := BuildCallstack(template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = cs.Write(&bytes.Buffer{})
+		if err == nil {
+			t.Errorf("Expected an error for %s", template)
+		} else if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error for %s to say %q, got %q", template, expected, err.Error())
+		}
+	}
+}
+
+func TestPercentageRange(t *testing.T) {
+	cs, err := BuildCallstack("{percentage:min:10|max:20|precision:6}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		n := mustParseFloat(result.String())
+		if n < 10 || n > 20 {
+			t.Errorf("Expected a percentage from 10 to 20, got %s", result.String())
+		}
+		result.Reset()
+	}
+}
+
+func TestBarcodeCheckDigits(t *testing.T) {
+	cs, err := BuildCallstack("{ean13} {upc} {isbn}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), " ")
+		if len(p[0]) != 13 || !passesGS1(p[0]) {
+			t.Errorf("%s is not a valid EAN-13", p[0])
+		}
+		if len(p[1]) != 12 || !passesGS1(p[1]) {
+			t.Errorf("%s is not a valid UPC-A", p[1])
+		}
+		// A UPC-A is an EAN-13 with a leading 0
+		if !passesGS1("0" + p[1]) {
+			t.Errorf("%s is not a valid EAN-13 with a leading 0", p[1])
 		}
+		if isbn := strings.Replace(p[2], "-", "", -1); !passesGS1(isbn) {
+			t.Errorf("%s is not a valid EAN-13", isbn)
+		}
+		result.Reset()
+	}
+}
+
+// passesGS1 returns whether the barcode's check digit is right, where the sum of every
+// digit, with those in even positions from the right tripled, is a multiple of 10
+func passesGS1(barcode string) bool {
+	sum := 0
+	for i := range barcode {
+		d := int(barcode[len(barcode)-1-i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func TestLatencyPercentiles(t *testing.T) {
+	cs, err := BuildCallstack("{latency:p50:80|p99:2000|precision:3}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs.Seed(11)
+	const samples = 20000
+	values := make([]float64, samples)
+	result := &bytes.Buffer{}
+	for i := range values {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		values[i] = mustParseFloat(result.String())
+		if values[i] <= 0 {
+			t.Fatalf("Expected a latency greater than zero, got %s", result.String())
+		}
+		result.Reset()
+	}
+	sort.Float64s(values)
+	// The tail is sparse, so the 99th percentile is given more room than the median
+	for _, p := range []struct {
+		name      string
+		value     float64
+		expected  float64
+		tolerance float64
+	}{
+		{"p50", values[samples/2], 80, 0.05},
+		{"p99", values[samples*99/100], 2000, 0.15},
+	} {
+		if math.Abs(p.value-p.expected) > p.expected*p.tolerance {
+			t.Errorf("Expected the %s to be about %v, got %v", p.name, p.expected, p.value)
+		}
+	}
+}
+
+func TestMeasureConvertsRange(t *testing.T) {
+	cs, err := BuildCallstack("{measure:unit:fahrenheit|in:celsius|min:-10|max:40|precision:6},{measure:ordinal:0|unit:celsius|precision:6}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := &bytes.Buffer{}
+	for i := 0; i < 1000; i++ {
+		if err := cs.Write(result); err != nil {
+			t.Fatal(err)
+		}
+		p := strings.Split(result.String(), ",")
+		f, c := mustParseFloat(p[0]), mustParseFloat(p[1])
+		// -10°C to 40°C is 14°F to 104°F
+		if f < 14-1e-6 || f > 104+1e-6 {
+			t.Errorf("Expected a temperature from 14°F to 104°F, got %s", p[0])
+		}
+		if math.Abs(c*9/5+32-f) > 1e-5 {
+			t.Errorf("Expected %s°C to be %s°F", p[1], p[0])
+		}
+		result.Reset()
+	}
+}
+
+func TestUsernameFallback(t *testing.T) {
+	oc := newObjectCache()
+	// A name with no ascii letters in it leaves nothing to build the username from
+	oc["firstname"] = []string{"Иван"}
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	opts := cmdOptions{"ordinal": "-1", "style": "flast", "digits": "0", "maxlength": "0", "firstname": "0", "lastname": "-1"}
+	result, err := username(rnd, oc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result, "u") {
+		t.Errorf("Expected the username to fall back to user for the first name, got %s", result)
+	}
+	name, err := usernamePart(rnd, "firstname", data.FirstNames, oc, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "user" {
+		t.Errorf("Expected the first name to fall back to user, got %s", name)
 	}
 }
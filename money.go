@@ -0,0 +1,122 @@
+package moldova
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+// amount is an amount of money in a currency, kept as a number so that a reference can
+// write it for another locale
+type amount struct {
+	value    float64
+	currency *Currency
+}
+
+func money(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	code := strings.ToUpper(opts["code"])
+	cur, ok := Currencies[code]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("code: %s is not a known currency code", code))
+	}
+	format, err := getMoneyFormat(opts["locale"])
+	if err != nil {
+		return "", err
+	}
+	min, err := opts.getFloat("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getFloat("max")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["money"]
+		cache := c.([]*amount)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "money", len(cache))
+		}
+		return formatMoney(cache[ord], format), nil
+	}
+
+	if min > max {
+		return "", InvalidArgumentError("You cannot generate a random amount whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+	// Round to the minor unit of the currency, the same as {currency}
+	unit := math.Pow10(cur.Decimals)
+	a := &amount{math.Floor((min+rnd.Float64()*(max-min))*unit+0.5) / unit, cur}
+
+	// store it in the cache
+	ca := oc["money"]
+	cache := ca.([]*amount)
+	oc["money"] = append(cache, a)
+
+	return formatMoney(a, format), nil
+}
+
+// getMoneyFormat looks up the locale, which can be written with an underscore or in any
+// case, as in "de_de"
+func getMoneyFormat(locale string) (*MoneyFormat, error) {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 2 {
+		if format, ok := MoneyFormats[strings.ToLower(parts[0])+"-"+strings.ToUpper(parts[1])]; ok {
+			return format, nil
+		}
+	}
+	return nil, InvalidArgumentError(fmt.Sprintf("locale: %s is not a known locale. Use one of en-US, en-GB, ja-JP, de-DE, es-ES, it-IT, nl-NL, pt-BR, de-CH, or fr-FR", locale))
+}
+
+// formatMoney writes the amount with the digits grouped into thousands, and the symbol
+// of it's currency on the side the locale puts it
+func formatMoney(a *amount, format *MoneyFormat) string {
+	sign := ""
+	n := a.value
+	if n < 0 {
+		sign = "-"
+		n = -n
+	}
+	digits := strconv.FormatFloat(n, 'f', a.currency.Decimals, 64)
+	whole, fraction := digits, ""
+	if i := strings.IndexByte(digits, '.'); i >= 0 {
+		whole, fraction = digits[:i], format.Decimal+digits[i+1:]
+	}
+	number := groupThousands(whole, format.Group) + fraction
+
+	// Some symbols, such as "CHF ", already hold the space they're written with
+	symbol := strings.TrimSpace(a.currency.Symbol)
+	space := ""
+	if format.Space || symbol != a.currency.Symbol {
+		space = " "
+	}
+	if format.SymbolAfter {
+		return sign + number + space + symbol
+	}
+	return sign + symbol + space + number
+}
+
+// groupThousands places sep between each group of three digits, counting from the right
+func groupThousands(digits string, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	head := len(digits) % 3
+	if head == 0 {
+		head = 3
+	}
+	groups := []string{digits[:head]}
+	for i := head; i < len(digits); i += 3 {
+		groups = append(groups, digits[i:i+3])
+	}
+	return strings.Join(groups, sep)
+}
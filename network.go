@@ -0,0 +1,246 @@
+package moldova
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+// portPresets are the ranges of ports set aside by IANA for each purpose
+var portPresets = map[string][2]int{
+	"any":        {1, 65535},
+	"privileged": {1, 1023},
+	"registered": {1024, 49151},
+	"ephemeral":  {49152, 65535},
+}
+
+func port(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	min, err := opts.getInt("min")
+	if err != nil {
+		return "", err
+	}
+	max, err := opts.getInt("max")
+	if err != nil {
+		return "", err
+	}
+	preset, ok := portPresets[opts["preset"]]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("preset: %s is not one of any, privileged, registered, or ephemeral. Please check your input string", opts["preset"]))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["port"]
+		cache := c.([]int)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "port", len(cache))
+		}
+		return strconv.Itoa(cache[ord]), nil
+	}
+
+	if min < 1 || max > 65535 {
+		return "", InvalidArgumentError("You cannot generate a port outside of the range 1 to 65535. Please check your input string")
+	}
+	// The preset narrows down the range given by min and max
+	if min < preset[0] {
+		min = preset[0]
+	}
+	if max > preset[1] {
+		max = preset[1]
+	}
+	if min > max {
+		return "", InvalidArgumentError("You cannot generate a port whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+	p, err := steppedInteger(rnd, min, max, 1)
+	if err != nil {
+		return "", err
+	}
+
+	// store it in the cache
+	ca := oc["port"]
+	cache := ca.([]int)
+	oc["port"] = append(cache, p)
+
+	return strconv.Itoa(p), nil
+}
+
+func hostname(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	style := opts["style"]
+	if style != "words" && style != "role" {
+		return "", InvalidArgumentError(fmt.Sprintf("style: %s is not one of words or role. Please check your input string", style))
+	}
+	domain := strings.ToLower(opts["domain"])
+	if domain != "" && !validHostname(domain) {
+		return "", InvalidArgumentError(fmt.Sprintf("domain: %s is not a valid domain name. Please check your input string", opts["domain"]))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["hostname"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "hostname", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	var host string
+	if style == "role" {
+		host = fmt.Sprintf("%s%02d", HostRoles[rnd.Intn(len(HostRoles))], 1+rnd.Intn(99))
+	} else {
+		// Two words and a number, like the names given to containers and cloud instances
+		host = fmt.Sprintf("%s-%s-%d", hostWord(rnd), hostWord(rnd), rnd.Intn(100))
+	}
+	if domain != "" {
+		host += "." + domain
+	}
+	// A long domain can leave no room for the host in front of it
+	if !validHostname(host) {
+		return "", InvalidArgumentError(fmt.Sprintf("domain: %s is too long to put a host name in front of. Please check your input string", opts["domain"]))
+	}
+
+	// store it in the cache
+	ca := oc["hostname"]
+	cache := ca.([]string)
+	oc["hostname"] = append(cache, host)
+
+	return host, nil
+}
+
+// hostWord picks a word of at least 3 letters for a host name, as the shorter ones make
+// for names which are hard to read
+func hostWord(rnd *rand.Rand) string {
+	// Words are sorted by length, so skip past the short ones at the start
+	first := sort.Search(len(Words), func(i int) bool { return len(Words[i]) >= 3 })
+	return Words[first+rnd.Intn(len(Words)-first)]
+}
+
+// validHostname returns whether name is a valid host name, following RFC 1123. It can be
+// at most 253 characters, made of labels separated by dots. Each label is 1 to 63 letters,
+// digits, or hyphens, and can't start or end with a hyphen.
+func validHostname(name string) bool {
+	if len(name) == 0 || len(name) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && !(c >= '0' && c <= '9') && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// z99 is how many standard deviations above the mean the 99th percentile of a normal
+// distribution is
+const z99 = 2.3263478740408408
+
+func latency(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	prec, err := opts.getInt("precision")
+	if err != nil {
+		return "", err
+	} else if prec < 0 {
+		return "", InvalidArgumentError("You have specified a precision which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["latency"]
+		cache := c.([]float64)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "latency", len(cache))
+		}
+		return strconv.FormatFloat(cache[ord], 'f', prec, 64), nil
+	}
+
+	p50, err := opts.getFloat("p50")
+	if err != nil {
+		return "", err
+	}
+	p99, err := opts.getFloat("p99")
+	if err != nil {
+		return "", err
+	}
+	if p50 <= 0 || p99 < p50 {
+		return "", InvalidArgumentError("You have specified a p50 and p99 which are not numbers greater than zero, with the p50 no greater than the p99. Please check your input string")
+	}
+
+	// Latency is log-normal: most requests are close to the median, but there's a long
+	// tail of slow ones. The log of the latency is normal, with the median at the mean,
+	// and the 99th percentile z99 standard deviations above it.
+	mu := math.Log(p50)
+	sigma := (math.Log(p99) - mu) / z99
+	n := math.Exp(normalFloat(rnd, mu, sigma))
+
+	// store it in the cache
+	ca := oc["latency"]
+	cache := ca.([]float64)
+	oc["latency"] = append(cache, n)
+
+	return strconv.FormatFloat(n, 'f', prec, 64), nil
+}
+
+func macaddr(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	if cCase != "down" && cCase != "up" {
+		return "", InvalidArgumentError(fmt.Sprintf("case: %s is not one of down or up. Please check your input string", cCase))
+	}
+	vendor := strings.ToLower(opts["vendor"])
+	ouis, ok := VendorOUIs[vendor]
+	if vendor != "" && !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("vendor: %s is not a known vendor. Use one of apple, cisco, dell, intel, raspberrypi, virtualbox, vmware, or xen", opts["vendor"]))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["macaddr"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "macaddr", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	octets := make([]byte, 6)
+	rnd.Read(octets)
+	var mac string
+	if vendor != "" {
+		// Only the last three octets are up to the vendor to hand out
+		mac = ouis[rnd.Intn(len(ouis))] + fmt.Sprintf(":%02x:%02x:%02x", octets[3], octets[4], octets[5])
+	} else {
+		// Clear the lowest bit of the first octet, as an address with it set is for
+		// multicast, and can't belong to a single interface
+		octets[0] &^= 1
+		mac = fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", octets[0], octets[1], octets[2], octets[3], octets[4], octets[5])
+	}
+
+	// store it in the cache
+	ca := oc["macaddr"]
+	cache := ca.([]string)
+	oc["macaddr"] = append(cache, mac)
+
+	return applyCase(mac, cCase), nil
+}
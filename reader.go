@@ -0,0 +1,41 @@
+package moldova
+
+import (
+	"bytes"
+	"io"
+)
+
+// Reader returns an io.Reader which writes the Callstack n times, one line at a time, as
+// it is read from. Each line ends with a newline. Lines are only written once the reader
+// has run out of what it holds, so large amounts of data can be streamed into code which
+// expects an io.Reader without ever being held in memory at once.
+//
+// If writing a line fails, the error is returned by Read, and the reader should not be
+// used any further.
+func Reader(cs *Callstack, n int) io.Reader {
+	return &callstackReader{cs: cs, remaining: n}
+}
+
+type callstackReader struct {
+	cs        *Callstack
+	remaining int
+	buf       bytes.Buffer
+}
+
+func (r *callstackReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	for r.buf.Len() == 0 {
+		if r.remaining <= 0 {
+			return 0, io.EOF
+		}
+		if err := r.cs.Write(&r.buf); err != nil {
+			r.buf.Reset()
+			return 0, err
+		}
+		r.buf.WriteByte('\n')
+		r.remaining--
+	}
+	return r.buf.Read(p)
+}
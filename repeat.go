@@ -0,0 +1,66 @@
+package moldova
+
+import (
+	"bytes"
+	"math/rand"
+)
+
+// repeat writes out the template in the tpl option count times, joined by the separator.
+// Each repetition is written separately, so the values in each are independent of one
+// another, and ordinals inside the template only refer to values in the same repetition.
+func repeat(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	sep := opts["sep"]
+	count, err := opts.getInt("count")
+	if err != nil {
+		return "", err
+	} else if count < 0 {
+		return "", InvalidArgumentError("You have specified a count which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["repeat"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "repeat", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	// The nested template was built along with the token
+	sub := oc[nestedKey].(*Callstack)
+	// Share the random source, so a seeded Callstack stays reproducible
+	sub.rand = rnd
+	// Each line numbers it's repetitions from 1
+	sub.rows = 0
+	result := &bytes.Buffer{}
+	var errs WriteErrors
+	for i := 0; i < count; i++ {
+		if i > 0 {
+			result.WriteString(sep)
+		}
+		if err := sub.writeNested(result, oc); err != nil {
+			collected, ok := err.(WriteErrors)
+			if !ok {
+				return "", err
+			}
+			errs = append(errs, collected...)
+		}
+	}
+	if len(errs) > 0 {
+		// When errors are collected, the placeholders are kept, and the errors are
+		// returned along with those of the template around this one
+		nested, _ := oc[nestedErrorsKey].(WriteErrors)
+		oc[nestedErrorsKey] = append(nested, errs...)
+	}
+
+	// store it in the cache
+	ca := oc["repeat"]
+	cache := ca.([]string)
+	oc["repeat"] = append(cache, result.String())
+
+	return result.String(), nil
+}
@@ -0,0 +1,60 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+)
+
+// The labels a prerelease version can be given
+var prereleaseLabels = []string{"alpha", "beta", "rc"}
+
+func semver(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	prerelease, err := opts.getBool("prerelease")
+	if err != nil {
+		return "", err
+	}
+	build, err := opts.getBool("build")
+	if err != nil {
+		return "", err
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["semver"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "semver", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	// Each component is picked from 0 up to and including it's maximum
+	parts := make([]string, 0, 3)
+	for _, option := range []string{"maxmajor", "maxminor", "maxpatch"} {
+		max, err := opts.getInt(option)
+		if err != nil {
+			return "", err
+		} else if max < 0 {
+			return "", InvalidArgumentError(fmt.Sprintf("%s: You have specified a maximum which is not a number greater than or equal to zero. Please check your input string", option))
+		}
+		parts = append(parts, strconv.Itoa(rnd.Intn(max+1)))
+	}
+	result := parts[0] + "." + parts[1] + "." + parts[2]
+	if prerelease {
+		result += "-" + prereleaseLabels[rnd.Intn(len(prereleaseLabels))] + "." + strconv.Itoa(rnd.Intn(9)+1)
+	}
+	if build {
+		result += "+build" + strconv.Itoa(rnd.Intn(1000))
+	}
+
+	// store it in the cache
+	ca := oc["semver"]
+	cache := ca.([]string)
+	oc["semver"] = append(cache, result)
+
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package moldova
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func status(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["status"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "status", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	set, ok := StatusSets[strings.ToLower(opts["set"])]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("set: %s is not a known set of statuses. Use one of %s", opts["set"], strings.Join(statusSets(), ", ")))
+	}
+	weights := make([]float64, len(set))
+	for i, v := range set {
+		weights[i] = v.Weight
+	}
+	result := set[weightedIndex(rnd, weights)].Value
+
+	// store it in the cache
+	ca := oc["status"]
+	cache := ca.([]string)
+	oc["status"] = append(cache, result)
+
+	return applyCase(result, cCase), nil
+}
+
+// statusSets returns the names of the sets of statuses, in order
+func statusSets() []string {
+	sets := make([]string, 0, len(StatusSets))
+	for s := range StatusSets {
+		sets = append(sets, s)
+	}
+	sort.Strings(sets)
+	return sets
+}
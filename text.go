@@ -0,0 +1,511 @@
+package moldova
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	// The {unicode} token's function already has the package's name
+	stdunicode "unicode"
+	"unicode/utf8"
+
+	// See the note in moldova.go about why this is a dot import
+	. "github.com/StabbyCutyou/moldova/data"
+)
+
+func slug(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	words, err := opts.getInt("words")
+	if err != nil {
+		return "", err
+	} else if words <= 0 {
+		return "", InvalidArgumentError("You have specified a number of words which is not a number greater than zero. Please check your input string")
+	}
+	maxLength, err := opts.getInt("maxlength")
+	if err != nil {
+		return "", err
+	} else if maxLength < 0 {
+		return "", InvalidArgumentError("You have specified a maximum length which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["slug"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "slug", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	var result string
+	if from := opts["from"]; from != "" {
+		text, err := oc.getNamedText("from", from)
+		if err != nil {
+			return "", err
+		}
+		// Keep to what's safe in a URL, folding accented letters to the ones they're
+		// built on, and dropping letters from scripts other than Latin
+		words := strings.FieldsFunc(strings.ToLower(foldLatin(text)), func(r rune) bool {
+			return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+		})
+		if result = strings.Join(words, "-"); result == "" {
+			return "", InvalidArgumentError(fmt.Sprintf("from: %s has no ascii letters or digits to make a slug from. Please check your input string", from))
+		}
+	} else {
+		result = strings.Join(loremWords(rnd, words), "-")
+	}
+	if maxLength > 0 && runeLength(result) > maxLength {
+		// Don't leave a dangling hyphen where a word was cut off
+		result = strings.TrimRight(truncateRunes(result, maxLength), "-")
+	}
+
+	// store it in the cache
+	ca := oc["slug"]
+	cache := ca.([]string)
+	oc["slug"] = append(cache, result)
+
+	return result, nil
+}
+
+// latinFolds are the plain letters each accented Latin letter is built on, or written as
+// in ascii, such as "ss" for "ß"
+var latinFolds = map[string]string{
+	"a": "àáâãäåāăąǎ", "A": "ÀÁÂÃÄÅĀĂĄǍ", "ae": "æ", "AE": "Æ",
+	"c": "çćĉċč", "C": "ÇĆĈĊČ", "d": "ďđð", "D": "ĎĐÐ",
+	"e": "èéêëēĕėęě", "E": "ÈÉÊËĒĔĖĘĚ", "g": "ĝğġģ", "G": "ĜĞĠĢ",
+	"h": "ĥħ", "H": "ĤĦ", "i": "ìíîïĩīĭįıǐ", "I": "ÌÍÎÏĨĪĬĮİǏ",
+	"j": "ĵ", "J": "Ĵ", "k": "ķ", "K": "Ķ", "l": "ĺļľŀł", "L": "ĹĻĽĿŁ",
+	"n": "ñńņňŉ", "N": "ÑŃŅŇ", "o": "òóôõöøōŏőǒ", "O": "ÒÓÔÕÖØŌŎŐǑ", "oe": "œ", "OE": "Œ",
+	"r": "ŕŗř", "R": "ŔŖŘ", "s": "śŝşšș", "S": "ŚŜŞŠȘ", "ss": "ß",
+	"t": "ţťŧț", "T": "ŢŤŦȚ", "th": "þ", "TH": "Þ",
+	"u": "ùúûüũūŭůűųǔ", "U": "ÙÚÛÜŨŪŬŮŰŲǓ", "w": "ŵ", "W": "Ŵ",
+	"y": "ýÿŷ", "Y": "ÝŶŸ", "z": "źżž", "Z": "ŹŻŽ",
+}
+
+// latinFolder replaces each accented letter in latinFolds with it's plain letters
+var latinFolder = func() *strings.Replacer {
+	var pairs []string
+	for plain, accented := range latinFolds {
+		for _, r := range accented {
+			pairs = append(pairs, string(r), plain)
+		}
+	}
+	return strings.NewReplacer(pairs...)
+}()
+
+// foldLatin replaces the accented Latin letters in text with the plain letters they're
+// built on, such as "Zoe" for "Zoë". Letters from other scripts are left as they are.
+func foldLatin(text string) string {
+	return latinFolder.Replace(text)
+}
+
+// textWords splits text into the runs of letters and digits in it, dropping everything
+// else, so that it can be rebuilt as a slug or a hashtag
+func textWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !stdunicode.IsLetter(r) && !stdunicode.IsDigit(r)
+	})
+}
+
+func filename(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	words, err := opts.getInt("words")
+	if err != nil {
+		return "", err
+	} else if words <= 0 {
+		return "", InvalidArgumentError("You have specified a number of words which is not a number greater than zero. Please check your input string")
+	}
+	dirs, err := opts.getInt("path")
+	if err != nil {
+		return "", err
+	} else if dirs < 0 {
+		return "", InvalidArgumentError("You have specified a number of directories which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["filename"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "filename", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	exts := strings.Split(opts["ext"], ",")
+	ext := strings.TrimPrefix(strings.TrimSpace(exts[rnd.Intn(len(exts))]), ".")
+	if ext == "" {
+		return "", InvalidArgumentError(fmt.Sprintf("ext: %s contains an empty extension. Please check your input string", opts["ext"]))
+	}
+	// The case only applies to the words, the extension is left as it was asked for
+	parts := loremWords(rnd, dirs)
+	parts = append(parts, strings.Join(loremWords(rnd, words), "_"))
+	for i := range parts {
+		parts[i] = applyCase(parts[i], cCase)
+	}
+	result := strings.Join(parts, "/") + "." + ext
+
+	// store it in the cache
+	ca := oc["filename"]
+	cache := ca.([]string)
+	oc["filename"] = append(cache, result)
+
+	return result, nil
+}
+
+func emoji(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	category := opts["category"]
+	count, err := opts.getInt("count")
+	if err != nil {
+		return "", err
+	} else if count <= 0 {
+		return "", InvalidArgumentError("You have specified a count which is not a number greater than zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["emoji"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "emoji", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	var ranges [][]int
+	if category == "any" {
+		for _, c := range EmojiCategories {
+			ranges = append(ranges, EmojiRanges[c]...)
+		}
+	} else if r, ok := EmojiRanges[category]; ok {
+		ranges = r
+	} else {
+		return "", InvalidArgumentError(fmt.Sprintf("category: %s is not a known emoji category. Use one of any, %s", category, strings.Join(EmojiCategories, ", ")))
+	}
+	runes := make([]rune, count)
+	for i := range runes {
+		// First, pick which range this emoji comes from
+		r := ranges[rnd.Intn(len(ranges))]
+		runes[i] = rune(r[0] + rnd.Intn(r[1]-r[0]+1))
+	}
+	result := string(runes)
+
+	// store it in the cache
+	ca := oc["emoji"]
+	cache := ca.([]string)
+	oc["emoji"] = append(cache, result)
+
+	return result, nil
+}
+
+// loremWords returns n random words from the lorem ipsum text
+func loremWords(rnd *rand.Rand, n int) []string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = LoremWords[rnd.Intn(len(LoremWords))]
+	}
+	return words
+}
+
+func dictionaryWord(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	minLength, err := opts.getInt("minlen")
+	if err != nil {
+		return "", err
+	} else if minLength < 0 {
+		return "", InvalidArgumentError("You have specified a minimum length which is not a number greater than or equal to zero. Please check your input string")
+	}
+	maxLength, err := opts.getInt("maxlen")
+	if err != nil {
+		return "", err
+	} else if maxLength < 0 {
+		return "", InvalidArgumentError("You have specified a maximum length which is not a number greater than or equal to zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["word"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "word", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	// Words are sorted by length, so the ones which fit are all next to each other
+	first, last := 0, len(Words)
+	for first < last && runeLength(Words[first]) < minLength {
+		first++
+	}
+	for maxLength > 0 && last > first && runeLength(Words[last-1]) > maxLength {
+		last--
+	}
+	if first == last {
+		return "", InvalidArgumentError(fmt.Sprintf("There are no words between %d and %d letters long. Please check your input string", minLength, maxLength))
+	}
+	result := Words[first+rnd.Intn(last-first)]
+
+	// store it in the cache
+	ca := oc["word"]
+	cache := ca.([]string)
+	oc["word"] = append(cache, result)
+
+	return applyCase(result, cCase), nil
+}
+
+func hashtag(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	count, err := opts.getInt("count")
+	if err != nil {
+		return "", err
+	} else if count <= 0 {
+		return "", InvalidArgumentError("You have specified a count which is not a number greater than zero. Please check your input string")
+	}
+	words, err := opts.getInt("words")
+	if err != nil {
+		return "", err
+	} else if words <= 0 {
+		return "", InvalidArgumentError("You have specified a number of words which is not a number greater than zero. Please check your input string")
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["hashtag"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "hashtag", len(cache))
+		}
+		return cache[ord], nil
+	}
+
+	if from := opts["from"]; from != "" {
+		text, err := oc.getNamedText("from", from)
+		if err != nil {
+			return "", err
+		}
+		words := textWords(text)
+		if len(words) == 0 {
+			return "", InvalidArgumentError(fmt.Sprintf("from: %s has no letters or digits to make a hashtag from. Please check your input string", from))
+		}
+		tag := "#" + strings.ToLower(words[0])
+		for _, w := range words[1:] {
+			_, size := utf8.DecodeRuneInString(w)
+			tag += strings.ToUpper(w[:size]) + strings.ToLower(w[size:])
+		}
+		ca := oc["hashtag"]
+		cache := ca.([]string)
+		oc["hashtag"] = append(cache, tag)
+		return tag, nil
+	}
+
+	tags := make([]string, count)
+	for i := range tags {
+		// Words after the first are capitalized, to make a camelCase tag
+		tag := []byte{'#'}
+		for j := 0; j < words; j++ {
+			w := Words[rnd.Intn(len(Words))]
+			if j > 0 {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+			tag = append(tag, w...)
+		}
+		tags[i] = string(tag)
+	}
+	result := strings.Join(tags, " ")
+
+	// store it in the cache
+	ca := oc["hashtag"]
+	cache := ca.([]string)
+	oc["hashtag"] = append(cache, result)
+
+	return result, nil
+}
+
+func lorem(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	wrap := opts["wrap"]
+	if wrap != "" && wrap != "html" && wrap != "markdown" {
+		return "", InvalidArgumentError(fmt.Sprintf("wrap: %s is not a known markup. Use either html or markdown", wrap))
+	}
+	count, err := opts.getInt("words")
+	if err != nil {
+		return "", err
+	} else if count <= 0 {
+		return "", InvalidArgumentError("You have specified a number of words which is not a number greater than zero. Please check your input string")
+	}
+	units, err := opts.getInt("count")
+	if err != nil {
+		return "", err
+	} else if units <= 0 {
+		return "", InvalidArgumentError("You have specified a count which is not a number greater than zero. Please check your input string")
+	}
+	sentences, err := opts.getInt("sentences")
+	if err != nil {
+		return "", err
+	} else if sentences <= 0 {
+		return "", InvalidArgumentError("You have specified a number of sentences which is not a number greater than zero. Please check your input string")
+	}
+	words, ok := LoremLanguages[strings.ToLower(opts["language"])]
+	if !ok {
+		return "", InvalidArgumentError(fmt.Sprintf("language: There is no lorem text for %s. Use one of latin, french, german, spanish, russian, or greek", opts["language"]))
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["lorem"]
+		cache := c.([][]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "lorem", len(cache))
+		}
+		return formatLorem(cache[ord], cCase, wrap), nil
+	}
+
+	sentence := func() string {
+		text := make([]string, count)
+		for i := range text {
+			text[i] = words[rnd.Intn(len(words))]
+		}
+		return strings.Join(text, " ")
+	}
+	// Each paragraph is kept apart, so they can be wrapped on their own
+	var paragraphs []string
+	switch opts["unit"] {
+	case "word":
+		paragraphs = []string{sentence()}
+	case "sentence":
+		paragraphs = []string{loremSentences(sentence, units)}
+	case "paragraph":
+		paragraphs = make([]string, units)
+		for i := range paragraphs {
+			paragraphs[i] = loremSentences(sentence, sentences)
+		}
+	default:
+		return "", InvalidArgumentError(fmt.Sprintf("unit: %s is not a known unit. Use one of word, sentence, or paragraph", opts["unit"]))
+	}
+
+	// store it in the cache
+	ca := oc["lorem"]
+	cache := ca.([][]string)
+	oc["lorem"] = append(cache, paragraphs)
+
+	return formatLorem(paragraphs, cCase, wrap), nil
+}
+
+func sentence(rnd *rand.Rand, oc objectCache, opts cmdOptions) (string, error) {
+	cCase := opts["case"]
+	min, err := opts.getInt("min")
+	if err != nil {
+		return "", err
+	} else if min <= 0 {
+		return "", InvalidArgumentError("You have specified a minimum number of words which is not a number greater than zero. Please check your input string")
+	}
+	max, err := opts.getInt("max")
+	if err != nil {
+		return "", err
+	} else if max < min {
+		return "", InvalidArgumentError("You cannot generate a sentence whose lower bound is greater than it's upper bound. Please check your input string")
+	}
+	normal, err := isNormal(opts)
+	if err != nil {
+		return "", err
+	}
+	end := []rune(opts["end"])
+	if len(end) == 0 {
+		return "", InvalidArgumentError("You have specified an empty end. Give at least one character to end the sentence with. Please check your input string")
+	}
+	words := Words
+	if language := strings.ToLower(opts["language"]); language != "english" {
+		var ok bool
+		if words, ok = LoremLanguages[language]; !ok {
+			return "", InvalidArgumentError(fmt.Sprintf("language: There are no words for %s. Use one of english, latin, french, german, spanish, russian, or greek", opts["language"]))
+		}
+	}
+	ord, err := opts.getInt("ordinal")
+	if err != nil {
+		return "", err
+	}
+
+	if ord >= 0 {
+		c := oc["sentence"]
+		cache := c.([]string)
+		if len(cache)-1 < ord {
+			return "", ordinalError(ord, "sentence", len(cache))
+		}
+		return applyCase(cache[ord], cCase), nil
+	}
+
+	var count int
+	if normal {
+		x, err := normalValue(rnd, opts, float64(min), float64(max))
+		if err != nil {
+			return "", err
+		}
+		count = int(math.Floor(x + 0.5))
+	} else {
+		count = min + rnd.Intn(max-min+1)
+	}
+	text := make([]string, count)
+	for i := range text {
+		text[i] = words[rnd.Intn(len(words))]
+	}
+	s := strings.Join(text, " ")
+	r, size := utf8.DecodeRuneInString(s)
+	result := strings.ToUpper(string(r)) + s[size:] + string(end[rnd.Intn(len(end))])
+
+	// store it in the cache
+	ca := oc["sentence"]
+	cache := ca.([]string)
+	oc["sentence"] = append(cache, result)
+
+	return applyCase(result, cCase), nil
+}
+
+// loremSentences writes n sentences, each with it's first letter capitalized and ending
+// in a period
+func loremSentences(sentence func() string, n int) string {
+	text := make([]string, n)
+	for i := range text {
+		s := sentence()
+		r, size := utf8.DecodeRuneInString(s)
+		text[i] = strings.ToUpper(string(r)) + s[size:] + "."
+	}
+	return strings.Join(text, " ")
+}
+
+// formatLorem changes the case of each paragraph, then wraps it in the markup asked for.
+// Paragraphs are kept on one line unless they are wrapped in markdown, which needs the
+// blank line between them to tell them apart.
+func formatLorem(paragraphs []string, cCase string, wrap string) string {
+	text := make([]string, len(paragraphs))
+	for i, p := range paragraphs {
+		p = applyCase(p, cCase)
+		if wrap == "html" {
+			p = "<p>" + p + "</p>"
+		}
+		text[i] = p
+	}
+	switch wrap {
+	case "html":
+		return strings.Join(text, "")
+	case "markdown":
+		return strings.Join(text, "\n\n")
+	}
+	return strings.Join(text, " ")
+}